@@ -9,9 +9,12 @@ import (
 	"os"
 	"os/signal"
 	"time"
+	// в образе alpine нет базы часовых поясов, а расписания комнат задаются IANA поясами
+	_ "time/tzdata"
 
 	"github.com/ynuraddi/test-kami/config"
 	"github.com/ynuraddi/test-kami/internal/application"
+	"github.com/ynuraddi/test-kami/internal/domain"
 	repository "github.com/ynuraddi/test-kami/internal/infrastructure/postgres"
 	"github.com/ynuraddi/test-kami/internal/transport"
 	httpserver "github.com/ynuraddi/test-kami/pkg/httpServer"
@@ -42,12 +45,43 @@ func main() {
 		panic(err)
 	}
 
-	txManager := repository.NewTxManager(psg)
+	txManager := repository.NewTxManager(psg, repository.RetryPolicy{
+		MaxAttempts: cfg.TxRetry.MaxAttempts,
+		BaseDelay:   cfg.TxRetry.BaseDelay,
+		MaxDelay:    cfg.TxRetry.MaxDelay,
+	})
 	repo := repository.NewReservations(psg)
+	roomRepo := repository.NewRooms(psg)
+	scheduleRepo := repository.NewSchedules(psg)
+	waitlistRepo := repository.NewWaitlist(psg)
+
+	var (
+		locker  application.RoomLocker
+		mutexes *application.MutexManager
+	)
+	if cfg.RoomLock == "postgres" {
+		locker = repository.NewAdvisoryLocker()
+	} else {
+		mutexes = application.NewMutexManager(time.Minute, time.Minute)
+		locker = mutexes
+	}
 
-	service := application.NewReservationService(repo, txManager)
+	policy := domain.BookingPolicies{
+		domain.NoPastBookings{},
+		domain.MinDuration(cfg.Policy.MinDuration),
+		domain.MaxDuration(cfg.Policy.MaxDuration),
+		domain.AdvanceWindow(cfg.Policy.AdvanceWindow),
+		domain.OwnerQuota(cfg.Policy.OwnerQuota),
+	}
 
-	handler := transport.NewRouter(service)
+	service := application.NewReservationService(repo, roomRepo, scheduleRepo, waitlistRepo, txManager, locker, application.NewLogPublisher(), policy)
+	roomService := application.NewRoomService(roomRepo, scheduleRepo)
+	scheduleService := application.NewScheduleService(scheduleRepo, txManager)
+	idempotency := application.NewIdempotencyManager(repository.NewIdempotencyKeys(psg), cfg.Idempotency.TTL, cfg.Idempotency.CleanupInterval, cfg.Idempotency.ClaimTimeout)
+
+	holdReaper := application.NewHoldReaper(service, cfg.Holds.ReapInterval)
+
+	handler := transport.NewRouter(service, roomService, scheduleService, idempotency)
 	server := httpserver.New(handler, cfg.HTTP.PORT)
 
 	gracefullShutdown(func() {
@@ -55,6 +89,11 @@ func main() {
 			log.Println("shutdown server error:", err.Error())
 			return
 		}
+		if mutexes != nil {
+			mutexes.Close()
+		}
+		idempotency.Close()
+		holdReaper.Close()
 		psg.Close()
 	})
 
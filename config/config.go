@@ -1,6 +1,10 @@
 package config
 
-import "github.com/ilyakaznacheev/cleanenv"
+import (
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
 
 type Config struct {
 	Postgres struct {
@@ -8,6 +12,37 @@ type Config struct {
 		MigrationURL string `yaml:"migration_url" env:"PG_MIGRATION_URL" env-default:"file://migrations"`
 	} `yaml:"postgres"`
 
+	// повтор транзакции при serialization failure (40001) и deadlock (40P01)
+	TxRetry struct {
+		MaxAttempts int           `yaml:"max_attempts" env:"TX_RETRY_MAX_ATTEMPTS" env-default:"3"`
+		BaseDelay   time.Duration `yaml:"base_delay" env:"TX_RETRY_BASE_DELAY" env-default:"10ms"`
+		MaxDelay    time.Duration `yaml:"max_delay" env:"TX_RETRY_MAX_DELAY" env-default:"200ms"`
+	} `yaml:"tx_retry"`
+
+	// memory - блокировка комнат в рамках процесса, postgres - общая для всех реплик
+	RoomLock string `yaml:"room_lock" env:"ROOM_LOCK" env-default:"memory"`
+
+	// сколько хранится ответ на запрос с Idempotency-Key и как часто удаляются старые ключи.
+	// Ключ без ответа дольше claim_timeout брошен, значение должно быть больше таймаута запроса
+	Idempotency struct {
+		TTL             time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+		CleanupInterval time.Duration `yaml:"cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"1h"`
+		ClaimTimeout    time.Duration `yaml:"claim_timeout" env:"IDEMPOTENCY_CLAIM_TIMEOUT" env-default:"30s"`
+	} `yaml:"idempotency"`
+
+	// как часто удаляются истекшие временные брони
+	Holds struct {
+		ReapInterval time.Duration `yaml:"reap_interval" env:"HOLD_REAP_INTERVAL" env-default:"1m"`
+	} `yaml:"holds"`
+
+	// правила бронирования, 0 - без ограничения. Бронирования в прошлом запрещены всегда
+	Policy struct {
+		MinDuration   time.Duration `yaml:"min_duration" env:"POLICY_MIN_DURATION" env-default:"5m"`
+		MaxDuration   time.Duration `yaml:"max_duration" env:"POLICY_MAX_DURATION" env-default:"12h"`
+		AdvanceWindow time.Duration `yaml:"advance_window" env:"POLICY_ADVANCE_WINDOW" env-default:"8760h"`
+		OwnerQuota    int           `yaml:"owner_quota" env:"POLICY_OWNER_QUOTA" env-default:"20"`
+	} `yaml:"policy"`
+
 	HTTP struct {
 		PORT string `yaml:"port" env:"PORT" env-default:"8080"`
 	} `yaml:"http"`
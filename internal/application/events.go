@@ -0,0 +1,27 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+// LogPublisher пишет события в лог одной строкой JSON.
+// Подходит, пока событиями никто не подписан, для брокера нужна своя реализация EventPublisher
+type LogPublisher struct{}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+func (LogPublisher) Publish(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	log.Println("event", event.EventName(), string(body))
+	return nil
+}
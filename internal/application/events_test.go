@@ -0,0 +1,27 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+func Test_LogPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	out := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(out)
+
+	err := NewLogPublisher().Publish(context.Background(), domain.WaitlistPromotedEvent{
+		Entry:       domain.WaitlistEntry{ID: 7, RoomID: "room", Status: domain.WaitlistPromoted, ReservationID: 3},
+		Reservation: domain.Reservation{ID: 3, RoomID: "room"},
+		CancelledID: 1,
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "event waitlist.promoted")
+	assert.Contains(t, buf.String(), `"CancelledID":1`)
+}
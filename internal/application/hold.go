@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// HoldReaper периодически удаляет истекшие held брони.
+// Для новых бронирований слот освобождается и без него (FindOverlapping не видит истекшие брони),
+// но лист ожидания продвигается только reaper, как после отмены
+type HoldReaper struct {
+	service  *reservationService
+	interval time.Duration
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewHoldReaper(service *reservationService, interval time.Duration) *HoldReaper {
+	hr := &HoldReaper{
+		service:  service,
+		interval: interval,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go hr.startReapRoutine()
+	return hr
+}
+
+// Close останавливает reaper и дожидается его завершения. Повторный вызов безопасен
+func (hr *HoldReaper) Close() {
+	hr.closeOnce.Do(func() {
+		close(hr.done)
+	})
+	<-hr.stopped
+}
+
+func (hr *HoldReaper) startReapRoutine() {
+	defer close(hr.stopped)
+
+	ticker := time.NewTicker(hr.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			hr.reap()
+		case <-hr.done:
+			return
+		}
+	}
+}
+
+func (hr *HoldReaper) reap() {
+	ctx, cancel := context.WithTimeout(context.Background(), hr.interval)
+	defer cancel()
+
+	if _, err := hr.service.ReapExpiredHolds(ctx); err != nil {
+		log.Println("reap expired holds:", err.Error())
+	}
+}
@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_domain "github.com/ynuraddi/test-kami/internal/domain/mock"
+)
+
+func Test_HoldReaper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+
+	service := NewReservationService(repo, nil, nil, nil, nil, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	reaped := make(chan struct{}, 1)
+
+	// ошибка очистки не останавливает reaper
+	first := repo.EXPECT().ListExpiredHoldRooms(gomock.Any()).Return(nil, errors.New("unexpected error")).Times(1)
+	repo.EXPECT().ListExpiredHoldRooms(gomock.Any()).DoAndReturn(
+		func(ctx context.Context) ([]domain.RoomID, error) {
+			select {
+			case reaped <- struct{}{}:
+			default:
+			}
+			return nil, nil
+		},
+	).MinTimes(1).After(first)
+
+	hr := NewHoldReaper(service, 10*time.Millisecond)
+
+	select {
+	case <-reaped:
+	case <-time.After(time.Second):
+		t.Fatal("expired holds were not reaped")
+	}
+
+	hr.Close()
+	hr.Close() // повторный вызов безопасен
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+// IdempotencyManager хранит ответы на запросы с Idempotency-Key,
+// чтобы повтор клиента после таймаута не создавал бронирование второй раз.
+// Ключи старше ttl удаляются фоновой очисткой, ключ без ответа старше claimTimeout
+// считается брошенным (процесс упал, не освободив его) и достается повтору запроса
+type IdempotencyManager struct {
+	repo            domain.IdempotencyRepository
+	ttl             time.Duration
+	cleanupInterval time.Duration
+	claimTimeout    time.Duration
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewIdempotencyManager(repo domain.IdempotencyRepository, ttl, cleanupInterval, claimTimeout time.Duration) *IdempotencyManager {
+	im := &IdempotencyManager{
+		repo:            repo,
+		ttl:             ttl,
+		cleanupInterval: cleanupInterval,
+		claimTimeout:    claimTimeout,
+		done:            make(chan struct{}),
+		stopped:         make(chan struct{}),
+	}
+	go im.startCleanupRoutine()
+	return im
+}
+
+// Begin занимает ключ за запросом. Если ключ новый - возвращает nil и запрос нужно выполнить,
+// затем вызвать Complete или Release. Если по ключу уже есть ответ на тот же запрос - возвращает его
+func (im *IdempotencyManager) Begin(ctx context.Context, key, requestHash string) (*domain.IdempotentResponse, error) {
+	k, err := domain.NewIdempotencyKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	record, claimed, err := im.repo.Claim(ctx, k, requestHash, im.claimTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if record.Response == nil {
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+	return record.Response, nil
+}
+
+// Complete сохраняет ответ для повторов с тем же ключом
+func (im *IdempotencyManager) Complete(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	k, err := domain.NewIdempotencyKey(key)
+	if err != nil {
+		return err
+	}
+	return im.repo.SaveResponse(ctx, k, response)
+}
+
+// Release освобождает ключ без ответа, например после 5xx, чтобы клиент мог повторить запрос
+func (im *IdempotencyManager) Release(ctx context.Context, key string) error {
+	k, err := domain.NewIdempotencyKey(key)
+	if err != nil {
+		return err
+	}
+	return im.repo.Delete(ctx, k)
+}
+
+// Close останавливает фоновую очистку и дожидается ее завершения,
+// чтобы после него можно было закрыть пул подключений. Повторный вызов безопасен
+func (im *IdempotencyManager) Close() {
+	im.closeOnce.Do(func() {
+		close(im.done)
+	})
+	<-im.stopped
+}
+
+func (im *IdempotencyManager) startCleanupRoutine() {
+	defer close(im.stopped)
+
+	ticker := time.NewTicker(im.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			im.cleanupKeys()
+		case <-im.done:
+			return
+		}
+	}
+}
+
+func (im *IdempotencyManager) cleanupKeys() {
+	ctx, cancel := context.WithTimeout(context.Background(), im.cleanupInterval)
+	defer cancel()
+
+	if _, err := im.repo.DeleteExpired(ctx, time.Now().UTC().Add(-im.ttl)); err != nil {
+		log.Println("cleanup idempotency keys:", err.Error())
+	}
+}
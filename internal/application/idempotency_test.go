@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_domain "github.com/ynuraddi/test-kami/internal/domain/mock"
+)
+
+func Test_IdempotencyManager_Begin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockIdempotencyRepository(ctrl)
+
+	im := NewIdempotencyManager(repo, time.Hour, time.Hour, time.Minute)
+	defer im.Close()
+
+	unexpectedError := errors.New("unexpected error")
+
+	key := "key"
+	hash := "hash"
+
+	response := &domain.IdempotentResponse{
+		StatusCode: 201,
+		Location:   "/api/v1/reservations/1",
+		Body:       []byte(`{"id":1}`),
+	}
+
+	testCases := []struct {
+		name        string
+		key         string
+		buildStubs  func()
+		checkResult func(t *testing.T, response *domain.IdempotentResponse, err error)
+	}{
+		{
+			name: "OK new key",
+			key:  key,
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Eq(domain.IdempotencyKey(key)), gomock.Eq(hash), gomock.Eq(time.Minute)).
+					Return(domain.IdempotencyRecord{Key: domain.IdempotencyKey(key), RequestHash: hash}, true, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, replay)
+			},
+		},
+		{
+			name: "OK replay same request",
+			key:  key,
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.IdempotencyRecord{Key: domain.IdempotencyKey(key), RequestHash: hash, Response: response}, false, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, response, replay)
+			},
+		},
+		{
+			name: "NOT OK key reused with another request",
+			key:  key,
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.IdempotencyRecord{Key: domain.IdempotencyKey(key), RequestHash: "other", Response: response}, false, nil).Times(1) // note
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+				assert.Nil(t, replay)
+			},
+		},
+		{
+			name: "NOT OK key reused with another request in progress",
+			key:  key,
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.IdempotencyRecord{Key: domain.IdempotencyKey(key), RequestHash: "other"}, false, nil).Times(1) // note
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+				assert.Nil(t, replay)
+			},
+		},
+		{
+			name: "NOT OK same request in progress",
+			key:  key,
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.IdempotencyRecord{Key: domain.IdempotencyKey(key), RequestHash: hash}, false, nil).Times(1) // note
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInProgress)
+				assert.Nil(t, replay)
+			},
+		},
+		{
+			name: "NOT OK invalid key",
+			key:  "some key", // note
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, replay)
+			},
+		},
+		{
+			name: "NOT OK error from Claim",
+			key:  key,
+			buildStubs: func() {
+				repo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.IdempotencyRecord{}, false, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, replay *domain.IdempotentResponse, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, replay)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			replay, err := im.Begin(context.Background(), tc.key, hash)
+			tc.checkResult(t, replay, err)
+		})
+	}
+}
+
+func Test_IdempotencyManager_CompleteRelease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockIdempotencyRepository(ctrl)
+
+	im := NewIdempotencyManager(repo, time.Hour, time.Hour, time.Minute)
+	defer im.Close()
+
+	response := domain.IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":1}`)}
+
+	repo.EXPECT().SaveResponse(gomock.Any(), gomock.Eq(domain.IdempotencyKey("key")), gomock.Eq(response)).Return(nil).Times(1)
+	assert.NoError(t, im.Complete(context.Background(), "key", response))
+
+	repo.EXPECT().Delete(gomock.Any(), gomock.Eq(domain.IdempotencyKey("key"))).Return(nil).Times(1)
+	assert.NoError(t, im.Release(context.Background(), "key"))
+
+	assert.ErrorIs(t, im.Complete(context.Background(), "", response), internal.ErrValidationFailed)
+	assert.ErrorIs(t, im.Release(context.Background(), ""), internal.ErrValidationFailed)
+}
+
+func Test_IdempotencyManager_Cleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockIdempotencyRepository(ctrl)
+
+	ttl := time.Hour
+	cleaned := make(chan time.Time, 1)
+
+	repo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, createdBefore time.Time) (int64, error) {
+			select {
+			case cleaned <- createdBefore:
+			default:
+			}
+			return 0, nil
+		},
+	).MinTimes(1)
+
+	im := NewIdempotencyManager(repo, ttl, 10*time.Millisecond, time.Minute)
+
+	select {
+	case createdBefore := <-cleaned:
+		// удаляются ключи старше ttl
+		assert.WithinDuration(t, time.Now().UTC().Add(-ttl), createdBefore, time.Second)
+	case <-time.After(time.Second):
+		t.Fatal("cleanup was not called")
+	}
+
+	im.Close()
+	im.Close() // повторный вызов безопасен
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	pgx "github.com/jackc/pgx/v5"
+	domain "github.com/ynuraddi/test-kami/internal/domain"
 )
 
 // MockTransaction is a mock of Transaction interface.
@@ -48,3 +49,92 @@ func (mr *MockTransactionMockRecorder) Execute(ctx, f, options interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockTransaction)(nil).Execute), ctx, f, options)
 }
+
+// MockRoomLocker is a mock of RoomLocker interface.
+type MockRoomLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomLockerMockRecorder
+}
+
+// MockRoomLockerMockRecorder is the mock recorder for MockRoomLocker.
+type MockRoomLockerMockRecorder struct {
+	mock *MockRoomLocker
+}
+
+// NewMockRoomLocker creates a new mock instance.
+func NewMockRoomLocker(ctrl *gomock.Controller) *MockRoomLocker {
+	mock := &MockRoomLocker{ctrl: ctrl}
+	mock.recorder = &MockRoomLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomLocker) EXPECT() *MockRoomLockerMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method.
+func (m *MockRoomLocker) Lock(ctx context.Context, roomID domain.RoomID) (func(), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, roomID)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockRoomLockerMockRecorder) Lock(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockRoomLocker)(nil).Lock), ctx, roomID)
+}
+
+// NeedsTx mocks base method.
+func (m *MockRoomLocker) NeedsTx() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsTx")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsTx indicates an expected call of NeedsTx.
+func (mr *MockRoomLockerMockRecorder) NeedsTx() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsTx", reflect.TypeOf((*MockRoomLocker)(nil).NeedsTx))
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(ctx context.Context, event domain.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), ctx, event)
+}
@@ -1,8 +1,13 @@
 package application
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
 )
 
 type MutexManager struct {
@@ -10,11 +15,34 @@ type MutexManager struct {
 	mutexesLock     sync.Mutex
 	cleanupInterval time.Duration
 	expiration      time.Duration
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// roomMutex мьютекс на канале, чтобы ожидание можно было прервать контекстом
+type roomMutex chan struct{}
+
+func (m roomMutex) lock(ctx context.Context) error {
+	select {
+	case m <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m roomMutex) unlock() {
+	<-m
 }
 
 type mutexWithTime struct {
-	mu       *sync.Mutex
+	mu       roomMutex
 	lastUsed time.Time
+	// сколько горутин держат или ждут мьютекс,
+	// пока больше нуля запись нельзя удалять, иначе следующий Lock
+	// создаст новый мьютекс и два запроса к комнате пойдут параллельно
+	refs int
 }
 
 func NewMutexManager(cleanupInterval, expiration time.Duration) *MutexManager {
@@ -22,34 +50,76 @@ func NewMutexManager(cleanupInterval, expiration time.Duration) *MutexManager {
 		mutexes:         make(map[string]*mutexWithTime, 100),
 		cleanupInterval: cleanupInterval,
 		expiration:      expiration,
+		done:            make(chan struct{}),
 	}
 	go mm.startCleanupRoutine()
 	return mm
 }
 
-func (mm *MutexManager) GetMutex(roomID string) *sync.Mutex {
+// acquire отдает мьютекс комнаты, увеличивая счетчик ссылок
+func (mm *MutexManager) acquire(roomID string) *mutexWithTime {
 	mm.mutexesLock.Lock()
 	defer mm.mutexesLock.Unlock()
 
-	if roomMutex, ok := mm.mutexes[roomID]; ok {
-		roomMutex.lastUsed = time.Now()
-		return roomMutex.mu
+	m, ok := mm.mutexes[roomID]
+	if !ok {
+		m = &mutexWithTime{mu: make(roomMutex, 1)}
+		mm.mutexes[roomID] = m
 	}
+	m.refs++
+	m.lastUsed = time.Now()
+	return m
+}
+
+func (mm *MutexManager) release(m *mutexWithTime) {
+	mm.mutexesLock.Lock()
+	defer mm.mutexesLock.Unlock()
+
+	m.refs--
+	m.lastUsed = time.Now()
+}
 
-	m := &sync.Mutex{}
-	mm.mutexes[roomID] = &mutexWithTime{
-		mu:       m,
-		lastUsed: time.Now(),
+// Lock реализация RoomLocker в рамках одного процесса,
+// ожидание ограничено контекстом запроса
+func (mm *MutexManager) Lock(ctx context.Context, roomID domain.RoomID) (func(), error) {
+	m := mm.acquire(string(roomID))
+	if err := m.mu.lock(ctx); err != nil {
+		mm.release(m)
+		return nil, fmt.Errorf("%w: %w", internal.ErrLockTimeout, err)
 	}
-	return m
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.unlock()
+			mm.release(m)
+		})
+	}, nil
+}
+
+// NeedsTx мьютекс не зависит от транзакции и берется до нее
+func (mm *MutexManager) NeedsTx() bool {
+	return false
+}
+
+// Close останавливает фоновую очистку, повторный вызов безопасен
+func (mm *MutexManager) Close() {
+	mm.closeOnce.Do(func() {
+		close(mm.done)
+	})
 }
 
 func (mm *MutexManager) startCleanupRoutine() {
 	ticker := time.NewTicker(mm.cleanupInterval)
+	defer ticker.Stop()
 
 	for {
-		<-ticker.C
-		mm.cleanupMutexes()
+		select {
+		case <-ticker.C:
+			mm.cleanupMutexes()
+		case <-mm.done:
+			return
+		}
 	}
 }
 
@@ -59,7 +129,7 @@ func (mm *MutexManager) cleanupMutexes() {
 
 	now := time.Now()
 	for k, v := range mm.mutexes {
-		if now.Sub(v.lastUsed) > mm.expiration {
+		if v.refs == 0 && now.Sub(v.lastUsed) > mm.expiration {
 			delete(mm.mutexes, k)
 		}
 	}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+func Test_MutexManager_Lock(t *testing.T) {
+	mm := NewMutexManager(time.Minute, time.Minute)
+	defer mm.Close()
+	assert.False(t, mm.NeedsTx())
+
+	roomID := domain.RoomID("room")
+
+	unlock, err := mm.Lock(context.Background(), roomID)
+	assert.NoError(t, err)
+
+	t.Run("NOT OK context deadline while room is locked", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		u, err := mm.Lock(ctx, roomID)
+		assert.ErrorIs(t, err, internal.ErrLockTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, u)
+	})
+
+	t.Run("NOT OK context canceled while room is locked", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := mm.Lock(ctx, roomID)
+		assert.ErrorIs(t, err, internal.ErrLockTimeout)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("OK other room is not blocked", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		u, err := mm.Lock(ctx, "other")
+		assert.NoError(t, err)
+		u()
+	})
+
+	t.Run("OK waiter gets lock after unlock", func(t *testing.T) {
+		acquired := make(chan error)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			u, err := mm.Lock(ctx, roomID)
+			if err == nil {
+				u()
+			}
+			acquired <- err
+		}()
+
+		unlock()
+		assert.NoError(t, <-acquired)
+	})
+}
+
+func Test_MutexManager_EvictionKeepsHeldMutex(t *testing.T) {
+	// expiration 0 - любая неиспользуемая запись сразу подлежит удалению
+	mm := NewMutexManager(time.Hour, 0)
+	defer mm.Close()
+
+	roomID := domain.RoomID("room")
+
+	unlock, err := mm.Lock(context.Background(), roomID)
+	assert.NoError(t, err)
+
+	mm.cleanupMutexes()
+
+	// мьютекс все еще занят, новый Lock должен ждать его, а не создать свежий
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = mm.Lock(ctx, roomID)
+	assert.ErrorIs(t, err, internal.ErrLockTimeout)
+
+	unlock()
+	// повторный unlock не должен ломать счетчик ссылок
+	unlock()
+
+	time.Sleep(time.Millisecond)
+	mm.cleanupMutexes()
+
+	mm.mutexesLock.Lock()
+	assert.Empty(t, mm.mutexes)
+	mm.mutexesLock.Unlock()
+}
+
+// запускать с -race: make race
+func Test_MutexManager_Stress(t *testing.T) {
+	mm := NewMutexManager(time.Millisecond, 0)
+	defer mm.Close()
+
+	rooms := []domain.RoomID{"1", "2", "3"}
+
+	const (
+		workers    = 50
+		iterations = 200
+	)
+
+	inside := make(map[domain.RoomID]*int32, len(rooms))
+	for _, r := range rooms {
+		inside[r] = new(int32)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		violations int32
+	)
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < iterations; i++ {
+				roomID := rooms[(w+i)%len(rooms)]
+
+				unlock, err := mm.Lock(context.Background(), roomID)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				if atomic.AddInt32(inside[roomID], 1) != 1 {
+					atomic.AddInt32(&violations, 1)
+				}
+				// даем очистке шанс отработать, пока мьютекс занят
+				if i%10 == 0 {
+					time.Sleep(time.Millisecond)
+				} else {
+					runtime.Gosched()
+				}
+				atomic.AddInt32(inside[roomID], -1)
+
+				unlock()
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(0), violations)
+
+	// после всех запросов записи вычищаются
+	assert.Eventually(t, func() bool {
+		mm.mutexesLock.Lock()
+		defer mm.mutexesLock.Unlock()
+		return len(mm.mutexes) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func Test_MutexManager_Close(t *testing.T) {
+	mm := NewMutexManager(time.Millisecond, 0)
+
+	mm.Close()
+	// повторный вызов безопасен
+	mm.Close()
+
+	_, ok := <-mm.done
+	assert.False(t, ok)
+}
@@ -2,9 +2,14 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/ynuraddi/test-kami/internal"
 	"github.com/ynuraddi/test-kami/internal/domain"
 )
 
@@ -12,72 +17,825 @@ type Transaction interface {
 	Execute(ctx context.Context, f func(txCtx context.Context) error, options pgx.TxOptions) error
 }
 
+// RoomLocker сериализует операции над одной комнатой.
+// unlock вызывается только после завершения транзакции,
+// иначе следующий запрос может не увидеть закоммиченные изменения
+type RoomLocker interface {
+	Lock(ctx context.Context, roomID domain.RoomID) (unlock func(), err error)
+	// NeedsTx блокировка живет в транзакции (advisory lock), и Lock вызывается внутри
+	// Transaction.Execute с txCtx. Иначе Lock вызывается до Execute, чтобы запрос,
+	// который ждет занятую комнату, не держал соединение из пула
+	NeedsTx() bool
+}
+
+// EventPublisher сообщает о событиях сервиса наружу. Publish вызывается после commit,
+// ошибка публикации не отменяет уже выполненное изменение
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+// ReadCommitted, потому что конфликты исключает блокировка комнаты:
+// в RepeatableRead снимок берется на первом запросе транзакции, то есть до ожидания
+// advisory lock, и бронирование, закоммиченное пока мы ждали, было бы не видно
+var reserveTxOptions = pgx.TxOptions{
+	IsoLevel:       pgx.ReadCommitted,
+	AccessMode:     pgx.ReadWrite,
+	DeferrableMode: pgx.NotDeferrable,
+}
+
 type reservationService struct {
-	repo domain.ReservationRepository
-	tx   Transaction
+	repo      domain.ReservationRepository
+	rooms     domain.RoomRepository
+	schedules domain.ScheduleRepository
+	waitlist  domain.WaitlistRepository
+	tx        Transaction
+	events    EventPublisher
+	// policy nil - бронирования не ограничиваются
+	policy domain.BookingPolicy
 
 	// это такой оркестратор
 	// я собираюсь разделить транзакции по комнатам
 	// я подумал что в рамках текущей логики комнаты никак не конфликтуют между собой
 	// поэтому сериализовать можно операции по комнатам
 	//
-	// такое решение сложно не масштабируемое, потому что нужна согласованность между репликами
-	// в таком случае лучше использовать брокер сообщений
-	roomMutex *MutexManager
+	// MutexManager работает только в рамках одного процесса,
+	// для нескольких реплик используется advisory lock в postgres
+	locker RoomLocker
 }
 
-func NewReservationService(repo domain.ReservationRepository, tx Transaction) *reservationService {
+func NewReservationService(repo domain.ReservationRepository, rooms domain.RoomRepository, schedules domain.ScheduleRepository,
+	waitlist domain.WaitlistRepository, tx Transaction, locker RoomLocker, events EventPublisher, policy domain.BookingPolicy,
+) *reservationService {
 	return &reservationService{
-		repo: repo,
-		tx:   tx,
+		repo:      repo,
+		rooms:     rooms,
+		schedules: schedules,
+		waitlist:  waitlist,
+		tx:        tx,
+		events:    events,
+		policy:    policy,
+
+		locker: locker,
+	}
+}
+
+func (s reservationService) ReserveRoom(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails) (domain.Reservation, error) {
+	return s.reserve(ctx, roomID, from, to, details, func(txCtx context.Context, rid domain.RoomID, tr domain.TimeRange) (domain.Reservation, error) {
+		return s.repo.Create(txCtx, rid, tr, details)
+	}, nil)
+}
+
+// HoldRoom временно занимает слот на ttl, пока клиент не подтвердит бронь через ConfirmReservation.
+// До истечения hold конфликтует с другими бронированиями как обычное
+func (s reservationService) HoldRoom(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails, ttl time.Duration) (domain.Reservation, error) {
+	if err := domain.CheckHoldTTL(ttl); err != nil {
+		return domain.Reservation{}, err
+	}
+
+	return s.reserve(ctx, roomID, from, to, details, func(txCtx context.Context, rid domain.RoomID, tr domain.TimeRange) (domain.Reservation, error) {
+		return s.repo.CreateHold(txCtx, rid, tr, details, ttl)
+	}, nil)
+}
+
+// WaitlistRoom то же, что ReserveRoom, но при конфликте вместо отказа ставит запрос в лист ожидания.
+// Возвращается либо бронирование, либо запись листа ожидания (entry.ID > 0)
+func (s reservationService) WaitlistRoom(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails) (reservation domain.Reservation, entry domain.WaitlistEntry, err error) {
+	reservation, err = s.reserve(ctx, roomID, from, to, details, func(txCtx context.Context, rid domain.RoomID, tr domain.TimeRange) (domain.Reservation, error) {
+		// Execute может повторить транзакцию, запись листа ожидания прошлой попытки откатилась
+		entry = domain.WaitlistEntry{}
+		return s.repo.Create(txCtx, rid, tr, details)
+	}, func(txCtx context.Context, rid domain.RoomID, tr domain.TimeRange) error {
+		// в очередь под той же блокировкой комнаты, иначе отмена между проверкой
+		// и вставкой освободит слот, а запрос так и останется ждать
+		entry, err = s.waitlist.Create(txCtx, rid, tr, details)
+		return err
+	})
+	if err != nil {
+		return domain.Reservation{}, domain.WaitlistEntry{}, err
+	}
 
-		roomMutex: NewMutexManager(time.Minute, time.Minute),
+	return reservation, entry, nil
+}
+
+// GetWaitlistEntry запись листа ожидания, у продвинутой записи задан ReservationID
+func (s reservationService) GetWaitlistEntry(ctx context.Context, id int64) (domain.WaitlistEntry, error) {
+	if id <= 0 {
+		return domain.WaitlistEntry{},
+			fmt.Errorf("GetWaitlistEntry: ID should be positive number: %w", internal.ErrValidationFailed)
 	}
+
+	return s.waitlist.GetByID(ctx, id)
 }
 
-func (s reservationService) ReserveRoom(ctx context.Context, roomID string, from, to time.Time) (err error) {
+// reserve общие проверки ReserveRoom, HoldRoom и WaitlistRoom, create сохраняет бронирование.
+// Если onConflict задан, при конфликте вызывается он, и тогда бронирование не создается
+func (s reservationService) reserve(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails,
+	create func(txCtx context.Context, rid domain.RoomID, tr domain.TimeRange) (domain.Reservation, error),
+	onConflict func(txCtx context.Context, rid domain.RoomID, tr domain.TimeRange) error,
+) (reservation domain.Reservation, err error) {
 	rid, err := domain.NewRoomID(roomID)
 	if err != nil {
-		return err
+		return domain.Reservation{}, err
 	}
 	tr, err := domain.NewTimeRange(from, to)
 	if err != nil {
+		return domain.Reservation{}, err
+	}
+	if err := details.Validate(); err != nil {
+		return domain.Reservation{}, err
+	}
+
+	err = s.withRoomLock(ctx, rid, func(txCtx context.Context) error {
+		room, err := s.bookableRoom(txCtx, rid)
+		if err != nil {
+			return err
+		}
+		if err := room.CheckCapacity(details); err != nil {
+			return err
+		}
+		// под блокировкой комнаты, а квота еще и под блокировкой владельца, чтобы видеть его параллельные бронирования
+		if err := s.checkPolicy(txCtx, rid, details.Owner, tr, 0); err != nil {
+			return err
+		}
+		if err := s.checkBookableHours(txCtx, rid, tr, tr); err != nil {
+			return err
+		}
+
+		reservations, err := s.repo.FindOverlapping(txCtx, rid, room.Buffer.Widen(tr))
+		if err != nil {
+			return err
+		}
+
+		if err := checkConflicts(reservations, tr, room.Buffer, 0); err != nil {
+			if onConflict == nil {
+				return err
+			}
+			// Execute может повторить транзакцию, бронирование прошлой попытки не нужно
+			reservation = domain.Reservation{}
+			return onConflict(txCtx, rid, tr)
+		}
+
+		reservation, err = create(txCtx, rid, tr)
 		return err
+	})
+	if err != nil {
+		return domain.Reservation{}, err
 	}
 
-	mu := s.roomMutex.GetMutex(roomID)
-	mu.Lock()
-	defer mu.Unlock()
+	return reservation, nil
+}
 
-	return s.tx.Execute(ctx, func(txCtx context.Context) error {
-		reservations, err := s.ListByRoom(txCtx, roomID)
+// ReserveRecurring создает серию целиком или возвращает SeriesConflictError
+// со всеми вхождениями, которые пересеклись с существующими бронированиями
+// Details одни на все вхождения
+func (s reservationService) ReserveRecurring(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails, rule string) (series domain.ReservationSeries, err error) {
+	rid, err := domain.NewRoomID(roomID)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+	tr, err := domain.NewTimeRange(from, to)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+	if err := details.Validate(); err != nil {
+		return domain.ReservationSeries{}, err
+	}
+	rrule, err := domain.ParseRecurrenceRule(rule)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+	// серия повторяется по часам комнаты, а не по UTC
+	schedule, err := s.schedules.GetSchedule(ctx, rid)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+	occurrences, err := rrule.Expand(tr, schedule.Location)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+
+	err = s.withRoomLock(ctx, rid, func(txCtx context.Context) error {
+		room, err := s.bookableRoom(txCtx, rid)
 		if err != nil {
 			return err
 		}
+		if err := room.CheckCapacity(details); err != nil {
+			return err
+		}
+		// каждое вхождение - отдельное бронирование владельца и входит в его квоту
+		if err := s.checkPolicies(txCtx, rid, details.Owner, occurrences, 0); err != nil {
+			return err
+		}
 
-		// быстрее можно сделать если создать запрос на roomAndRange
-		for _, r := range reservations {
-			if r.TimeRange.CrossWith(tr) {
-				return domain.ReservationConflictError{
-					Reservation:         tr,
-					ConflictReservation: r.TimeRange,
-				}
+		// одним запросом забираем все бронирования на протяжении серии
+		span := domain.TimeRange{
+			Start: occurrences[0].Start,
+			End:   occurrences[len(occurrences)-1].End,
+		}
+		if err := s.checkBookableHours(txCtx, rid, span, occurrences...); err != nil {
+			return err
+		}
+
+		reservations, err := s.repo.FindOverlapping(txCtx, rid, room.Buffer.Widen(span))
+		if err != nil {
+			return err
+		}
+
+		var conflicts []domain.ReservationConflictError
+		for _, occurrence := range occurrences {
+			var conflict domain.ReservationConflictError
+			if err := checkConflicts(reservations, occurrence, room.Buffer, 0); errors.As(err, &conflict) {
+				conflicts = append(conflicts, conflict)
+			}
+		}
+		if len(conflicts) > 0 {
+			return domain.SeriesConflictError{Conflicts: conflicts}
+		}
+
+		series, err = s.repo.CreateSeries(txCtx, rid, rule, occurrences, details)
+		return err
+	})
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+
+	return series, nil
+}
+
+// ReserveBatch бронирует все комнаты пакета в одной транзакции или ни одной,
+// при пересечениях возвращает BatchConflictError со всеми конфликтующими комнатами.
+// Бронирования возвращаются в порядке запроса
+func (s reservationService) ReserveBatch(ctx context.Context, requests []domain.BookingRequest) (reservations []domain.Reservation, err error) {
+	bookings, err := domain.NewRoomBookings(requests)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bookings {
+		if err := s.checkPolicy(ctx, b.RoomID, "", b.TimeRange, 0); err != nil {
+			return nil, fmt.Errorf("room %s: %w", b.RoomID, err)
+		}
+	}
+
+	roomIDs := make([]domain.RoomID, 0, len(bookings))
+	for _, b := range bookings {
+		roomIDs = append(roomIDs, b.RoomID)
+	}
+
+	err = s.withRoomLocks(ctx, roomIDs, func(txCtx context.Context) error {
+		var conflicts []domain.RoomConflict
+		for _, b := range bookings {
+			room, err := s.bookableRoom(txCtx, b.RoomID)
+			if err != nil {
+				return fmt.Errorf("room %s: %w", b.RoomID, err)
+			}
+			if err := s.checkBookableHours(txCtx, b.RoomID, b.TimeRange, b.TimeRange); err != nil {
+				return fmt.Errorf("room %s: %w", b.RoomID, err)
 			}
+
+			existing, err := s.repo.FindOverlapping(txCtx, b.RoomID, room.Buffer.Widen(b.TimeRange))
+			if err != nil {
+				return err
+			}
+
+			var conflict domain.ReservationConflictError
+			if err := checkConflicts(existing, b.TimeRange, room.Buffer, 0); errors.As(err, &conflict) {
+				conflicts = append(conflicts, domain.RoomConflict{RoomID: b.RoomID, Conflict: conflict})
+			}
+		}
+		if len(conflicts) > 0 {
+			return domain.BatchConflictError{Conflicts: conflicts}
 		}
 
-		return s.repo.Create(txCtx, rid, tr)
-	}, pgx.TxOptions{
-		IsoLevel:       pgx.RepeatableRead,
-		AccessMode:     pgx.ReadWrite,
-		DeferrableMode: pgx.NotDeferrable,
+		// транзакция может повториться, результат прошлой попытки не нужен
+		reservations = make([]domain.Reservation, 0, len(bookings))
+		for _, b := range bookings {
+			reservation, err := s.repo.Create(txCtx, b.RoomID, b.TimeRange, domain.ReservationDetails{})
+			if err != nil {
+				return err
+			}
+			reservations = append(reservations, reservation)
+		}
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+func (s reservationService) ListByRoom(ctx context.Context, roomID string, filter domain.ReservationFilter) ([]domain.Reservation, error) {
+	query, err := domain.NewReservationQuery(roomID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListByRoom(ctx, query)
 }
 
-func (s reservationService) ListByRoom(ctx context.Context, roomID string) ([]domain.Reservation, error) {
+// RoomAvailability свободные интервалы комнаты в окне [from, to) длиной не меньше duration
+func (s reservationService) RoomAvailability(ctx context.Context, roomID string, from, to time.Time, duration time.Duration) ([]domain.TimeRange, error) {
 	rid, err := domain.NewRoomID(roomID)
 	if err != nil {
 		return nil, err
 	}
+	window, err := domain.NewTimeRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if window.Duration() > domain.MaxAvailabilityWindow {
+		return nil, fmt.Errorf("RoomAvailability: window should be less than %s: %w", domain.MaxAvailabilityWindow, internal.ErrValidationFailed)
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("RoomAvailability: duration should not be negative: %w", internal.ErrValidationFailed)
+	}
+
+	room, err := s.bookableRoom(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.schedules.GetSchedule(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+	blackouts, err := s.schedules.FindBlackouts(ctx, rid, window)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := s.repo.FindOverlapping(ctx, rid, room.Buffer.Widen(window))
+	if err != nil {
+		return nil, err
+	}
+
+	// новое бронирование со своим буфером не должно задеть буфер существующего,
+	// поэтому существующее занимает еще по обоим буферам с каждой стороны
+	busy := make([]domain.TimeRange, 0, len(reservations)+len(blackouts))
+	for _, r := range reservations {
+		busy = append(busy, room.Buffer.Widen(r.TimeRange))
+	}
+	for _, b := range blackouts {
+		busy = append(busy, b.TimeRange)
+	}
+
+	// свободное время ищется только в часы работы комнаты
+	free := make([]domain.TimeRange, 0)
+	for _, open := range schedule.OpenIntervals(window) {
+		for _, slot := range open.Subtract(busy) {
+			if slot.Duration() >= duration {
+				free = append(free, slot)
+			}
+		}
+	}
+
+	return free, nil
+}
+
+// GetReservation бронирование по ID, вместе с version для ETag
+func (s reservationService) GetReservation(ctx context.Context, id int64) (domain.Reservation, error) {
+	if id <= 0 {
+		return domain.Reservation{},
+			fmt.Errorf("GetReservation: ID should be positive number: %w", internal.ErrValidationFailed)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// CancelReservation удаляет бронирование, только если его version не изменилась
+func (s reservationService) CancelReservation(ctx context.Context, id int64, version int64) error {
+	if id <= 0 {
+		return fmt.Errorf("CancelReservation: ID should be positive number: %w", internal.ErrValidationFailed)
+	}
+	if version <= 0 {
+		return fmt.Errorf("CancelReservation: version should be positive number: %w", internal.ErrValidationFailed)
+	}
+
+	reservation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reservation.Version != version {
+		return domain.ErrReservationVersionMismatch
+	}
+
+	// удаление под той же блокировкой комнаты, что и бронирование,
+	// чтобы освободившийся слот сразу был доступен следующему ReserveRoom
+	var promoted []domain.WaitlistPromotedEvent
+	err = s.withRoomLock(ctx, reservation.RoomID, func(txCtx context.Context) error {
+		if err := s.repo.Delete(txCtx, id, version); err != nil {
+			return err
+		}
+		promoted, err = s.promoteWaitlisted(txCtx, reservation)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishPromoted(ctx, promoted)
+	return nil
+}
+
+// ReapExpiredHolds удаляет истекшие held брони и отдает их слоты листу ожидания,
+// как при отмене. Каждая комната обрабатывается под своей блокировкой и в своей транзакции,
+// ошибка в одной комнате не мешает остальным. Возвращает число удаленных броней
+func (s reservationService) ReapExpiredHolds(ctx context.Context) (int64, error) {
+	roomIDs, err := s.repo.ListExpiredHoldRooms(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		reaped int64
+		errs   []error
+	)
+	for _, roomID := range roomIDs {
+		var (
+			holds    []domain.Reservation
+			promoted []domain.WaitlistPromotedEvent
+		)
+		err := s.withRoomLock(ctx, roomID, func(txCtx context.Context) error {
+			// транзакцию могут повторить, события копятся заново
+			promoted = nil
+
+			var err error
+			holds, err = s.repo.DeleteExpiredHolds(txCtx, roomID)
+			if err != nil {
+				return err
+			}
+			for _, hold := range holds {
+				events, err := s.promoteWaitlisted(txCtx, hold)
+				if err != nil {
+					return err
+				}
+				promoted = append(promoted, events...)
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("room %s: %w", roomID, err))
+			continue
+		}
+
+		reaped += int64(len(holds))
+		s.publishPromoted(ctx, promoted)
+	}
+
+	return reaped, errors.Join(errs...)
+}
+
+// publishPromoted публикует события после commit, ошибка публикации не отменяет продвижение
+func (s reservationService) publishPromoted(ctx context.Context, promoted []domain.WaitlistPromotedEvent) {
+	for _, event := range promoted {
+		if err := s.events.Publish(ctx, event); err != nil {
+			log.Println("publish", event.EventName(), "event:", err.Error())
+		}
+	}
+}
+
+// promoteWaitlisted превращает в бронирования ждущие запросы, которым мешала отмененная бронь cancelled.
+// Вызывается под блокировкой комнаты. Запросы рассматриваются в порядке очереди: более ранний
+// получает слот первым, следующие - только если еще помещаются рядом с ним
+func (s reservationService) promoteWaitlisted(ctx context.Context, cancelled domain.Reservation) ([]domain.WaitlistPromotedEvent, error) {
+	room, err := s.bookableRoom(ctx, cancelled.RoomID)
+	if errors.Is(err, domain.ErrRoomInactive) {
+		// в деактивированную комнату никого не продвигаем, запросы ждут ее включения
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.waitlist.FindWaiting(ctx, cancelled.RoomID, room.Buffer.Widen(cancelled.TimeRange))
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted []domain.WaitlistPromotedEvent
+	for _, entry := range entries {
+		if room.CheckCapacity(entry.Details) != nil {
+			// вместимость комнаты уменьшили, пока запрос ждал
+			continue
+		}
+		err := s.checkPolicy(ctx, entry.RoomID, entry.Details.Owner, entry.TimeRange, 0)
+		if errors.Is(err, &domain.PolicyViolationError{}) {
+			// например, запрос ждал так долго, что его время уже прошло
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		err = s.checkBookableHours(ctx, entry.RoomID, entry.TimeRange, entry.TimeRange)
+		if errors.Is(err, &domain.OutsideBookableHoursError{}) {
+			// расписание поменялось, пока запрос ждал
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// видит и бронирования, созданные для более ранних запросов в этом цикле
+		reservations, err := s.repo.FindOverlapping(ctx, entry.RoomID, room.Buffer.Widen(entry.TimeRange))
+		if err != nil {
+			return nil, err
+		}
+		if checkConflicts(reservations, entry.TimeRange, room.Buffer, 0) != nil {
+			continue
+		}
+
+		reservation, err := s.repo.Create(ctx, entry.RoomID, entry.TimeRange, entry.Details)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.waitlist.Promote(ctx, entry.ID, reservation.ID); err != nil {
+			return nil, err
+		}
+
+		entry.Status = domain.WaitlistPromoted
+		entry.ReservationID = reservation.ID
+		promoted = append(promoted, domain.WaitlistPromotedEvent{
+			Entry:       entry,
+			Reservation: reservation,
+			CancelledID: cancelled.ID,
+		})
+	}
+
+	return promoted, nil
+}
+
+// ConfirmReservation делает held бронь постоянной, только если ее version не изменилась
+// и она еще не истекла
+func (s reservationService) ConfirmReservation(ctx context.Context, id int64, version int64) (domain.Reservation, error) {
+	if id <= 0 {
+		return domain.Reservation{},
+			fmt.Errorf("ConfirmReservation: ID should be positive number: %w", internal.ErrValidationFailed)
+	}
+	if version <= 0 {
+		return domain.Reservation{},
+			fmt.Errorf("ConfirmReservation: version should be positive number: %w", internal.ErrValidationFailed)
+	}
+
+	reservation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	if reservation.Version != version {
+		return domain.Reservation{}, domain.ErrReservationVersionMismatch
+	}
+	if reservation.Status != domain.StatusHeld {
+		return domain.Reservation{}, domain.ErrReservationNotHeld
+	}
+
+	// под блокировкой комнаты, чтобы не подтвердить бронь, слот которой
+	// после истечения уже занял параллельный ReserveRoom
+	err = s.withRoomLock(ctx, reservation.RoomID, func(txCtx context.Context) error {
+		reservation.Version, err = s.repo.Confirm(txCtx, id, version)
+		return err
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	reservation.Status = domain.StatusConfirmed
+	reservation.ExpiresAt = nil
+	return reservation, nil
+}
+
+// RescheduleReservation переносит бронирование, только если его version не изменилась
+func (s reservationService) RescheduleReservation(ctx context.Context, id int64, from, to time.Time, version int64) (domain.Reservation, error) {
+	if id <= 0 {
+		return domain.Reservation{},
+			fmt.Errorf("RescheduleReservation: ID should be positive number: %w", internal.ErrValidationFailed)
+	}
+	if version <= 0 {
+		return domain.Reservation{},
+			fmt.Errorf("RescheduleReservation: version should be positive number: %w", internal.ErrValidationFailed)
+	}
+	tr, err := domain.NewTimeRange(from, to)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	reservation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	// быстрый отказ без блокировки комнаты, окончательно версию проверяет условный update
+	if reservation.Version != version {
+		return domain.Reservation{}, domain.ErrReservationVersionMismatch
+	}
+
+	err = s.withRoomLock(ctx, reservation.RoomID, func(txCtx context.Context) error {
+		room, err := s.bookableRoom(txCtx, reservation.RoomID)
+		if err != nil {
+			return err
+		}
+		// переносимое бронирование уже учтено в квоте владельца
+		if err := s.checkPolicy(txCtx, reservation.RoomID, reservation.Details.Owner, tr, id); err != nil {
+			return err
+		}
+		if err := s.checkBookableHours(txCtx, reservation.RoomID, tr, tr); err != nil {
+			return err
+		}
+
+		reservations, err := s.repo.FindOverlapping(txCtx, reservation.RoomID, room.Buffer.Widen(tr))
+		if err != nil {
+			return err
+		}
+
+		// переносимое бронирование не должно конфликтовать само с собой
+		if err := checkConflicts(reservations, tr, room.Buffer, id); err != nil {
+			return err
+		}
+
+		reservation.Version, err = s.repo.Update(txCtx, id, tr, version)
+		return err
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	reservation.TimeRange = tr
+	return reservation, nil
+}
+
+// withRoomLock выполняет f в транзакции под блокировкой комнаты.
+// Advisory lock берется внутри транзакции (он живет до ее конца), in-memory - до нее,
+// а отпускаются оба после commit/rollback
+func (s reservationService) withRoomLock(ctx context.Context, roomID domain.RoomID, f func(txCtx context.Context) error) error {
+	return s.withRoomLocks(ctx, []domain.RoomID{roomID}, f)
+}
+
+// withRoomLocks то же, что withRoomLock, но для нескольких комнат.
+// Комнаты блокируются в порядке сортировки ID, поэтому два пакета
+// с общими комнатами не могут взять их накрест и зависнуть друг на друге
+func (s reservationService) withRoomLocks(ctx context.Context, roomIDs []domain.RoomID, f func(txCtx context.Context) error) error {
+	sorted := slices.Clone(roomIDs)
+	slices.Sort(sorted)
+
+	var unlocks []func()
+	release := func() {
+		// в обратном порядке, как defer
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+		unlocks = nil
+	}
+	defer release()
+
+	lockAll := func(ctx context.Context) error {
+		for _, roomID := range sorted {
+			unlock, err := s.locker.Lock(ctx, roomID)
+			if err != nil {
+				return err
+			}
+			unlocks = append(unlocks, unlock)
+		}
+		return nil
+	}
+
+	// started - транзакция открылась и f начала выполняться, дальше истекший контекст уже не ожидание комнаты
+	var started bool
+	if !s.locker.NeedsTx() {
+		if err := lockAll(ctx); err != nil {
+			return err
+		}
+		err := s.tx.Execute(ctx, func(txCtx context.Context) error {
+			started = true
+			return f(txCtx)
+		}, reserveTxOptions)
+		return lockTimeout(err, started)
+	}
+
+	err := s.tx.Execute(ctx, func(txCtx context.Context) error {
+		started = true
+		// Execute может повторить транзакцию, блокировки прошлой попытки
+		// нужно отпустить, иначе следующая попытка будет ждать сама себя
+		release()
+
+		// ожидание advisory lock локер сам отдает как ErrLockTimeout
+		if err := lockAll(txCtx); err != nil {
+			return err
+		}
+		return f(txCtx)
+	}, reserveTxOptions)
+	return lockTimeout(err, started)
+}
+
+// lockTimeout истекший до начала транзакции контекст - это тоже ожидание занятой комнаты: при всплеске запросов
+// к одной комнате он обычно истекает, пока BeginTx ждет соединение из пула. После started истечь он мог в f
+// или в commit, и тогда неизвестно, записано ли что-то, поэтому ошибка отдается как есть, а не как повод повторить
+func lockTimeout(err error, started bool) error {
+	if err == nil || started || errors.Is(err, internal.ErrLockTimeout) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%w: %w", internal.ErrLockTimeout, err)
+	}
+	return err
+}
 
-	return s.repo.ListByRoom(ctx, rid)
+// bookableRoom комната должна быть заведена и не деактивирована
+func (s reservationService) bookableRoom(ctx context.Context, roomID domain.RoomID) (domain.Room, error) {
+	room, err := s.rooms.GetByID(ctx, roomID)
+	if err != nil {
+		return domain.Room{}, err
+	}
+	if !room.Active {
+		return domain.Room{}, domain.ErrRoomInactive
+	}
+	return room, nil
+}
+
+// checkPolicy проверяет tr политикой бронирования. Квота считается только при заданном owner,
+// ignoreID - бронирование, которое не нужно учитывать в квоте (0 - учитываются все)
+func (s reservationService) checkPolicy(ctx context.Context, roomID domain.RoomID, owner string, tr domain.TimeRange, ignoreID int64) error {
+	return s.checkPolicies(ctx, roomID, owner, []domain.TimeRange{tr}, ignoreID)
+}
+
+// checkPolicies то же, что checkPolicy, но для нескольких бронирований одного owner сразу, например вхождений серии.
+// Каждое следующее проверяется так, будто предыдущие уже созданы, поэтому в квоту идут все
+func (s reservationService) checkPolicies(ctx context.Context, roomID domain.RoomID, owner string, ranges []domain.TimeRange, ignoreID int64) error {
+	if s.policy == nil {
+		return nil
+	}
+
+	var active int
+	if owner != "" {
+		// бронирования владельца в других комнатах идут под блокировками других комнат,
+		// поэтому подсчет и создание сериализуются еще и по владельцу
+		if err := s.repo.LockOwner(ctx, owner); err != nil {
+			return err
+		}
+		var err error
+		active, err = s.repo.CountActiveByOwner(ctx, owner, ignoreID)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	for i, tr := range ranges {
+		booking := domain.Booking{
+			RoomID:      roomID,
+			Owner:       owner,
+			TimeRange:   tr,
+			Now:         now,
+			OwnerActive: active + i,
+		}
+		if err := domain.CheckPolicy(s.policy, booking); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBookableHours каждый из ranges должен приходиться на часы работы комнаты
+// и не попадать на blackout, span покрывает все ranges
+func (s reservationService) checkBookableHours(ctx context.Context, roomID domain.RoomID, span domain.TimeRange, ranges ...domain.TimeRange) error {
+	schedule, err := s.schedules.GetSchedule(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	blackouts, err := s.schedules.FindBlackouts(ctx, roomID, span)
+	if err != nil {
+		return err
+	}
+
+	for _, tr := range ranges {
+		if err := schedule.CheckBookable(tr, blackouts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConflicts ищет пересечение tr с бронированиями комнаты с учетом ее буфера,
+// бронирование с ID ignoreID не учитывается (0 - учитываются все).
+// Пересечение с самим бронированием важнее пересечения только с буфером
+func checkConflicts(reservations []domain.Reservation, tr domain.TimeRange, buffer domain.RoomBuffer, ignoreID int64) error {
+	var bufferConflict *domain.ReservationConflictError
+	for _, r := range reservations {
+		if ignoreID > 0 && r.ID == ignoreID {
+			continue
+		}
+		reason, ok := buffer.Conflict(tr, r.TimeRange)
+		if !ok {
+			continue
+		}
+
+		conflict := domain.ReservationConflictError{
+			Reservation:         tr,
+			ConflictReservation: r.TimeRange,
+			Reason:              reason,
+		}
+		if reason == domain.ConflictBooking {
+			return conflict
+		}
+		if bufferConflict == nil {
+			bufferConflict = &conflict
+		}
+	}
+	if bufferConflict != nil {
+		return *bufferConflict
+	}
+	return nil
 }
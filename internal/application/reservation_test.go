@@ -3,6 +3,8 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,8 +23,9 @@ func Test_CreateReservation(t *testing.T) {
 
 	txManager := mock_application.NewMockTransaction(ctrl)
 	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
 
-	service := NewReservationService(repo, txManager)
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
 
 	now := time.Now().Truncate(time.Second).UTC()
 
@@ -41,11 +44,27 @@ func Test_CreateReservation(t *testing.T) {
 		to:     now.Add(1 * time.Hour),
 	}
 
+	defaultRoom := domain.Room{
+		ID:       domain.RoomID(defaultArgs.roomID),
+		Name:     defaultArgs.roomID,
+		Capacity: 10,
+		Active:   true,
+	}
+
+	defaultReservation := domain.Reservation{
+		ID:     1,
+		RoomID: domain.RoomID(defaultArgs.roomID),
+		TimeRange: domain.TimeRange{
+			Start: defaultArgs.from,
+			End:   defaultArgs.to,
+		},
+	}
+
 	testCases := []struct {
 		name        string
 		args        args
 		buildStubs  func()
-		checkResult func(t *testing.T, err error)
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
 	}{
 		{
 			name: "OK",
@@ -57,22 +76,27 @@ func Test_CreateReservation(t *testing.T) {
 					},
 				).Times(1)
 
-				c2 := repo.EXPECT().ListByRoom(
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+
+				c2 := repo.EXPECT().FindOverlapping(
 					gomock.Any(),
 					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
+					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
 				).Return(nil, nil).Times(1)
 
 				c3 := repo.EXPECT().Create(
 					gomock.Any(),
 					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
 					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
-				).Return(nil).Times(1)
+					gomock.Eq(domain.ReservationDetails{}),
+				).Return(defaultReservation, nil).Times(1)
 
 				c2.After(c1)
 				c3.After(c2)
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
 				assert.NoError(t, err)
+				assert.Equal(t, defaultReservation, reservation)
 			},
 		},
 		{
@@ -85,11 +109,13 @@ func Test_CreateReservation(t *testing.T) {
 			},
 			buildStubs: func() {
 				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-				repo.EXPECT().ListByRoom(gomock.Any(), gomock.Any()).Times(0)
-				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
 				assert.Error(t, err)
+				assert.Empty(t, reservation)
 				assert.ErrorIs(t, err, internal.ErrValidationFailed)
 			},
 		},
@@ -103,16 +129,69 @@ func Test_CreateReservation(t *testing.T) {
 			},
 			buildStubs: func() {
 				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-				repo.EXPECT().ListByRoom(gomock.Any(), gomock.Any()).Times(0)
-				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
 				assert.Error(t, err)
+				assert.Empty(t, reservation)
 				assert.ErrorIs(t, err, internal.ErrValidationFailed)
 			},
 		},
 		{
-			name: "unexpected error from ListByRoom",
+			name: "room not found error",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+
+				c2 := rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).
+					Return(domain.Room{}, domain.ErrRoomNotFound).Times(1) // note
+
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				c2.After(c1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.Empty(t, reservation)
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+			},
+		},
+		{
+			name: "room inactive error",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+
+				inactiveRoom := defaultRoom
+				inactiveRoom.Active = false // note
+
+				c2 := rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).
+					Return(inactiveRoom, nil).Times(1)
+
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				c2.After(c1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.Empty(t, reservation)
+				assert.ErrorIs(t, err, domain.ErrRoomInactive)
+			},
+		},
+		{
+			name: "unexpected error from FindOverlapping",
 			args: defaultArgs,
 			buildStubs: func() {
 				c1 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
@@ -121,18 +200,22 @@ func Test_CreateReservation(t *testing.T) {
 					},
 				).Times(1)
 
-				c2 := repo.EXPECT().ListByRoom(
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+
+				c2 := repo.EXPECT().FindOverlapping(
 					gomock.Any(),
 					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
+					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
 				).Return(nil, unexpectedError).Times(1) // note
 
-				c3 := repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				c3 := repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 				c2.After(c1)
 				c3.After(c2)
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
 				assert.Error(t, err)
+				assert.Empty(t, reservation)
 				assert.ErrorIs(t, err, unexpectedError)
 			},
 		},
@@ -146,22 +229,27 @@ func Test_CreateReservation(t *testing.T) {
 					},
 				).Times(1)
 
-				c2 := repo.EXPECT().ListByRoom(
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+
+				c2 := repo.EXPECT().FindOverlapping(
 					gomock.Any(),
 					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
+					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
 				).Return(nil, nil).Times(1)
 
 				c3 := repo.EXPECT().Create(
 					gomock.Any(),
 					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
 					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
-				).Return(unexpectedError).Times(1) // note
+					gomock.Eq(domain.ReservationDetails{}),
+				).Return(domain.Reservation{}, unexpectedError).Times(1) // note
 
 				c2.After(c1)
 				c3.After(c2)
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
 				assert.Error(t, err)
+				assert.Empty(t, reservation)
 				assert.ErrorIs(t, err, unexpectedError)
 			},
 		},
@@ -175,9 +263,12 @@ func Test_CreateReservation(t *testing.T) {
 					},
 				).Times(1)
 
-				c2 := repo.EXPECT().ListByRoom(
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+
+				c2 := repo.EXPECT().FindOverlapping(
 					gomock.Any(),
 					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
+					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
 				).Return([]domain.Reservation{
 					{
 						TimeRange: domain.TimeRange{
@@ -187,124 +278,286 @@ func Test_CreateReservation(t *testing.T) {
 					},
 				}, nil).Times(1)
 
-				c3 := repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				c3 := repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 				c2.After(c1)
 				c3.After(c2)
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
 				assert.Error(t, err)
+				assert.Empty(t, reservation)
 				assert.ErrorIs(t, err, &domain.ReservationConflictError{})
 			},
 		},
+		{
+			name: "reservation buffer conflict error",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+
+				bufferedRoom := defaultRoom
+				bufferedRoom.Buffer = domain.RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute} // note
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(bufferedRoom, nil).Times(1)
+
+				// окно поиска расширено на оба буфера
+				c2 := repo.EXPECT().FindOverlapping(
+					gomock.Any(),
+					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
+					gomock.Eq(domain.TimeRange{Start: defaultArgs.from.Add(-20 * time.Minute), End: defaultArgs.to.Add(20 * time.Minute)}),
+				).Return([]domain.Reservation{
+					{
+						ID: 2,
+						TimeRange: domain.TimeRange{
+							Start: defaultArgs.from.Add(-1 * time.Hour),
+							End:   defaultArgs.from, // note
+						},
+					},
+				}, nil).Times(1)
+
+				c3 := repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				c2.After(c1)
+				c3.After(c2)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Empty(t, reservation)
+
+				var conflict domain.ReservationConflictError
+				assert.ErrorAs(t, err, &conflict)
+				assert.Equal(t, domain.ConflictBuffer, conflict.Reason)
+				assert.Equal(t, defaultArgs.from, conflict.ConflictReservation.End)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.buildStubs()
-			err := service.ReserveRoom(tc.args.ctx, tc.args.roomID, tc.args.from, tc.args.to)
-			tc.checkResult(t, err)
+			reservation, err := service.ReserveRoom(tc.args.ctx, tc.args.roomID, tc.args.from, tc.args.to, domain.ReservationDetails{})
+			tc.checkResult(t, reservation, err)
 		})
 	}
 
 }
 
-func Test_ListByRoom(t *testing.T) {
+func Test_ReserveRecurring(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	txManager := mock_application.NewMockTransaction(ctrl)
 	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
 
-	service := NewReservationService(repo, txManager)
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
 
-	now := time.Now()
+	now := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
 
 	unexpectedError := errors.New("unexpected error")
 
 	type args struct {
-		ctx    context.Context
-		roomID string
+		ctx      context.Context
+		roomID   string
+		from, to time.Time
+		details  domain.ReservationDetails
+		rule     string
 	}
 
 	defaultArgs := args{
 		ctx:    context.Background(),
 		roomID: "room",
+		from:   now,
+		to:     now.Add(1 * time.Hour),
+		details: domain.ReservationDetails{
+			Title:     "standup",
+			Attendees: []string{"bob", "carol"},
+		},
+		rule: "FREQ=WEEKLY;COUNT=3",
 	}
 
-	defaultReservations := []domain.Reservation{
-		{
-			ID:     1,
-			RoomID: "1",
-			TimeRange: domain.TimeRange{
-				Start: now,
-				End:   now.Add(1 * time.Minute),
-			},
+	defaultRoom := domain.Room{
+		ID:       domain.RoomID(defaultArgs.roomID),
+		Name:     defaultArgs.roomID,
+		Capacity: 10,
+		Active:   true,
+	}
+
+	occurrences := []domain.TimeRange{
+		{Start: now, End: now.Add(1 * time.Hour)},
+		{Start: now.AddDate(0, 0, 7), End: now.AddDate(0, 0, 7).Add(1 * time.Hour)},
+		{Start: now.AddDate(0, 0, 14), End: now.AddDate(0, 0, 14).Add(1 * time.Hour)},
+	}
+	span := domain.TimeRange{Start: occurrences[0].Start, End: occurrences[2].End}
+
+	defaultSeries := domain.ReservationSeries{
+		ID:     1,
+		RoomID: defaultRoom.ID,
+		Rule:   defaultArgs.rule,
+		Reservations: []domain.Reservation{
+			{ID: 1, RoomID: defaultRoom.ID, TimeRange: occurrences[0]},
+			{ID: 2, RoomID: defaultRoom.ID, TimeRange: occurrences[1]},
+			{ID: 3, RoomID: defaultRoom.ID, TimeRange: occurrences[2]},
 		},
 	}
 
+	executeTx := func() *gomock.Call {
+		return txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+	}
+
 	testCases := []struct {
 		name        string
 		args        args
 		buildStubs  func()
-		checkResult func(t *testing.T, reservations []domain.Reservation, err error)
+		checkResult func(t *testing.T, series domain.ReservationSeries, err error)
 	}{
 		{
 			name: "OK",
 			args: defaultArgs,
 			buildStubs: func() {
-				repo.EXPECT().ListByRoom(
+				c1 := executeTx()
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+
+				c2 := repo.EXPECT().FindOverlapping(
 					gomock.Any(),
-					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
-				).Times(1).Return(defaultReservations, nil)
+					gomock.Eq(defaultRoom.ID),
+					gomock.Eq(span),
+				).Return([]domain.Reservation{
+					// между вхождениями серии - не конфликт
+					{ID: 10, RoomID: defaultRoom.ID, TimeRange: domain.TimeRange{Start: now.Add(1 * time.Hour), End: now.Add(2 * time.Hour)}},
+				}, nil).Times(1)
+
+				c3 := repo.EXPECT().CreateSeries(
+					gomock.Any(),
+					gomock.Eq(defaultRoom.ID),
+					gomock.Eq(defaultArgs.rule),
+					gomock.Eq(occurrences),
+					gomock.Eq(defaultArgs.details),
+				).Return(defaultSeries, nil).Times(1)
+
+				c2.After(c1)
+				c3.After(c2)
 			},
-			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
 				assert.NoError(t, err)
-				assert.Equal(t, defaultReservations, reservations)
+				assert.Equal(t, defaultSeries, series)
 			},
 		},
 		{
-			name: "OK with empty reservations",
-			args: defaultArgs,
+			name: "validation error rule",
+			args: args{
+				ctx:     defaultArgs.ctx,
+				roomID:  defaultArgs.roomID,
+				from:    defaultArgs.from,
+				to:      defaultArgs.to,
+				details: defaultArgs.details,
+				rule:    "FREQ=WEEKLY", // note
+			},
 			buildStubs: func() {
-				repo.EXPECT().ListByRoom(
-					gomock.Any(),
-					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
-				).Times(1).Return(nil, nil) // note
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
-				assert.NoError(t, err)
-				assert.Nil(t, reservations)
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, series)
 			},
 		},
 		{
-			name: "validation error room id",
+			name: "validation error time range",
 			args: args{
-				ctx:    defaultArgs.ctx,
-				roomID: "", // note
+				ctx:     defaultArgs.ctx,
+				roomID:  defaultArgs.roomID,
+				from:    defaultArgs.to,   // note
+				to:      defaultArgs.from, // note
+				details: defaultArgs.details,
+				rule:    defaultArgs.rule,
 			},
 			buildStubs: func() {
-				repo.EXPECT().ListByRoom(gomock.Any(), gomock.Any()).Times(0)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
-				assert.Error(t, err)
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
 				assert.ErrorIs(t, err, internal.ErrValidationFailed)
-				assert.Nil(t, reservations)
+				assert.Empty(t, series)
 			},
 		},
 		{
-			name: "unexpected error from ListByRoom",
+			name: "validation error details",
+			args: args{
+				ctx:     defaultArgs.ctx,
+				roomID:  defaultArgs.roomID,
+				from:    defaultArgs.from,
+				to:      defaultArgs.to,
+				details: domain.ReservationDetails{Attendees: []string{"bob", "bob"}}, // note
+				rule:    defaultArgs.rule,
+			},
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, series)
+			},
+		},
+		{
+			name: "room inactive error",
 			args: defaultArgs,
 			buildStubs: func() {
-				repo.EXPECT().ListByRoom(
-					gomock.Any(),
-					gomock.Eq(domain.RoomID(defaultArgs.roomID)),
-				).Times(1).Return(nil, unexpectedError)
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(domain.Room{ID: defaultRoom.ID}, nil).Times(1) // note
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
-			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
-				assert.Error(t, err)
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomInactive)
+				assert.Empty(t, series)
+			},
+		},
+		{
+			name: "series conflict error with every conflicting occurrence",
+			args: defaultArgs,
+			buildStubs: func() {
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.Reservation{
+					{ID: 10, RoomID: defaultRoom.ID, TimeRange: occurrences[0]},
+					{ID: 11, RoomID: defaultRoom.ID, TimeRange: domain.TimeRange{Start: occurrences[2].Start.Add(30 * time.Minute), End: occurrences[2].End}}, // note
+				}, nil).Times(1)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				assert.ErrorIs(t, err, &domain.SeriesConflictError{})
+				assert.Empty(t, series)
+
+				var conflict domain.SeriesConflictError
+				assert.True(t, errors.As(err, &conflict))
+				assert.Equal(t, []domain.ReservationConflictError{
+					{Reservation: occurrences[0], ConflictReservation: occurrences[0]},
+					{Reservation: occurrences[2], ConflictReservation: domain.TimeRange{Start: occurrences[2].Start.Add(30 * time.Minute), End: occurrences[2].End}},
+				}, conflict.Conflicts)
+			},
+		},
+		{
+			name: "unexpected error from CreateSeries",
+			args: defaultArgs,
+			buildStubs: func() {
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.ReservationSeries{}, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
 				assert.ErrorIs(t, err, unexpectedError)
-				assert.Nil(t, reservations)
+				assert.Empty(t, series)
 			},
 		},
 	}
@@ -312,8 +565,2711 @@ func Test_ListByRoom(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.buildStubs()
-			reservation, err := service.ListByRoom(tc.args.ctx, tc.args.roomID)
-			tc.checkResult(t, reservation, err)
+			series, err := service.ReserveRecurring(tc.args.ctx, tc.args.roomID, tc.args.from, tc.args.to, tc.args.details, tc.args.rule)
+			tc.checkResult(t, series, err)
 		})
 	}
+
+	t.Run("OK expands in room time zone across DST", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+
+		schedules := mock_domain.NewMockScheduleRepository(ctrl)
+		schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Eq(defaultRoom.ID)).
+			Return(domain.RoomSchedule{RoomID: defaultRoom.ID, Location: berlin}, nil).MinTimes(1)
+		schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+		service := NewReservationService(repo, rooms, schedules, nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+		// 17:00-18:00 по Берлину до и после перевода часов 31 марта
+		local := func(day int) domain.TimeRange {
+			s := time.Date(2024, time.March, day, 17, 0, 0, 0, berlin)
+			return domain.TimeRange{Start: s.UTC(), End: s.Add(1 * time.Hour).UTC()}
+		}
+		want := []domain.TimeRange{local(25), local(32)}
+
+		executeTx()
+		rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+		repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(want), gomock.Any()).Return(domain.ReservationSeries{ID: 1}, nil).Times(1)
+
+		_, err = service.ReserveRecurring(context.Background(), defaultArgs.roomID, want[0].Start, want[0].End, domain.ReservationDetails{}, "FREQ=WEEKLY;COUNT=2")
+		assert.NoError(t, err)
+	})
+}
+
+func Test_ReserveBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+	locker := mock_application.NewMockRoomLocker(ctrl)
+	locker.EXPECT().NeedsTx().Return(true).AnyTimes()
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, locker, nil, nil)
+
+	now := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	tr := domain.TimeRange{Start: now, End: now.Add(2 * time.Hour)}
+
+	unexpectedError := errors.New("unexpected error")
+
+	// главный зал и две комнаты для трансляции, порядок запроса не отсортирован
+	defaultRequests := []domain.BookingRequest{
+		{RoomID: "hall", From: tr.Start, To: tr.End},
+		{RoomID: "overflow-b", From: tr.Start, To: tr.End},
+		{RoomID: "overflow-a", From: tr.Start, To: tr.End},
+	}
+
+	bookable := func(roomID domain.RoomID) domain.Room {
+		return domain.Room{ID: roomID, Name: string(roomID), Capacity: 10, Active: true}
+	}
+
+	executeTx := func() *gomock.Call {
+		return txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Eq(reserveTxOptions)).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+	}
+
+	// lockAll ожидает блокировки в порядке сортировки ID и записывает их в events
+	lockAll := func(events *[]string) {
+		var prev *gomock.Call
+		for _, roomID := range []domain.RoomID{"hall", "overflow-a", "overflow-b"} {
+			roomID := roomID
+			call := locker.EXPECT().Lock(gomock.Any(), gomock.Eq(roomID)).DoAndReturn(
+				func(ctx context.Context, roomID domain.RoomID) (func(), error) {
+					*events = append(*events, "lock "+string(roomID))
+					return func() { *events = append(*events, "unlock "+string(roomID)) }, nil
+				},
+			).Times(1)
+			if prev != nil {
+				call.After(prev)
+			}
+			prev = call
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		requests    []domain.BookingRequest
+		buildStubs  func(events *[]string)
+		checkResult func(t *testing.T, events []string, reservations []domain.Reservation, err error)
+	}{
+		{
+			name:     "OK rooms locked in sorted order, reservations in request order",
+			requests: defaultRequests,
+			buildStubs: func(events *[]string) {
+				executeTx()
+				lockAll(events)
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID) (domain.Room, error) {
+						return bookable(roomID), nil
+					},
+				).Times(3)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Eq(tr)).Return(nil, nil).Times(3)
+
+				var id int64
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Eq(tr), gomock.Eq(domain.ReservationDetails{})).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID, tr domain.TimeRange, _ domain.ReservationDetails) (domain.Reservation, error) {
+						id++
+						return domain.Reservation{ID: id, RoomID: roomID, TimeRange: tr}, nil
+					},
+				).Times(3)
+			},
+			checkResult: func(t *testing.T, events []string, reservations []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Reservation{
+					{ID: 1, RoomID: "hall", TimeRange: tr},
+					{ID: 2, RoomID: "overflow-b", TimeRange: tr},
+					{ID: 3, RoomID: "overflow-a", TimeRange: tr},
+				}, reservations)
+				assert.Equal(t, []string{
+					"lock hall", "lock overflow-a", "lock overflow-b",
+					"unlock overflow-b", "unlock overflow-a", "unlock hall",
+				}, events)
+			},
+		},
+		{
+			name:     "validation error duplicated room",
+			requests: append(defaultRequests, defaultRequests[0]), // note
+			buildStubs: func(events *[]string) {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, events []string, reservations []domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, reservations)
+			},
+		},
+		{
+			name:     "batch conflict error with every conflicting room, nothing created",
+			requests: defaultRequests,
+			buildStubs: func(events *[]string) {
+				executeTx()
+				lockAll(events)
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID) (domain.Room, error) {
+						return bookable(roomID), nil
+					},
+				).Times(3)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(domain.RoomID("hall")), gomock.Any()).Return([]domain.Reservation{
+					{ID: 10, RoomID: "hall", TimeRange: tr}, // note
+				}, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(domain.RoomID("overflow-b")), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(domain.RoomID("overflow-a")), gomock.Any()).Return([]domain.Reservation{
+					{ID: 11, RoomID: "overflow-a", TimeRange: domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(3 * time.Hour)}}, // note
+				}, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, events []string, reservations []domain.Reservation, err error) {
+				assert.ErrorIs(t, err, &domain.BatchConflictError{})
+				assert.Nil(t, reservations)
+
+				var conflict domain.BatchConflictError
+				assert.True(t, errors.As(err, &conflict))
+				assert.Equal(t, []domain.RoomConflict{
+					{RoomID: "hall", Conflict: domain.ReservationConflictError{Reservation: tr, ConflictReservation: tr}},
+					{RoomID: "overflow-a", Conflict: domain.ReservationConflictError{
+						Reservation:         tr,
+						ConflictReservation: domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(3 * time.Hour)},
+					}},
+				}, conflict.Conflicts)
+
+				// блокировки отпускаются и при ошибке
+				assert.Len(t, events, 6)
+			},
+		},
+		{
+			name:     "room inactive error",
+			requests: defaultRequests,
+			buildStubs: func(events *[]string) {
+				executeTx()
+				lockAll(events)
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(domain.RoomID("hall"))).Return(bookable("hall"), nil).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(domain.RoomID("overflow-b"))).Return(domain.Room{ID: "overflow-b"}, nil).Times(1) // note
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, events []string, reservations []domain.Reservation, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomInactive)
+				assert.ErrorContains(t, err, "overflow-b")
+				assert.Nil(t, reservations)
+			},
+		},
+		{
+			name:     "lock error",
+			requests: defaultRequests,
+			buildStubs: func(events *[]string) {
+				executeTx()
+
+				locker.EXPECT().Lock(gomock.Any(), gomock.Eq(domain.RoomID("hall"))).
+					Return(func() { *events = append(*events, "unlock hall") }, nil).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Eq(domain.RoomID("overflow-a"))).
+					Return(nil, internal.ErrLockTimeout).Times(1) // note
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, events []string, reservations []domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.Nil(t, reservations)
+				assert.Equal(t, []string{"unlock hall"}, events)
+			},
+		},
+		{
+			name:     "unexpected error from Create",
+			requests: defaultRequests,
+			buildStubs: func(events *[]string) {
+				executeTx()
+				lockAll(events)
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID) (domain.Room, error) {
+						return bookable(roomID), nil
+					},
+				).Times(3)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Reservation{ID: 1}, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.Reservation{}, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, events []string, reservations []domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, reservations)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			tc.buildStubs(&events)
+			reservations, err := service.ReserveBatch(context.Background(), tc.requests)
+			tc.checkResult(t, events, reservations, err)
+		})
+	}
+}
+
+func Test_ListByRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now()
+
+	unexpectedError := errors.New("unexpected error")
+
+	type args struct {
+		ctx    context.Context
+		roomID string
+		filter domain.ReservationFilter
+	}
+
+	defaultArgs := args{
+		ctx:    context.Background(),
+		roomID: "room",
+		filter: domain.ReservationFilter{
+			From:  now,
+			To:    now.Add(1 * time.Hour),
+			Limit: 10,
+		},
+	}
+
+	defaultQuery := domain.ReservationQuery{
+		RoomID:            domain.RoomID(defaultArgs.roomID),
+		ReservationFilter: defaultArgs.filter,
+	}
+
+	defaultReservations := []domain.Reservation{
+		{
+			ID:     1,
+			RoomID: "1",
+			TimeRange: domain.TimeRange{
+				Start: now,
+				End:   now.Add(1 * time.Minute),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, reservations []domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListByRoom(
+					gomock.Any(),
+					gomock.Eq(defaultQuery),
+				).Times(1).Return(defaultReservations, nil)
+			},
+			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultReservations, reservations)
+			},
+		},
+		{
+			name: "OK with empty reservations",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListByRoom(
+					gomock.Any(),
+					gomock.Eq(defaultQuery),
+				).Times(1).Return(nil, nil) // note
+			},
+			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, reservations)
+			},
+		},
+		{
+			name: "OK default limit",
+			args: args{
+				ctx:    defaultArgs.ctx,
+				roomID: defaultArgs.roomID,
+				filter: domain.ReservationFilter{}, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().ListByRoom(
+					gomock.Any(),
+					gomock.Eq(domain.ReservationQuery{
+						RoomID:            domain.RoomID(defaultArgs.roomID),
+						ReservationFilter: domain.ReservationFilter{Limit: domain.DefaultReservationsLimit},
+					}),
+				).Times(1).Return(defaultReservations, nil)
+			},
+			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultReservations, reservations)
+			},
+		},
+		{
+			name: "validation error room id",
+			args: args{
+				ctx:    defaultArgs.ctx,
+				roomID: "", // note
+				filter: defaultArgs.filter,
+			},
+			buildStubs: func() {
+				repo.EXPECT().ListByRoom(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, reservations)
+			},
+		},
+		{
+			name: "validation error filter",
+			args: args{
+				ctx:    defaultArgs.ctx,
+				roomID: defaultArgs.roomID,
+				filter: domain.ReservationFilter{Limit: -1}, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().ListByRoom(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, reservations)
+			},
+		},
+		{
+			name: "unexpected error from ListByRoom",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListByRoom(
+					gomock.Any(),
+					gomock.Eq(defaultQuery),
+				).Times(1).Return(nil, unexpectedError)
+			},
+			checkResult: func(t *testing.T, reservations []domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, reservations)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.ListByRoom(tc.args.ctx, tc.args.roomID, tc.args.filter)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_RoomAvailability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Hour).UTC()
+
+	unexpectedError := errors.New("unexpected error")
+
+	type args struct {
+		roomID   string
+		from, to time.Time
+		duration time.Duration
+	}
+
+	defaultArgs := args{
+		roomID:   "room",
+		from:     now,
+		to:       now.Add(4 * time.Hour),
+		duration: 1 * time.Hour,
+	}
+
+	defaultRoom := domain.Room{
+		ID:       domain.RoomID(defaultArgs.roomID),
+		Name:     defaultArgs.roomID,
+		Capacity: 10,
+		Active:   true,
+	}
+
+	window := domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, free []domain.TimeRange, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(window)).Return([]domain.Reservation{
+					{ID: 1, TimeRange: domain.TimeRange{Start: now.Add(30 * time.Minute), End: now.Add(1 * time.Hour)}},
+					{ID: 2, TimeRange: domain.TimeRange{Start: now.Add(2 * time.Hour), End: now.Add(150 * time.Minute)}},
+				}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.NoError(t, err)
+				// 30 минут в начале короче duration
+				assert.Equal(t, []domain.TimeRange{
+					{Start: now.Add(1 * time.Hour), End: now.Add(2 * time.Hour)},
+					{Start: now.Add(150 * time.Minute), End: now.Add(4 * time.Hour)},
+				}, free)
+			},
+		},
+		{
+			name: "OK fully booked",
+			args: defaultArgs,
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.Reservation{
+					{ID: 1, TimeRange: window}, // note
+				}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, free)
+				assert.NotNil(t, free)
+			},
+		},
+		{
+			name: "OK room buffer",
+			args: defaultArgs,
+			buildStubs: func() {
+				bufferedRoom := defaultRoom
+				bufferedRoom.Buffer = domain.RoomBuffer{Before: 5 * time.Minute, After: 10 * time.Minute} // note
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(bufferedRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(bufferedRoom.Buffer.Widen(window))).Return([]domain.Reservation{
+					{ID: 1, TimeRange: domain.TimeRange{Start: now.Add(30 * time.Minute), End: now.Add(1 * time.Hour)}},
+					{ID: 2, TimeRange: domain.TimeRange{Start: now.Add(2 * time.Hour), End: now.Add(150 * time.Minute)}},
+				}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.NoError(t, err)
+				// между бронированиями остается 60 - 2*15 минут, это короче duration
+				assert.Equal(t, []domain.TimeRange{
+					{Start: now.Add(165 * time.Minute), End: now.Add(4 * time.Hour)},
+				}, free)
+			},
+		},
+		{
+			name: "validation error time range",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				from:     defaultArgs.to,   // note
+				to:       defaultArgs.from, // note
+				duration: defaultArgs.duration,
+			},
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, free)
+			},
+		},
+		{
+			name: "validation error window too wide",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				from:     defaultArgs.from,
+				to:       defaultArgs.from.Add(domain.MaxAvailabilityWindow + time.Hour), // note
+				duration: defaultArgs.duration,
+			},
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, free)
+			},
+		},
+		{
+			name: "validation error negative duration",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				from:     defaultArgs.from,
+				to:       defaultArgs.to,
+				duration: -time.Minute, // note
+			},
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, free)
+			},
+		},
+		{
+			name: "room not found error",
+			args: defaultArgs,
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(domain.Room{}, domain.ErrRoomNotFound).Times(1) // note
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Nil(t, free)
+			},
+		},
+		{
+			name: "unexpected error from FindOverlapping",
+			args: defaultArgs,
+			buildStubs: func() {
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, free []domain.TimeRange, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, free)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			free, err := service.RoomAvailability(context.Background(), tc.args.roomID, tc.args.from, tc.args.to, tc.args.duration)
+			tc.checkResult(t, free, err)
+		})
+	}
+}
+
+func Test_ReserveRoomBookableHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+	schedules := mock_domain.NewMockScheduleRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, schedules, nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	// 2024-01-01 понедельник
+	monday := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	schedule := domain.RoomSchedule{
+		RoomID:   defaultRoom.ID,
+		Location: time.UTC,
+		Hours:    []domain.OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}},
+	}
+
+	blackout := domain.Blackout{
+		ID:        1,
+		RoomID:    defaultRoom.ID,
+		TimeRange: domain.TimeRange{Start: monday.Add(12 * time.Hour), End: monday.Add(13 * time.Hour)},
+	}
+
+	testCases := []struct {
+		name        string
+		tr          domain.TimeRange
+		buildStubs  func(tr domain.TimeRange)
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name: "OK inside hours",
+			tr:   domain.TimeRange{Start: monday.Add(10 * time.Hour), End: monday.Add(11 * time.Hour)},
+			buildStubs: func(tr domain.TimeRange) {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(schedule, nil).Times(1)
+				schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr)).Return(nil, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Eq(tr), gomock.Eq(domain.ReservationDetails{})).Return(domain.Reservation{ID: 1, RoomID: defaultRoom.ID, TimeRange: tr}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), reservation.ID)
+			},
+		},
+		{
+			name: "error outside opening hours",
+			tr:   domain.TimeRange{Start: monday.Add(17 * time.Hour), End: monday.Add(19 * time.Hour)}, // note
+			buildStubs: func(tr domain.TimeRange) {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(schedule, nil).Times(1)
+				schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				var outside domain.OutsideBookableHoursError
+				assert.ErrorAs(t, err, &outside)
+				assert.Nil(t, outside.Blackout)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "error in blackout",
+			tr:   domain.TimeRange{Start: monday.Add(11 * time.Hour), End: monday.Add(12*time.Hour + 30*time.Minute)}, // note
+			buildStubs: func(tr domain.TimeRange) {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(schedule, nil).Times(1)
+				schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Any(), gomock.Eq(tr)).Return([]domain.Blackout{blackout}, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				var outside domain.OutsideBookableHoursError
+				assert.ErrorAs(t, err, &outside)
+				assert.Equal(t, &blackout.TimeRange, outside.Blackout)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "error from GetSchedule",
+			tr:   domain.TimeRange{Start: monday.Add(10 * time.Hour), End: monday.Add(11 * time.Hour)},
+			buildStubs: func(tr domain.TimeRange) {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Return(domain.RoomSchedule{}, unexpectedError).Times(1) // note
+				schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(tc.tr)
+			reservation, err := service.ReserveRoom(context.Background(), string(defaultRoom.ID), tc.tr.Start, tc.tr.End, domain.ReservationDetails{})
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_RoomAvailabilityBookableHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+	schedules := mock_domain.NewMockScheduleRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, schedules, nil, mock_application.NewMockTransaction(ctrl), NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	// 2024-01-01 понедельник
+	monday := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	window := domain.TimeRange{Start: monday, End: monday.Add(24 * time.Hour)}
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+	schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(domain.RoomSchedule{
+		RoomID:   defaultRoom.ID,
+		Location: time.UTC,
+		Hours: []domain.OpeningHours{
+			{Weekday: time.Monday, Open: 9 * time.Hour, Close: 13 * time.Hour},
+			{Weekday: time.Monday, Open: 14 * time.Hour, Close: 18 * time.Hour},
+		},
+	}, nil).Times(1)
+	schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(window)).Return([]domain.Blackout{
+		{ID: 1, RoomID: defaultRoom.ID, TimeRange: domain.TimeRange{Start: monday.Add(16 * time.Hour), End: monday.Add(20 * time.Hour)}},
+	}, nil).Times(1)
+	repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(window)).Return([]domain.Reservation{
+		{ID: 1, TimeRange: domain.TimeRange{Start: monday.Add(10 * time.Hour), End: monday.Add(11 * time.Hour)}},
+	}, nil).Times(1)
+
+	free, err := service.RoomAvailability(context.Background(), string(defaultRoom.ID), window.Start, window.End, time.Hour)
+	assert.NoError(t, err)
+	// вне часов работы и во время blackout свободных слотов нет
+	assert.Equal(t, []domain.TimeRange{
+		{Start: monday.Add(9 * time.Hour), End: monday.Add(10 * time.Hour)},
+		{Start: monday.Add(11 * time.Hour), End: monday.Add(13 * time.Hour)},
+		{Start: monday.Add(14 * time.Hour), End: monday.Add(16 * time.Hour)},
+	}, free)
+}
+
+func Test_CheckConflicts(t *testing.T) {
+	now := time.Now().Truncate(time.Hour).UTC()
+
+	tr := domain.TimeRange{Start: now, End: now.Add(1 * time.Hour)}
+	buffer := domain.RoomBuffer{After: 15 * time.Minute}
+
+	before := domain.Reservation{ID: 1, TimeRange: domain.TimeRange{Start: now.Add(-1 * time.Hour), End: now}}
+	overlap := domain.Reservation{ID: 2, TimeRange: domain.TimeRange{Start: now.Add(30 * time.Minute), End: now.Add(90 * time.Minute)}}
+
+	testCases := []struct {
+		name         string
+		reservations []domain.Reservation
+		buffer       domain.RoomBuffer
+		ignoreID     int64
+		checkResult  func(t *testing.T, err error)
+	}{
+		{
+			name:         "OK back to back without buffer",
+			reservations: []domain.Reservation{before},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:         "buffer conflict",
+			reservations: []domain.Reservation{before},
+			buffer:       buffer, // note
+			checkResult: func(t *testing.T, err error) {
+				var conflict domain.ReservationConflictError
+				assert.ErrorAs(t, err, &conflict)
+				assert.Equal(t, domain.ConflictBuffer, conflict.Reason)
+				assert.Equal(t, before.TimeRange, conflict.ConflictReservation)
+			},
+		},
+		{
+			name:         "booking conflict preferred over buffer",
+			reservations: []domain.Reservation{before, overlap}, // note
+			buffer:       buffer,
+			checkResult: func(t *testing.T, err error) {
+				var conflict domain.ReservationConflictError
+				assert.ErrorAs(t, err, &conflict)
+				assert.Equal(t, domain.ConflictBooking, conflict.Reason)
+				assert.Equal(t, overlap.TimeRange, conflict.ConflictReservation)
+			},
+		},
+		{
+			name:         "OK ignored reservation",
+			reservations: []domain.Reservation{overlap},
+			buffer:       buffer,
+			ignoreID:     overlap.ID, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkConflicts(tc.reservations, tr, tc.buffer, tc.ignoreID)
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func Test_WithRoomLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	locker := mock_application.NewMockRoomLocker(ctrl)
+
+	service := NewReservationService(nil, nil, nil, nil, txManager, locker, nil, nil)
+
+	unexpectedError := errors.New("unexpected error")
+
+	roomID := domain.RoomID("room")
+
+	type txCtxKey struct{}
+
+	testCases := []struct {
+		name        string
+		do          func(txCtx context.Context) error
+		buildStubs  func(events *[]string)
+		checkResult func(t *testing.T, events []string, err error)
+	}{
+		{
+			name: "OK lock inside transaction, unlock after it",
+			do:   func(txCtx context.Context) error { return nil },
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Eq(reserveTxOptions)).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						*events = append(*events, "begin")
+						err := f(context.WithValue(ctx, txCtxKey{}, true))
+						*events = append(*events, "commit")
+						return err
+					},
+				).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Eq(roomID)).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID) (func(), error) {
+						// advisory lock должен получить контекст с транзакцией
+						if ctx.Value(txCtxKey{}) != nil {
+							*events = append(*events, "lock")
+						}
+						return func() { *events = append(*events, "unlock") }, nil
+					},
+				).Times(1)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"begin", "lock", "commit", "unlock"}, events)
+			},
+		},
+		{
+			name: "OK unlock after failed f",
+			do:   func(txCtx context.Context) error { return unexpectedError }, // note
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).
+					Return(func() { *events = append(*events, "unlock") }, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Equal(t, []string{"unlock"}, events)
+			},
+		},
+		{
+			name: "OK retried transaction releases previous lock",
+			do:   func(txCtx context.Context) error { return nil },
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						// имитация повтора после serialization failure
+						_ = f(ctx)
+						return f(ctx)
+					},
+				).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID) (func(), error) {
+						*events = append(*events, "lock")
+						return func() { *events = append(*events, "unlock") }, nil
+					},
+				).Times(2)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"lock", "unlock", "lock", "unlock"}, events)
+			},
+		},
+		{
+			name: "NOT OK error from Lock",
+			do: func(txCtx context.Context) error {
+				t.Fatal("should not be called without lock")
+				return nil
+			},
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name: "OK in-memory lock taken before transaction",
+			do:   func(txCtx context.Context) error { return nil },
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(false).Times(1) // note
+				c1 := locker.EXPECT().Lock(gomock.Any(), gomock.Eq(roomID)).DoAndReturn(
+					func(ctx context.Context, roomID domain.RoomID) (func(), error) {
+						*events = append(*events, "lock")
+						return func() { *events = append(*events, "unlock") }, nil
+					},
+				).Times(1)
+				c2 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Eq(reserveTxOptions)).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						*events = append(*events, "begin")
+						err := f(ctx)
+						*events = append(*events, "commit")
+						return err
+					},
+				).Times(1)
+
+				c2.After(c1)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				// пока запрос ждет комнату, соединение из пула не занято
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"lock", "begin", "commit", "unlock"}, events)
+			},
+		},
+		{
+			name: "NOT OK in-memory lock error does not open transaction",
+			do: func(txCtx context.Context) error {
+				t.Fatal("should not be called without lock")
+				return nil
+			},
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(false).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name: "NOT OK begin tx deadline exceeded",
+			do: func(txCtx context.Context) error {
+				t.Fatal("should not be called without transaction")
+				return nil
+			},
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(false).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).
+					Return(func() { *events = append(*events, "unlock") }, nil).Times(1)
+				// так BeginTx сообщает, что не дождался соединения из пула
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(context.DeadlineExceeded).Times(1) // note
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.Equal(t, []string{"unlock"}, events)
+			},
+		},
+		{
+			name: "NOT OK begin tx deadline exceeded with advisory lock",
+			do: func(txCtx context.Context) error {
+				t.Fatal("should not be called without transaction")
+				return nil
+			},
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).Times(0)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("begin: %w", context.DeadlineExceeded)).Times(1) // note
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name: "NOT OK deadline exceeded in f is not lock timeout",
+			do:   func(txCtx context.Context) error { return fmt.Errorf("insert: %w", context.DeadlineExceeded) }, // note
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(false).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).
+					Return(func() { *events = append(*events, "unlock") }, nil).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				// запрос мог дойти до базы, повторять его как после 503 нельзя
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.NotErrorIs(t, err, internal.ErrLockTimeout)
+				assert.Equal(t, []string{"unlock"}, events)
+			},
+		},
+		{
+			name: "NOT OK commit deadline exceeded is not lock timeout",
+			do:   func(txCtx context.Context) error { return nil },
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).
+					Return(func() { *events = append(*events, "unlock") }, nil).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						if err := f(ctx); err != nil {
+							return err
+						}
+						return fmt.Errorf("commit: %w", context.DeadlineExceeded) // note
+					},
+				).Times(1)
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.NotErrorIs(t, err, internal.ErrLockTimeout)
+				assert.Equal(t, []string{"unlock"}, events)
+			},
+		},
+		{
+			name: "NOT OK advisory lock timeout",
+			do: func(txCtx context.Context) error {
+				t.Fatal("should not be called without lock")
+				return nil
+			},
+			buildStubs: func(events *[]string) {
+				locker.EXPECT().NeedsTx().Return(true).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				locker.EXPECT().Lock(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: %w", internal.ErrLockTimeout, context.DeadlineExceeded)).Times(1) // note
+			},
+			checkResult: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.Empty(t, events)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			tc.buildStubs(&events)
+			err := service.withRoomLock(context.Background(), roomID, tc.do)
+			tc.checkResult(t, events, err)
+		})
+	}
+}
+
+func Test_GetReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultReservation := domain.Reservation{
+		ID:     1,
+		RoomID: "room",
+		TimeRange: domain.TimeRange{
+			Start: now,
+			End:   now.Add(1 * time.Hour),
+		},
+		Version: 3,
+	}
+
+	testCases := []struct {
+		name        string
+		id          int64
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			id:   defaultReservation.ID,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultReservation, reservation)
+			},
+		},
+		{
+			name: "validation error id",
+			id:   0, // note
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "not found error",
+			id:   defaultReservation.ID,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(domain.Reservation{}, domain.ErrReservationNotFound).Times(1) // note
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "unexpected error from GetByID",
+			id:   defaultReservation.ID,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(domain.Reservation{}, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.GetReservation(context.Background(), tc.id)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_CancelReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+	waitlist := mock_domain.NewMockWaitlistRepository(ctrl)
+	events := mock_application.NewMockEventPublisher(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), waitlist, txManager, NewMutexManager(time.Minute, time.Minute), events, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	unexpectedError := errors.New("unexpected error")
+
+	executeTx := func() *gomock.Call {
+		return txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+	}
+
+	defaultReservation := domain.Reservation{
+		ID:     1,
+		RoomID: "room",
+		TimeRange: domain.TimeRange{
+			Start: now,
+			End:   now.Add(1 * time.Hour),
+		},
+		Version: 2,
+	}
+
+	defaultRoom := domain.Room{
+		ID:       defaultReservation.RoomID,
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	// первый и второй запросы хотят одно и то же время, третий - соседнее
+	waiting := []domain.WaitlistEntry{
+		{ID: 10, RoomID: defaultRoom.ID, TimeRange: defaultReservation.TimeRange, Status: domain.WaitlistWaiting},
+		{ID: 11, RoomID: defaultRoom.ID, TimeRange: defaultReservation.TimeRange, Status: domain.WaitlistWaiting},
+		{ID: 12, RoomID: defaultRoom.ID, TimeRange: domain.TimeRange{Start: now.Add(-1 * time.Hour), End: now}, Status: domain.WaitlistWaiting},
+	}
+	promoted := domain.Reservation{ID: 5, RoomID: defaultRoom.ID, TimeRange: waiting[0].TimeRange, Version: 1, Status: domain.StatusConfirmed}
+	promotedNeighbour := domain.Reservation{ID: 6, RoomID: defaultRoom.ID, TimeRange: waiting[2].TimeRange, Version: 1, Status: domain.StatusConfirmed}
+
+	testCases := []struct {
+		name        string
+		id          int64
+		version     int64
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name:    "OK",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := executeTx()
+				c3 := repo.EXPECT().Delete(gomock.Any(), gomock.Eq(defaultReservation.ID), gomock.Eq(defaultReservation.Version)).
+					Return(nil).Times(1)
+				c4 := rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				c5 := waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(defaultReservation.TimeRange)).
+					Return(nil, nil).Times(1)
+				events.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+				c2.After(c1)
+				c3.After(c2)
+				c4.After(c3)
+				c5.After(c4)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "OK promotes waitlist in queue order",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultReservation, nil).Times(1)
+				executeTx()
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Any(), gomock.Any()).Return(waiting, nil).Times(1) // note
+
+				// первый запрос помещается в освободившийся слот
+				c1 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting[0].TimeRange)).
+					Return(nil, nil).Times(1)
+				c2 := repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting[0].TimeRange), gomock.Eq(domain.ReservationDetails{})).
+					Return(promoted, nil).Times(1)
+				c3 := waitlist.EXPECT().Promote(gomock.Any(), gomock.Eq(waiting[0].ID), gomock.Eq(promoted.ID)).Return(nil).Times(1)
+				// второй уже конфликтует с продвинутым первым
+				c4 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting[1].TimeRange)).
+					Return([]domain.Reservation{promoted}, nil).Times(1)
+				// третий встает вплотную перед ним
+				c5 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting[2].TimeRange)).
+					Return(nil, nil).Times(1)
+				c6 := repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting[2].TimeRange), gomock.Eq(domain.ReservationDetails{})).
+					Return(promotedNeighbour, nil).Times(1)
+				c7 := waitlist.EXPECT().Promote(gomock.Any(), gomock.Eq(waiting[2].ID), gomock.Eq(promotedNeighbour.ID)).Return(nil).Times(1)
+
+				first := waiting[0]
+				first.Status = domain.WaitlistPromoted
+				first.ReservationID = promoted.ID
+				third := waiting[2]
+				third.Status = domain.WaitlistPromoted
+				third.ReservationID = promotedNeighbour.ID
+
+				// события только после commit
+				c8 := events.EXPECT().Publish(gomock.Any(), gomock.Eq(domain.WaitlistPromotedEvent{
+					Entry:       first,
+					Reservation: promoted,
+					CancelledID: defaultReservation.ID,
+				})).Return(nil).Times(1)
+				c9 := events.EXPECT().Publish(gomock.Any(), gomock.Eq(domain.WaitlistPromotedEvent{
+					Entry:       third,
+					Reservation: promotedNeighbour,
+					CancelledID: defaultReservation.ID,
+				})).Return(errors.New("broker is down")).Times(1)
+
+				c2.After(c1)
+				c3.After(c2)
+				c4.After(c3)
+				c5.After(c4)
+				c6.After(c5)
+				c7.After(c6)
+				c8.After(c7)
+				c9.After(c8)
+			},
+			checkResult: func(t *testing.T, err error) {
+				// ошибка публикации не отменяет отмену и продвижение
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "OK inactive room promotes nobody",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				inactive := defaultRoom
+				inactive.Active = false // note
+
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultReservation, nil).Times(1)
+				executeTx()
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(inactive, nil).Times(1)
+				waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "OK skips waitlist entry larger than room",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				small := defaultRoom
+				small.Capacity = 1
+
+				crowded := waiting[0]
+				crowded.Details = domain.ReservationDetails{Attendees: []string{"bob", "carol"}} // note
+
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultReservation, nil).Times(1)
+				executeTx()
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(small, nil).Times(1)
+				waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Any(), gomock.Any()).Return([]domain.WaitlistEntry{crowded}, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				waitlist.EXPECT().Promote(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				events.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "unexpected error from FindWaiting",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultReservation, nil).Times(1)
+				executeTx()
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+				events.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+		{
+			name:    "validation error id",
+			id:      0, // note
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "not found error from GetByID",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(domain.Reservation{}, domain.ErrReservationNotFound).Times(1) // note
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+			},
+		},
+		{
+			name:    "validation error version",
+			id:      defaultReservation.ID,
+			version: 0, // note
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "version mismatch error before lock",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version - 1, // note
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+			},
+		},
+		{
+			name:    "version mismatch error from Delete",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := executeTx()
+				// версия изменилась между чтением и удалением
+				c3 := repo.EXPECT().Delete(gomock.Any(), gomock.Eq(defaultReservation.ID), gomock.Eq(defaultReservation.Version)).
+					Return(domain.ErrReservationVersionMismatch).Times(1) // note
+
+				c2.After(c1)
+				c3.After(c2)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+			},
+		},
+		{
+			name:    "unexpected error from Delete",
+			id:      defaultReservation.ID,
+			version: defaultReservation.Version,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := executeTx()
+				c3 := repo.EXPECT().Delete(gomock.Any(), gomock.Eq(defaultReservation.ID), gomock.Eq(defaultReservation.Version)).
+					Return(unexpectedError).Times(1) // note
+
+				c2.After(c1)
+				c3.After(c2)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			err := service.CancelReservation(context.Background(), tc.id, tc.version)
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func Test_RescheduleReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultReservation := domain.Reservation{
+		ID:     1,
+		RoomID: "room",
+		TimeRange: domain.TimeRange{
+			Start: now,
+			End:   now.Add(1 * time.Hour),
+		},
+		Version: 2,
+	}
+
+	defaultRoom := domain.Room{
+		ID:       defaultReservation.RoomID,
+		Name:     string(defaultReservation.RoomID),
+		Capacity: 10,
+		Active:   true,
+	}
+
+	type args struct {
+		id       int64
+		from, to time.Time
+		version  int64
+	}
+
+	// перенос на полчаса вперед пересекается со старым временем самого бронирования
+	defaultArgs := args{
+		id:      defaultReservation.ID,
+		from:    now.Add(30 * time.Minute),
+		to:      now.Add(90 * time.Minute),
+		version: defaultReservation.Version,
+	}
+	newTimeRange := domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}
+
+	executeInTx := func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+		return f(ctx)
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(executeInTx).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.RoomID)).
+					Return(defaultRoom, nil).Times(1)
+				c3 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultReservation.RoomID), gomock.Eq(newTimeRange)).
+					Return([]domain.Reservation{defaultReservation}, nil).Times(1)
+				c4 := repo.EXPECT().Update(gomock.Any(), gomock.Eq(defaultReservation.ID), gomock.Eq(newTimeRange), gomock.Eq(defaultReservation.Version)).
+					Return(defaultReservation.Version+1, nil).Times(1)
+
+				c2.After(c1)
+				c3.After(c2)
+				c4.After(c3)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.Reservation{
+					ID:        defaultReservation.ID,
+					RoomID:    defaultReservation.RoomID,
+					TimeRange: newTimeRange,
+					Version:   defaultReservation.Version + 1,
+				}, reservation)
+			},
+		},
+		{
+			name: "validation error id",
+			args: args{
+				id:      0, // note
+				from:    defaultArgs.from,
+				to:      defaultArgs.to,
+				version: defaultArgs.version,
+			},
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "validation error time range",
+			args: args{
+				id:      defaultArgs.id,
+				from:    defaultArgs.to,   // note
+				to:      defaultArgs.from, // note
+				version: defaultArgs.version,
+			},
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "not found error from GetByID",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(domain.Reservation{}, domain.ErrReservationNotFound).Times(1) // note
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "room inactive error",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(executeInTx).Times(1)
+
+				inactiveRoom := defaultRoom
+				inactiveRoom.Active = false // note
+
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.RoomID)).
+					Return(inactiveRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				c2.After(c1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrRoomInactive)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "reservation time range conflict error",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(executeInTx).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.RoomID)).
+					Return(defaultRoom, nil).Times(1)
+				c3 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultReservation.RoomID), gomock.Eq(newTimeRange)).
+					Return([]domain.Reservation{
+						defaultReservation,
+						{
+							ID:     2, // note
+							RoomID: defaultReservation.RoomID,
+							TimeRange: domain.TimeRange{
+								Start: defaultReservation.TimeRange.End,
+								End:   defaultReservation.TimeRange.End.Add(1 * time.Hour),
+							},
+						},
+					}, nil).Times(1)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				c2.After(c1)
+				c3.After(c2)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "version mismatch error before lock",
+			args: args{
+				id:      defaultArgs.id,
+				from:    defaultArgs.from,
+				to:      defaultArgs.to,
+				version: defaultArgs.version + 1, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "version mismatch error from Update",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(executeInTx).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.RoomID)).
+					Return(defaultRoom, nil).Times(1)
+				c3 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultReservation.RoomID), gomock.Eq(newTimeRange)).
+					Return(nil, nil).Times(1)
+				// версия изменилась между чтением и обновлением
+				c4 := repo.EXPECT().Update(gomock.Any(), gomock.Eq(defaultReservation.ID), gomock.Eq(newTimeRange), gomock.Eq(defaultReservation.Version)).
+					Return(int64(0), domain.ErrReservationVersionMismatch).Times(1) // note
+
+				c2.After(c1)
+				c3.After(c2)
+				c4.After(c3)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "unexpected error from Update",
+			args: defaultArgs,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.ID)).
+					Return(defaultReservation, nil).Times(1)
+				c2 := txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(executeInTx).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultReservation.RoomID)).
+					Return(defaultRoom, nil).Times(1)
+				c3 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultReservation.RoomID), gomock.Eq(newTimeRange)).
+					Return(nil, nil).Times(1)
+				c4 := repo.EXPECT().Update(gomock.Any(), gomock.Eq(defaultReservation.ID), gomock.Eq(newTimeRange), gomock.Eq(defaultReservation.Version)).
+					Return(int64(0), unexpectedError).Times(1) // note
+
+				c2.After(c1)
+				c3.After(c2)
+				c4.After(c3)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.RescheduleReservation(context.Background(), tc.args.id, tc.args.from, tc.args.to, tc.args.version)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_HoldRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+	expiresAt := now.Add(10 * time.Minute)
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	tr := domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+
+	held := domain.Reservation{
+		ID:        1,
+		RoomID:    defaultRoom.ID,
+		TimeRange: tr,
+		Version:   1,
+		Status:    domain.StatusHeld,
+		ExpiresAt: &expiresAt,
+	}
+
+	testCases := []struct {
+		name        string
+		ttl         time.Duration
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			ttl:  10 * time.Minute,
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr)).Return(nil, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateHold(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr), gomock.Eq(domain.ReservationDetails{}), gomock.Eq(10*time.Minute)).
+					Return(held, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, held, reservation)
+			},
+		},
+		{
+			name: "error conflict with other hold",
+			ttl:  10 * time.Minute,
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]domain.Reservation{held}, nil).Times(1) // note
+				repo.EXPECT().CreateHold(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name: "validation error ttl",
+			ttl:  domain.MaxHoldTTL + time.Minute, // note
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateHold(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.HoldRoom(context.Background(), string(defaultRoom.ID), tr.Start, tr.End, domain.ReservationDetails{}, tc.ttl)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_WaitlistRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+	waitlist := mock_domain.NewMockWaitlistRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), waitlist, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	tr := domain.TimeRange{Start: now, End: now.Add(time.Hour)}
+
+	created := domain.Reservation{ID: 1, RoomID: defaultRoom.ID, TimeRange: tr, Version: 1, Status: domain.StatusConfirmed}
+	existing := domain.Reservation{ID: 2, RoomID: defaultRoom.ID, TimeRange: tr, Version: 1, Status: domain.StatusConfirmed}
+	entry := domain.WaitlistEntry{ID: 3, RoomID: defaultRoom.ID, TimeRange: tr, Status: domain.WaitlistWaiting, CreatedAt: now}
+
+	executeTx := func() {
+		txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, entry domain.WaitlistEntry, err error)
+	}{
+		{
+			name: "OK slot is free",
+			buildStubs: func() {
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr)).Return(nil, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr), gomock.Eq(domain.ReservationDetails{})).Return(created, nil).Times(1)
+				waitlist.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, waiting domain.WaitlistEntry, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, created, reservation)
+				assert.Empty(t, waiting)
+			},
+		},
+		{
+			name: "OK conflict puts request on waitlist",
+			buildStubs: func() {
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]domain.Reservation{existing}, nil).Times(1) // note
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				waitlist.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr), gomock.Eq(domain.ReservationDetails{})).Return(entry, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, waiting domain.WaitlistEntry, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, reservation)
+				assert.Equal(t, entry, waiting)
+			},
+		},
+		{
+			name: "error room inactive is not waitlisted",
+			buildStubs: func() {
+				inactive := defaultRoom
+				inactive.Active = false // note
+
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(inactive, nil).Times(1)
+				waitlist.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, waiting domain.WaitlistEntry, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomInactive)
+				assert.Empty(t, reservation)
+				assert.Empty(t, waiting)
+			},
+		},
+		{
+			name: "error from waitlist Create",
+			buildStubs: func() {
+				executeTx()
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(1)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]domain.Reservation{existing}, nil).Times(1)
+				waitlist.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.WaitlistEntry{}, domain.ErrRoomNotFound).Times(1) // note
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, waiting domain.WaitlistEntry, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, reservation)
+				assert.Empty(t, waiting)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, entry, err := service.WaitlistRoom(context.Background(), string(defaultRoom.ID), tr.Start, tr.End, domain.ReservationDetails{})
+			tc.checkResult(t, reservation, entry, err)
+		})
+	}
+
+	t.Run("OK retried transaction drops waitlist entry of rolled back attempt", func(t *testing.T) {
+		txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				// первая попытка встала в очередь, но упала на serialization failure,
+				// во второй слот уже свободен
+				_ = f(ctx)
+				return f(ctx)
+			},
+		).Times(1)
+		rooms.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(defaultRoom, nil).Times(2)
+		first := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]domain.Reservation{existing}, nil).Times(1)
+		repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil).Times(1).After(first)
+		waitlist.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(entry, nil).Times(1)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(created, nil).Times(1)
+
+		reservation, waiting, err := service.WaitlistRoom(context.Background(), string(defaultRoom.ID), tr.Start, tr.End, domain.ReservationDetails{})
+		assert.NoError(t, err)
+		assert.Equal(t, created, reservation)
+		assert.Empty(t, waiting)
+	})
+
+	waitlist.EXPECT().GetByID(gomock.Any(), entry.ID).Return(entry, nil).Times(1)
+	got, err := service.GetWaitlistEntry(context.Background(), entry.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entry, got)
+
+	_, err = service.GetWaitlistEntry(context.Background(), 0)
+	assert.ErrorIs(t, err, internal.ErrValidationFailed)
+}
+
+func Test_ReapExpiredHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+	waitlist := mock_domain.NewMockWaitlistRepository(ctrl)
+	events := mock_application.NewMockEventPublisher(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), waitlist, txManager, NewMutexManager(time.Minute, time.Minute), events, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	unexpectedError := errors.New("unexpected error")
+
+	executeTx := func() *gomock.Call {
+		return txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+	}
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	expiresAt := now.Add(-1 * time.Minute)
+	expired := domain.Reservation{
+		ID:        1,
+		RoomID:    defaultRoom.ID,
+		TimeRange: domain.TimeRange{Start: now.Add(1 * time.Hour), End: now.Add(2 * time.Hour)},
+		Version:   1,
+		Status:    domain.StatusHeld,
+		ExpiresAt: &expiresAt,
+	}
+
+	waiting := domain.WaitlistEntry{ID: 10, RoomID: defaultRoom.ID, TimeRange: expired.TimeRange, Status: domain.WaitlistWaiting}
+	promoted := domain.Reservation{ID: 5, RoomID: defaultRoom.ID, TimeRange: waiting.TimeRange, Version: 1, Status: domain.StatusConfirmed}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, reaped int64, err error)
+	}{
+		{
+			name: "OK promotes waitlist into expired hold slot",
+			buildStubs: func() {
+				c1 := repo.EXPECT().ListExpiredHoldRooms(gomock.Any()).Return([]domain.RoomID{defaultRoom.ID}, nil).Times(1)
+				c2 := executeTx()
+				c3 := repo.EXPECT().DeleteExpiredHolds(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return([]domain.Reservation{expired}, nil).Times(1)
+				c4 := rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				c5 := waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(expired.TimeRange)).
+					Return([]domain.WaitlistEntry{waiting}, nil).Times(1)
+				c6 := repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting.TimeRange)).Return(nil, nil).Times(1)
+				c7 := repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(waiting.TimeRange), gomock.Eq(domain.ReservationDetails{})).
+					Return(promoted, nil).Times(1)
+				c8 := waitlist.EXPECT().Promote(gomock.Any(), gomock.Eq(waiting.ID), gomock.Eq(promoted.ID)).Return(nil).Times(1)
+
+				entry := waiting
+				entry.Status = domain.WaitlistPromoted
+				entry.ReservationID = promoted.ID
+				c9 := events.EXPECT().Publish(gomock.Any(), gomock.Eq(domain.WaitlistPromotedEvent{
+					Entry:       entry,
+					Reservation: promoted,
+					CancelledID: expired.ID,
+				})).Return(nil).Times(1)
+
+				c2.After(c1)
+				c3.After(c2)
+				c4.After(c3)
+				c5.After(c4)
+				c6.After(c5)
+				c7.After(c6)
+				c8.After(c7)
+				c9.After(c8)
+			},
+			checkResult: func(t *testing.T, reaped int64, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), reaped)
+			},
+		},
+		{
+			name: "OK nothing expired",
+			buildStubs: func() {
+				repo.EXPECT().ListExpiredHoldRooms(gomock.Any()).Return(nil, nil).Times(1) // note
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().DeleteExpiredHolds(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reaped int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, reaped)
+			},
+		},
+		{
+			name: "NOT OK error in one room does not stop others",
+			buildStubs: func() {
+				repo.EXPECT().ListExpiredHoldRooms(gomock.Any()).Return([]domain.RoomID{"broken", defaultRoom.ID}, nil).Times(1)
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(2)
+				repo.EXPECT().DeleteExpiredHolds(gomock.Any(), gomock.Eq(domain.RoomID("broken"))).Return(nil, unexpectedError).Times(1) // note
+				repo.EXPECT().DeleteExpiredHolds(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return([]domain.Reservation{expired}, nil).Times(1)
+				rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+				waitlist.EXPECT().FindWaiting(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				events.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reaped int64, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Equal(t, int64(1), reaped)
+			},
+		},
+		{
+			name: "NOT OK error from ListExpiredHoldRooms",
+			buildStubs: func() {
+				repo.EXPECT().ListExpiredHoldRooms(gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reaped int64, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Zero(t, reaped)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reaped, err := service.ReapExpiredHolds(context.Background())
+			tc.checkResult(t, reaped, err)
+		})
+	}
+}
+
+func Test_ConfirmReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+
+	service := NewReservationService(repo, nil, nil, nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+	expiresAt := now.Add(10 * time.Minute)
+
+	unexpectedError := errors.New("unexpected error")
+
+	executeTx := func() *gomock.Call {
+		return txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+	}
+
+	held := domain.Reservation{
+		ID:        1,
+		RoomID:    "room",
+		TimeRange: domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+		Version:   2,
+		Status:    domain.StatusHeld,
+		ExpiresAt: &expiresAt,
+	}
+
+	testCases := []struct {
+		name        string
+		id          int64
+		version     int64
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name:    "OK",
+			id:      held.ID,
+			version: held.Version,
+			buildStubs: func() {
+				c1 := repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(held.ID)).Return(held, nil).Times(1)
+				c2 := executeTx()
+				c3 := repo.EXPECT().Confirm(gomock.Any(), gomock.Eq(held.ID), gomock.Eq(held.Version)).Return(int64(3), nil).Times(1)
+
+				c2.After(c1)
+				c3.After(c2)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.Reservation{
+					ID:        held.ID,
+					RoomID:    held.RoomID,
+					TimeRange: held.TimeRange,
+					Version:   3,
+					Status:    domain.StatusConfirmed,
+				}, reservation)
+			},
+		},
+		{
+			name:    "validation error version",
+			id:      held.ID,
+			version: 0, // note
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Confirm(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "version mismatch error",
+			id:      held.ID,
+			version: held.Version - 1, // note
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(held, nil).Times(1)
+				repo.EXPECT().Confirm(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "not held error",
+			id:      held.ID,
+			version: held.Version,
+			buildStubs: func() {
+				confirmed := held
+				confirmed.Status = domain.StatusConfirmed // note
+				confirmed.ExpiresAt = nil
+
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(confirmed, nil).Times(1)
+				repo.EXPECT().Confirm(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationNotHeld)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "hold expired error from Confirm",
+			id:      held.ID,
+			version: held.Version,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(held, nil).Times(1)
+				executeTx()
+				repo.EXPECT().Confirm(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), domain.ErrHoldExpired).Times(1) // note
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, domain.ErrHoldExpired)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "unexpected error from GetByID",
+			id:      held.ID,
+			version: held.Version,
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(domain.Reservation{}, unexpectedError).Times(1) // note
+				repo.EXPECT().Confirm(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.ConfirmReservation(context.Background(), tc.id, tc.version)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_ReserveRoomPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	policy := domain.BookingPolicies{
+		domain.NoPastBookings{},
+		domain.MinDuration(15 * time.Minute),
+		domain.OwnerQuota(2),
+	}
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, policy)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	alice := domain.ReservationDetails{Owner: "alice"}
+
+	tr := domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+	created := domain.Reservation{ID: 1, RoomID: defaultRoom.ID, TimeRange: tr, Version: 1, Status: domain.StatusConfirmed, Details: alice}
+
+	unexpectedError := errors.New("unexpected error")
+
+	executeTx := func() {
+		txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+		rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+	}
+
+	testCases := []struct {
+		name        string
+		tr          domain.TimeRange
+		details     domain.ReservationDetails
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name:    "OK",
+			tr:      tr,
+			details: alice,
+			buildStubs: func() {
+				executeTx()
+				lock := repo.EXPECT().LockOwner(gomock.Any(), gomock.Eq("alice")).Return(nil).Times(1)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Eq("alice"), gomock.Eq(int64(0))).Return(1, nil).Times(1).After(lock)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr)).Return(nil, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr), gomock.Eq(alice)).Return(created, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, created, reservation)
+			},
+		},
+		{
+			name:    "error owner quota exhausted",
+			tr:      tr,
+			details: alice,
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().LockOwner(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Any(), gomock.Any()).Return(2, nil).Times(1) // note
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				var violation domain.PolicyViolationError
+				assert.ErrorAs(t, err, &violation)
+				assert.Equal(t, []domain.PolicyViolation{
+					domain.OwnerQuotaViolation{Owner: "alice", Limit: 2, Active: 2},
+				}, violation.Violations)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "error all violations without owner",
+			tr:      domain.TimeRange{Start: now.Add(-time.Hour), End: now.Add(-time.Hour + 5*time.Minute)}, // note
+			details: domain.ReservationDetails{},
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().LockOwner(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				var violation domain.PolicyViolationError
+				assert.ErrorAs(t, err, &violation)
+				assert.Len(t, violation.Violations, 2)
+				assert.Equal(t, "no_past_bookings", violation.Violations[0].Rule())
+				assert.Equal(t, "min_duration", violation.Violations[1].Rule())
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "error count active unexpected",
+			tr:      tr,
+			details: alice,
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().LockOwner(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, unexpectedError).Times(1) // note
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "error owner lock timeout",
+			tr:      tr,
+			details: alice,
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().LockOwner(gomock.Any(), gomock.Any()).Return(fmt.Errorf("%w: %w", internal.ErrLockTimeout, context.DeadlineExceeded)).Times(1) // note
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.Empty(t, reservation)
+			},
+		},
+		{
+			name:    "validation error owner too long",
+			tr:      tr,
+			details: domain.ReservationDetails{Owner: strings.Repeat("a", 129)}, // note
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.ReserveRoom(context.Background(), string(defaultRoom.ID), tc.tr.Start, tc.tr.End, tc.details)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_RescheduleReservationPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, domain.OwnerQuota(1))
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	reservation := domain.Reservation{
+		ID:        5,
+		RoomID:    defaultRoom.ID,
+		TimeRange: domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+		Version:   1,
+		Status:    domain.StatusConfirmed,
+		Details:   domain.ReservationDetails{Owner: "alice"},
+	}
+	tr := domain.TimeRange{Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour)}
+
+	txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+			return f(ctx)
+		},
+	).Times(1)
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(reservation.ID)).Return(reservation, nil).Times(1)
+	rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+	repo.EXPECT().LockOwner(gomock.Any(), gomock.Eq("alice")).Return(nil).Times(1)
+	// само переносимое бронирование в квоту не входит
+	repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Eq("alice"), gomock.Eq(reservation.ID)).Return(0, nil).Times(1)
+	repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr)).Return([]domain.Reservation{reservation}, nil).Times(1)
+	repo.EXPECT().Update(gomock.Any(), gomock.Eq(reservation.ID), gomock.Eq(tr), gomock.Eq(int64(1))).Return(int64(2), nil).Times(1)
+
+	rescheduled, err := service.RescheduleReservation(context.Background(), reservation.ID, tr.Start, tr.End, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, tr, rescheduled.TimeRange)
+	assert.Equal(t, int64(2), rescheduled.Version)
+}
+
+func Test_ReserveRecurringPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, domain.OwnerQuota(3))
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	alice := domain.ReservationDetails{Owner: "alice"}
+	rule := "FREQ=WEEKLY;COUNT=2"
+
+	tr := domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+	occurrences := []domain.TimeRange{tr, {Start: tr.Start.AddDate(0, 0, 7), End: tr.End.AddDate(0, 0, 7)}}
+
+	executeTx := func() {
+		txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+		rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+	}
+
+	testCases := []struct {
+		name        string
+		details     domain.ReservationDetails
+		buildStubs  func()
+		checkResult func(t *testing.T, series domain.ReservationSeries, err error)
+	}{
+		{
+			name:    "OK every occurrence fits in quota",
+			details: alice,
+			buildStubs: func() {
+				executeTx()
+				lock := repo.EXPECT().LockOwner(gomock.Any(), gomock.Eq("alice")).Return(nil).Times(1)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Eq("alice"), gomock.Eq(int64(0))).Return(1, nil).Times(1).After(lock)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(rule), gomock.Eq(occurrences), gomock.Eq(alice)).
+					Return(domain.ReservationSeries{ID: 1}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.ReservationSeries{ID: 1}, series)
+			},
+		},
+		{
+			name:    "error owner quota exhausted by later occurrence",
+			details: alice,
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().LockOwner(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Any(), gomock.Any()).Return(2, nil).Times(1) // note
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				var violation domain.PolicyViolationError
+				assert.ErrorAs(t, err, &violation)
+				// первое вхождение еще помещается, второе уже нет
+				assert.Equal(t, []domain.PolicyViolation{
+					domain.OwnerQuotaViolation{Owner: "alice", Limit: 3, Active: 3},
+				}, violation.Violations)
+				assert.Empty(t, series)
+			},
+		},
+		{
+			name:    "OK series without owner",
+			details: domain.ReservationDetails{}, // note
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().LockOwner(gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().CountActiveByOwner(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.ReservationSeries{ID: 2}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, series domain.ReservationSeries, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.ReservationSeries{ID: 2}, series)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			series, err := service.ReserveRecurring(context.Background(), string(defaultRoom.ID), tr.Start, tr.End, tc.details, rule)
+			tc.checkResult(t, series, err)
+		})
+	}
+}
+
+func Test_ReserveRoomCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockReservationRepository(ctrl)
+	rooms := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewReservationService(repo, rooms, alwaysOpen(ctrl), nil, txManager, NewMutexManager(time.Minute, time.Minute), nil, nil)
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultRoom := domain.Room{
+		ID:       "room",
+		Name:     "room",
+		Capacity: 2,
+		Active:   true,
+	}
+
+	tr := domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+
+	full := domain.ReservationDetails{Attendees: []string{"bob", "carol"}}
+	created := domain.Reservation{ID: 1, RoomID: defaultRoom.ID, TimeRange: tr, Version: 1, Status: domain.StatusConfirmed, Details: full}
+
+	executeTx := func() {
+		txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+				return f(ctx)
+			},
+		).Times(1)
+		rooms.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Return(defaultRoom, nil).Times(1)
+	}
+
+	testCases := []struct {
+		name        string
+		details     domain.ReservationDetails
+		buildStubs  func()
+		checkResult func(t *testing.T, reservation domain.Reservation, err error)
+	}{
+		{
+			name:    "OK exactly full room",
+			details: full,
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr)).Return(nil, nil).Times(1)
+				repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom.ID), gomock.Eq(tr), gomock.Eq(full)).Return(created, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, created, reservation)
+			},
+		},
+		{
+			name:    "error capacity exceeded",
+			details: domain.ReservationDetails{Attendees: []string{"bob", "carol", "dave"}}, // note
+			buildStubs: func() {
+				executeTx()
+				repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, reservation domain.Reservation, err error) {
+				var exceeded domain.CapacityExceededError
+				assert.ErrorAs(t, err, &exceeded)
+				assert.Equal(t, domain.CapacityExceededError{RoomID: defaultRoom.ID, Capacity: 2, Attendees: 3}, exceeded)
+				assert.Empty(t, reservation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := service.ReserveRoom(context.Background(), string(defaultRoom.ID), tr.Start, tr.End, tc.details)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+
+	t.Run("error capacity exceeded series", func(t *testing.T) {
+		executeTx()
+		repo.EXPECT().FindOverlapping(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		series, err := service.ReserveRecurring(context.Background(), string(defaultRoom.ID), tr.Start, tr.End,
+			domain.ReservationDetails{Attendees: []string{"bob", "carol", "dave"}}, "FREQ=WEEKLY;COUNT=2")
+
+		var exceeded domain.CapacityExceededError
+		assert.ErrorAs(t, err, &exceeded)
+		assert.Equal(t, domain.CapacityExceededError{RoomID: defaultRoom.ID, Capacity: 2, Attendees: 3}, exceeded)
+		assert.Empty(t, series)
+	})
+}
+
+// alwaysOpen расписание комнаты без часов работы и blackout периодов,
+// для тестов, которые не проверяют часы работы
+func alwaysOpen(ctrl *gomock.Controller) *mock_domain.MockScheduleRepository {
+	schedules := mock_domain.NewMockScheduleRepository(ctrl)
+	schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, roomID domain.RoomID) (domain.RoomSchedule, error) {
+			return domain.RoomSchedule{RoomID: roomID, Location: time.UTC}, nil
+		}).AnyTimes()
+	schedules.EXPECT().FindBlackouts(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return schedules
 }
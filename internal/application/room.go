@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type roomService struct {
+	repo      domain.RoomRepository
+	schedules domain.ScheduleRepository
+}
+
+func NewRoomService(repo domain.RoomRepository, schedules domain.ScheduleRepository) *roomService {
+	return &roomService{
+		repo:      repo,
+		schedules: schedules,
+	}
+}
+
+func (s roomService) CreateRoom(ctx context.Context, roomID, name string, capacity int, location string, bufferBefore, bufferAfter time.Duration) (domain.Room, error) {
+	buffer, err := domain.NewRoomBuffer(bufferBefore, bufferAfter)
+	if err != nil {
+		return domain.Room{}, err
+	}
+	room, err := domain.NewRoom(roomID, name, capacity, location, true, buffer)
+	if err != nil {
+		return domain.Room{}, err
+	}
+
+	return s.repo.Create(ctx, room)
+}
+
+func (s roomService) GetRoom(ctx context.Context, roomID string) (domain.Room, error) {
+	rid, err := domain.NewRoomID(roomID)
+	if err != nil {
+		return domain.Room{}, err
+	}
+
+	return s.repo.GetByID(ctx, rid)
+}
+
+func (s roomService) ListRooms(ctx context.Context) ([]domain.Room, error) {
+	return s.repo.List(ctx)
+}
+
+// FindAvailableRooms активные комнаты, свободные на всем отрезке [from, to)
+// и работающие на нем по своему расписанию
+func (s roomService) FindAvailableRooms(ctx context.Context, from, to time.Time, minCapacity int) ([]domain.Room, error) {
+	tr, err := domain.NewTimeRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if minCapacity < 0 {
+		return nil, fmt.Errorf("FindAvailableRooms: min capacity should not be negative: %w", internal.ErrValidationFailed)
+	}
+
+	rooms, err := s.repo.ListAvailable(ctx, tr, minCapacity)
+	if err != nil {
+		return nil, err
+	}
+
+	// часы работы зависят от пояса комнаты и перевода часов, поэтому проверяются
+	// тем же RoomSchedule.Covers, что и при бронировании, а не в запросе.
+	// Расписания всех комнат забираются одним запросом, а не по комнате
+	roomIDs := make([]domain.RoomID, 0, len(rooms))
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	schedules, err := s.schedules.ListSchedules(ctx, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]domain.Room, 0, len(rooms))
+	for _, room := range rooms {
+		// комнату могли удалить между запросами
+		schedule, ok := schedules[room.ID]
+		if ok && schedule.Covers(tr) {
+			available = append(available, room)
+		}
+	}
+
+	return available, nil
+}
+
+func (s roomService) UpdateRoom(ctx context.Context, roomID, name string, capacity int, location string, active bool, bufferBefore, bufferAfter time.Duration) (domain.Room, error) {
+	buffer, err := domain.NewRoomBuffer(bufferBefore, bufferAfter)
+	if err != nil {
+		return domain.Room{}, err
+	}
+	room, err := domain.NewRoom(roomID, name, capacity, location, active, buffer)
+	if err != nil {
+		return domain.Room{}, err
+	}
+
+	return s.repo.Update(ctx, room)
+}
+
+func (s roomService) DeleteRoom(ctx context.Context, roomID string) error {
+	rid, err := domain.NewRoomID(roomID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, rid)
+}
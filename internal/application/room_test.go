@@ -0,0 +1,516 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_domain "github.com/ynuraddi/test-kami/internal/domain/mock"
+)
+
+func Test_CreateRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewRoomService(repo, mock_domain.NewMockScheduleRepository(ctrl))
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Location: "2nd floor",
+		Active:   true,
+		Buffer:   domain.RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute},
+	}
+
+	testCases := []struct {
+		name        string
+		room        domain.Room
+		buildStubs  func()
+		checkResult func(t *testing.T, room domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			room: defaultRoom,
+			buildStubs: func() {
+				repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom)).Times(1).Return(defaultRoom, nil)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRoom, room)
+			},
+		},
+		{
+			name: "validation error capacity",
+			room: domain.Room{
+				ID:       defaultRoom.ID,
+				Name:     defaultRoom.Name,
+				Capacity: 0, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "validation error buffer",
+			room: domain.Room{
+				ID:       defaultRoom.ID,
+				Name:     defaultRoom.Name,
+				Capacity: defaultRoom.Capacity,
+				Buffer:   domain.RoomBuffer{Before: -time.Minute}, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "already exists error from Create",
+			room: defaultRoom,
+			buildStubs: func() {
+				repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom)).Times(1).Return(domain.Room{}, domain.ErrRoomAlreadyExists)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomAlreadyExists)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "unexpected error from Create",
+			room: defaultRoom,
+			buildStubs: func() {
+				repo.EXPECT().Create(gomock.Any(), gomock.Eq(defaultRoom)).Times(1).Return(domain.Room{}, unexpectedError)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			room, err := service.CreateRoom(context.Background(), string(tc.room.ID), tc.room.Name, tc.room.Capacity, tc.room.Location,
+				tc.room.Buffer.Before, tc.room.Buffer.After)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_GetRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewRoomService(repo, mock_domain.NewMockScheduleRepository(ctrl))
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	testCases := []struct {
+		name        string
+		roomID      string
+		buildStubs  func()
+		checkResult func(t *testing.T, room domain.Room, err error)
+	}{
+		{
+			name:   "OK",
+			roomID: string(defaultRoom.ID),
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Times(1).Return(defaultRoom, nil)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRoom, room)
+			},
+		},
+		{
+			name:   "validation error room id",
+			roomID: "", // note
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name:   "not found error from GetByID",
+			roomID: string(defaultRoom.ID),
+			buildStubs: func() {
+				repo.EXPECT().GetByID(gomock.Any(), gomock.Eq(defaultRoom.ID)).Times(1).Return(domain.Room{}, domain.ErrRoomNotFound)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			room, err := service.GetRoom(context.Background(), tc.roomID)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_FindAvailableRooms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockRoomRepository(ctrl)
+	schedules := mock_domain.NewMockScheduleRepository(ctrl)
+
+	service := NewRoomService(repo, schedules)
+
+	unexpectedError := errors.New("unexpected error")
+
+	// понедельник, часы работы ниже зависят от дня недели
+	now := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	type args struct {
+		from, to    time.Time
+		minCapacity int
+	}
+
+	defaultArgs := args{
+		from:        now,
+		to:          now.Add(1 * time.Hour),
+		minCapacity: 5,
+	}
+
+	defaultRooms := []domain.Room{
+		{ID: "conf-a", Name: "Conference A", Capacity: 10, Active: true},
+	}
+
+	openSchedule := domain.RoomSchedule{
+		RoomID:   "conf-a",
+		Location: time.UTC,
+		Hours:    []domain.OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}},
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, rooms []domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(
+					gomock.Any(),
+					gomock.Eq(domain.TimeRange{Start: defaultArgs.from, End: defaultArgs.to}),
+					gomock.Eq(defaultArgs.minCapacity),
+				).Return(defaultRooms, nil).Times(1)
+				schedules.EXPECT().ListSchedules(gomock.Any(), gomock.Eq([]domain.RoomID{"conf-a"})).
+					Return(map[domain.RoomID]domain.RoomSchedule{"conf-a": openSchedule}, nil).Times(1)
+				schedules.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRooms, rooms)
+			},
+		},
+		{
+			name: "OK room closed by schedule is skipped",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(append(defaultRooms, domain.Room{ID: "conf-b", Name: "Conference B", Capacity: 10, Active: true}), nil).Times(1)
+				schedules.EXPECT().ListSchedules(gomock.Any(), gomock.Eq([]domain.RoomID{"conf-a", "conf-b"})).
+					Return(map[domain.RoomID]domain.RoomSchedule{
+						"conf-a": openSchedule,
+						"conf-b": {
+							RoomID:   "conf-b",
+							Location: time.UTC,
+							Hours:    []domain.OpeningHours{{Weekday: time.Monday, Open: 14 * time.Hour, Close: 18 * time.Hour}}, // note
+						},
+					}, nil).Times(1)
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRooms, rooms)
+			},
+		},
+		{
+			name: "OK room without opening hours is always open",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).Return(defaultRooms, nil).Times(1)
+				schedules.EXPECT().ListSchedules(gomock.Any(), gomock.Any()).
+					Return(map[domain.RoomID]domain.RoomSchedule{"conf-a": {RoomID: "conf-a"}}, nil).Times(1) // note
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRooms, rooms)
+			},
+		},
+		{
+			name: "OK room deleted after ListAvailable is skipped",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).Return(defaultRooms, nil).Times(1)
+				schedules.EXPECT().ListSchedules(gomock.Any(), gomock.Any()).
+					Return(map[domain.RoomID]domain.RoomSchedule{}, nil).Times(1) // note
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, rooms)
+			},
+		},
+		{
+			name: "validation error time range",
+			args: args{
+				from:        defaultArgs.to,   // note
+				to:          defaultArgs.from, // note
+				minCapacity: defaultArgs.minCapacity,
+			},
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, rooms)
+			},
+		},
+		{
+			name: "validation error negative capacity",
+			args: args{
+				from:        defaultArgs.from,
+				to:          defaultArgs.to,
+				minCapacity: -1, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, rooms)
+			},
+		},
+		{
+			name: "unexpected error from ListAvailable",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+				schedules.EXPECT().ListSchedules(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, rooms)
+			},
+		},
+		{
+			name: "unexpected error from ListSchedules",
+			args: defaultArgs,
+			buildStubs: func() {
+				repo.EXPECT().ListAvailable(gomock.Any(), gomock.Any(), gomock.Any()).Return(defaultRooms, nil).Times(1)
+				schedules.EXPECT().ListSchedules(gomock.Any(), gomock.Any()).Return(nil, unexpectedError).Times(1) // note
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, rooms)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			rooms, err := service.FindAvailableRooms(context.Background(), tc.args.from, tc.args.to, tc.args.minCapacity)
+			tc.checkResult(t, rooms, err)
+		})
+	}
+}
+
+func Test_UpdateRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewRoomService(repo, mock_domain.NewMockScheduleRepository(ctrl))
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   false,
+	}
+
+	testCases := []struct {
+		name        string
+		room        domain.Room
+		buildStubs  func()
+		checkResult func(t *testing.T, room domain.Room, err error)
+	}{
+		{
+			name: "OK deactivate",
+			room: defaultRoom,
+			buildStubs: func() {
+				repo.EXPECT().Update(gomock.Any(), gomock.Eq(defaultRoom)).Times(1).Return(defaultRoom, nil)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRoom, room)
+			},
+		},
+		{
+			name: "validation error name",
+			room: domain.Room{
+				ID:       defaultRoom.ID,
+				Name:     "", // note
+				Capacity: defaultRoom.Capacity,
+			},
+			buildStubs: func() {
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "OK buffer",
+			room: domain.Room{
+				ID:       defaultRoom.ID,
+				Name:     defaultRoom.Name,
+				Capacity: defaultRoom.Capacity,
+				Active:   true,
+				Buffer:   domain.RoomBuffer{After: 15 * time.Minute}, // note
+			},
+			buildStubs: func() {
+				withBuffer := defaultRoom
+				withBuffer.Active = true
+				withBuffer.Buffer = domain.RoomBuffer{After: 15 * time.Minute}
+
+				repo.EXPECT().Update(gomock.Any(), gomock.Eq(withBuffer)).Times(1).Return(withBuffer, nil)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 15*time.Minute, room.Buffer.After)
+			},
+		},
+		{
+			name: "validation error buffer",
+			room: domain.Room{
+				ID:       defaultRoom.ID,
+				Name:     defaultRoom.Name,
+				Capacity: defaultRoom.Capacity,
+				Buffer:   domain.RoomBuffer{After: domain.MaxRoomBuffer + time.Minute}, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "not found error from Update",
+			room: defaultRoom,
+			buildStubs: func() {
+				repo.EXPECT().Update(gomock.Any(), gomock.Eq(defaultRoom)).Times(1).Return(domain.Room{}, domain.ErrRoomNotFound)
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			room, err := service.UpdateRoom(context.Background(),
+				string(tc.room.ID), tc.room.Name, tc.room.Capacity, tc.room.Location, tc.room.Active,
+				tc.room.Buffer.Before, tc.room.Buffer.After)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_DeleteRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockRoomRepository(ctrl)
+
+	service := NewRoomService(repo, mock_domain.NewMockScheduleRepository(ctrl))
+
+	defaultRoomID := "conf-a"
+
+	testCases := []struct {
+		name        string
+		roomID      string
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name:   "OK",
+			roomID: defaultRoomID,
+			buildStubs: func() {
+				repo.EXPECT().Delete(gomock.Any(), gomock.Eq(domain.RoomID(defaultRoomID))).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "validation error room id",
+			roomID: "", // note
+			buildStubs: func() {
+				repo.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:   "in use error from Delete",
+			roomID: defaultRoomID,
+			buildStubs: func() {
+				repo.EXPECT().Delete(gomock.Any(), gomock.Eq(domain.RoomID(defaultRoomID))).Times(1).Return(domain.ErrRoomInUse)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomInUse)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			err := service.DeleteRoom(context.Background(), tc.roomID)
+			tc.checkResult(t, err)
+		})
+	}
+}
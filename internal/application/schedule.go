@@ -0,0 +1,88 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type scheduleService struct {
+	repo domain.ScheduleRepository
+	tx   Transaction
+}
+
+func NewScheduleService(repo domain.ScheduleRepository, tx Transaction) *scheduleService {
+	return &scheduleService{
+		repo: repo,
+		tx:   tx,
+	}
+}
+
+func (s scheduleService) GetSchedule(ctx context.Context, roomID string) (domain.RoomSchedule, error) {
+	rid, err := domain.NewRoomID(roomID)
+	if err != nil {
+		return domain.RoomSchedule{}, err
+	}
+
+	return s.repo.GetSchedule(ctx, rid)
+}
+
+// SetSchedule заменяет часы работы комнаты целиком, пустые hours снимают ограничение.
+// На существующие бронирования не влияет
+func (s scheduleService) SetSchedule(ctx context.Context, roomID, timeZone string, hours []domain.OpeningHours) (domain.RoomSchedule, error) {
+	schedule, err := domain.NewRoomSchedule(roomID, timeZone, hours)
+	if err != nil {
+		return domain.RoomSchedule{}, err
+	}
+
+	// часы заменяются несколькими запросами, бронирование не должно увидеть их наполовину
+	err = s.tx.Execute(ctx, func(txCtx context.Context) error {
+		return s.repo.SetSchedule(txCtx, schedule)
+	}, pgx.TxOptions{})
+	if err != nil {
+		return domain.RoomSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+// ListBlackouts blackout периоды комнаты, пересекающиеся с [from, to)
+func (s scheduleService) ListBlackouts(ctx context.Context, roomID string, from, to time.Time) ([]domain.Blackout, error) {
+	rid, err := domain.NewRoomID(roomID)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := domain.NewTimeRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindBlackouts(ctx, rid, tr)
+}
+
+// CreateBlackout закрывает комнату для новых бронирований на [from, to),
+// уже существующие бронирования не отменяются
+func (s scheduleService) CreateBlackout(ctx context.Context, roomID string, from, to time.Time, reason string) (domain.Blackout, error) {
+	blackout, err := domain.NewBlackout(roomID, from, to, reason)
+	if err != nil {
+		return domain.Blackout{}, err
+	}
+
+	return s.repo.CreateBlackout(ctx, blackout)
+}
+
+func (s scheduleService) DeleteBlackout(ctx context.Context, roomID string, id int64) error {
+	rid, err := domain.NewRoomID(roomID)
+	if err != nil {
+		return err
+	}
+	if id <= 0 {
+		return fmt.Errorf("DeleteBlackout: ID should be positive number: %w", internal.ErrValidationFailed)
+	}
+
+	return s.repo.DeleteBlackout(ctx, rid, id)
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	mock_application "github.com/ynuraddi/test-kami/internal/application/mock"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_domain "github.com/ynuraddi/test-kami/internal/domain/mock"
+)
+
+func Test_SetSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txManager := mock_application.NewMockTransaction(ctrl)
+	repo := mock_domain.NewMockScheduleRepository(ctrl)
+
+	service := NewScheduleService(repo, txManager)
+
+	unexpectedError := errors.New("unexpected error")
+
+	hours := []domain.OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}}
+
+	expected, err := domain.NewRoomSchedule("conf-a", "Europe/Berlin", hours)
+	assert.NoError(t, err)
+
+	type args struct {
+		roomID   string
+		timeZone string
+		hours    []domain.OpeningHours
+	}
+
+	defaultArgs := args{
+		roomID:   "conf-a",
+		timeZone: "Europe/Berlin",
+		hours:    hours,
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, schedule domain.RoomSchedule, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				repo.EXPECT().SetSchedule(gomock.Any(), gomock.Eq(expected)).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, expected, schedule)
+			},
+		},
+		{
+			name: "validation error time zone",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: "Mars/Olympus", // note
+				hours:    defaultArgs.hours,
+			},
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				repo.EXPECT().SetSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "error room not found",
+			args: defaultArgs,
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(txCtx context.Context) error, txOptions pgx.TxOptions) error {
+						return f(ctx)
+					},
+				).Times(1)
+				repo.EXPECT().SetSchedule(gomock.Any(), gomock.Any()).Times(1).Return(domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "error from tx",
+			args: defaultArgs,
+			buildStubs: func() {
+				txManager.EXPECT().Execute(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, schedule)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			schedule, err := service.SetSchedule(context.Background(), tc.args.roomID, tc.args.timeZone, tc.args.hours)
+			tc.checkResult(t, schedule, err)
+		})
+	}
+}
+
+func Test_CreateBlackout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockScheduleRepository(ctrl)
+
+	service := NewScheduleService(repo, mock_application.NewMockTransaction(ctrl))
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultBlackout := domain.Blackout{
+		RoomID:    "conf-a",
+		TimeRange: domain.TimeRange{Start: now, End: now.Add(time.Hour)},
+		Reason:    "maintenance",
+	}
+	created := defaultBlackout
+	created.ID = 1
+
+	testCases := []struct {
+		name        string
+		blackout    domain.Blackout
+		buildStubs  func()
+		checkResult func(t *testing.T, blackout domain.Blackout, err error)
+	}{
+		{
+			name:     "OK",
+			blackout: defaultBlackout,
+			buildStubs: func() {
+				repo.EXPECT().CreateBlackout(gomock.Any(), gomock.Eq(defaultBlackout)).Times(1).Return(created, nil)
+			},
+			checkResult: func(t *testing.T, blackout domain.Blackout, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, created, blackout)
+			},
+		},
+		{
+			name: "validation error time range",
+			blackout: domain.Blackout{
+				RoomID:    defaultBlackout.RoomID,
+				TimeRange: domain.TimeRange{Start: now.Add(time.Hour), End: now}, // note
+			},
+			buildStubs: func() {
+				repo.EXPECT().CreateBlackout(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, blackout domain.Blackout, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, blackout)
+			},
+		},
+		{
+			name:     "error room not found",
+			blackout: defaultBlackout,
+			buildStubs: func() {
+				repo.EXPECT().CreateBlackout(gomock.Any(), gomock.Any()).Times(1).Return(domain.Blackout{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, blackout domain.Blackout, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, blackout)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			blackout, err := service.CreateBlackout(context.Background(), string(tc.blackout.RoomID),
+				tc.blackout.TimeRange.Start, tc.blackout.TimeRange.End, tc.blackout.Reason)
+			tc.checkResult(t, blackout, err)
+		})
+	}
+}
+
+func Test_DeleteBlackout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_domain.NewMockScheduleRepository(ctrl)
+
+	service := NewScheduleService(repo, mock_application.NewMockTransaction(ctrl))
+
+	repo.EXPECT().DeleteBlackout(gomock.Any(), domain.RoomID("conf-a"), int64(1)).Times(1).Return(nil)
+	assert.NoError(t, service.DeleteBlackout(context.Background(), "conf-a", 1))
+
+	assert.ErrorIs(t, service.DeleteBlackout(context.Background(), "conf-a", 0), internal.ErrValidationFailed)
+	assert.ErrorIs(t, service.DeleteBlackout(context.Background(), "", 1), internal.ErrValidationFailed)
+}
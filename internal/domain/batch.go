@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+// MaxBatchRooms ограничение пакетного бронирования,
+// каждая комната держит блокировку до конца транзакции
+const MaxBatchRooms = 20
+
+// BookingRequest одна комната из пакетного бронирования до валидации
+type BookingRequest struct {
+	RoomID   string
+	From, To time.Time
+}
+
+type RoomBooking struct {
+	RoomID    RoomID
+	TimeRange TimeRange
+}
+
+// NewRoomBookings пакет бронирует все комнаты или ни одной,
+// поэтому комната может встречаться в нем только один раз
+func NewRoomBookings(requests []BookingRequest) ([]RoomBooking, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("NewRoomBookings: %w: empty batch", internal.ErrValidationFailed)
+	}
+	if len(requests) > MaxBatchRooms {
+		return nil, fmt.Errorf("NewRoomBookings: %w: batch should contain at most %d rooms", internal.ErrValidationFailed, MaxBatchRooms)
+	}
+
+	bookings := make([]RoomBooking, 0, len(requests))
+	seen := make(map[RoomID]bool, len(requests))
+	for _, r := range requests {
+		rid, err := NewRoomID(r.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if seen[rid] {
+			return nil, fmt.Errorf("NewRoomBookings: %w: room %s is duplicated", internal.ErrValidationFailed, rid)
+		}
+		seen[rid] = true
+
+		tr, err := NewTimeRange(r.From, r.To)
+		if err != nil {
+			return nil, err
+		}
+
+		bookings = append(bookings, RoomBooking{RoomID: rid, TimeRange: tr})
+	}
+
+	return bookings, nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+func Test_NewRoomBookings(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	hall := BookingRequest{RoomID: "hall", From: now, To: now.Add(time.Hour)}
+	overflow := BookingRequest{RoomID: "overflow", From: now, To: now.Add(time.Hour)}
+
+	tooMany := make([]BookingRequest, 0, MaxBatchRooms+1)
+	for i := 0; i <= MaxBatchRooms; i++ {
+		tooMany = append(tooMany, BookingRequest{RoomID: fmt.Sprint(i), From: now, To: now.Add(time.Hour)})
+	}
+
+	testCases := []struct {
+		name        string
+		requests    []BookingRequest
+		checkResult func(t *testing.T, bookings []RoomBooking, err error)
+	}{
+		{
+			name:     "OK keeps request order",
+			requests: []BookingRequest{overflow, hall},
+			checkResult: func(t *testing.T, bookings []RoomBooking, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []RoomBooking{
+					{RoomID: "overflow", TimeRange: TimeRange{Start: now, End: now.Add(time.Hour)}},
+					{RoomID: "hall", TimeRange: TimeRange{Start: now, End: now.Add(time.Hour)}},
+				}, bookings)
+			},
+		},
+		{
+			name:     "NOT OK empty batch",
+			requests: nil, // note
+			checkResult: func(t *testing.T, bookings []RoomBooking, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, bookings)
+			},
+		},
+		{
+			name:     "NOT OK too many rooms",
+			requests: tooMany, // note
+			checkResult: func(t *testing.T, bookings []RoomBooking, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, bookings)
+			},
+		},
+		{
+			name:     "NOT OK duplicated room",
+			requests: []BookingRequest{hall, overflow, hall}, // note
+			checkResult: func(t *testing.T, bookings []RoomBooking, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, bookings)
+			},
+		},
+		{
+			name:     "NOT OK invalid room id",
+			requests: []BookingRequest{hall, {RoomID: "", From: now, To: now.Add(time.Hour)}}, // note
+			checkResult: func(t *testing.T, bookings []RoomBooking, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, bookings)
+			},
+		},
+		{
+			name:     "NOT OK invalid time range",
+			requests: []BookingRequest{hall, {RoomID: "overflow", From: now, To: now}}, // note
+			checkResult: func(t *testing.T, bookings []RoomBooking, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Nil(t, bookings)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookings, err := NewRoomBookings(tc.requests)
+			tc.checkResult(t, bookings, err)
+		})
+	}
+}
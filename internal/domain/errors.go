@@ -1,17 +1,59 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
+var (
+	ErrReservationNotFound        = errors.New("reservation not found")
+	ErrReservationVersionMismatch = errors.New("reservation was modified by another request")
+	ErrReservationNotHeld         = errors.New("reservation is not a hold")
+	ErrHoldExpired                = errors.New("hold has expired")
+
+	ErrRoomNotFound      = errors.New("room not found")
+	ErrRoomAlreadyExists = errors.New("room already exists")
+	ErrRoomInactive      = errors.New("room is deactivated")
+	ErrRoomInUse         = errors.New("room has reservations")
+
+	ErrBlackoutNotFound = errors.New("blackout not found")
+
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with another request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// ConflictReason откуда пересечение: с самим бронированием или только с буфером вокруг него
+type ConflictReason int
+
+const (
+	ConflictBooking ConflictReason = iota
+	ConflictBuffer
+)
+
+func (r ConflictReason) String() string {
+	if r == ConflictBuffer {
+		return "buffer"
+	}
+	return "booking"
+}
+
 type ReservationConflictError struct {
 	Reservation         TimeRange
 	ConflictReservation TimeRange
+	Reason              ConflictReason
 }
 
 var _ error = (*ReservationConflictError)(nil)
 
 func (e ReservationConflictError) Error() string {
+	if e.Reason == ConflictBuffer {
+		return fmt.Sprintf("reservation with time [%s] conflicts with room buffer around [%s]",
+			e.Reservation.String(),
+			e.ConflictReservation.String())
+	}
 	return fmt.Sprintf("reservation with time [%s] conflicts with [%s]",
 		e.Reservation.String(),
 		e.ConflictReservation.String())
@@ -23,3 +65,129 @@ func (e ReservationConflictError) Is(target error) bool {
 	}
 	return true
 }
+
+// SeriesConflictError все вхождения серии, пересекающиеся с существующими бронированиями
+type SeriesConflictError struct {
+	Conflicts []ReservationConflictError
+}
+
+var _ error = (*SeriesConflictError)(nil)
+
+func (e SeriesConflictError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, c.Error())
+	}
+	return fmt.Sprintf("%d occurrences of series conflict: %s", len(e.Conflicts), strings.Join(parts, "; "))
+}
+
+func (e SeriesConflictError) Is(target error) bool {
+	if _, ok := target.(*SeriesConflictError); !ok {
+		return false
+	}
+	return true
+}
+
+type RoomConflict struct {
+	RoomID   RoomID
+	Conflict ReservationConflictError
+}
+
+// BatchConflictError все комнаты пакетного бронирования, которые пересеклись с существующими бронированиями
+type BatchConflictError struct {
+	Conflicts []RoomConflict
+}
+
+var _ error = (*BatchConflictError)(nil)
+
+func (e BatchConflictError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("room %s: %s", c.RoomID, c.Conflict.Error()))
+	}
+	return fmt.Sprintf("%d rooms of batch conflict: %s", len(e.Conflicts), strings.Join(parts, "; "))
+}
+
+func (e BatchConflictError) Is(target error) bool {
+	if _, ok := target.(*BatchConflictError); !ok {
+		return false
+	}
+	return true
+}
+
+// OutsideBookableHoursError бронирование выходит за часы работы комнаты
+// или попадает на blackout (тогда Blackout не nil)
+type OutsideBookableHoursError struct {
+	Reservation TimeRange
+	Blackout    *TimeRange
+}
+
+var _ error = (*OutsideBookableHoursError)(nil)
+
+func (e OutsideBookableHoursError) Error() string {
+	if e.Blackout != nil {
+		return fmt.Sprintf("reservation with time [%s] falls into room blackout [%s]",
+			e.Reservation.String(),
+			e.Blackout.String())
+	}
+	return fmt.Sprintf("reservation with time [%s] is outside room opening hours",
+		e.Reservation.String())
+}
+
+func (e OutsideBookableHoursError) Is(target error) bool {
+	if _, ok := target.(*OutsideBookableHoursError); !ok {
+		return false
+	}
+	return true
+}
+
+// CapacityExceededError участников бронирования больше, чем вмещает комната
+type CapacityExceededError struct {
+	RoomID    RoomID
+	Capacity  int
+	Attendees int
+}
+
+var _ error = (*CapacityExceededError)(nil)
+
+func (e CapacityExceededError) Error() string {
+	return fmt.Sprintf("reservation has %d attendees, room %s fits %d", e.Attendees, e.RoomID, e.Capacity)
+}
+
+func (e CapacityExceededError) Is(target error) bool {
+	if _, ok := target.(*CapacityExceededError); !ok {
+		return false
+	}
+	return true
+}
+
+// PolicyViolationError все правила BookingPolicy, которые нарушает бронирование
+type PolicyViolationError struct {
+	Violations []PolicyViolation
+}
+
+var _ error = (*PolicyViolationError)(nil)
+
+func (e PolicyViolationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Rule(), v.Error()))
+	}
+	return fmt.Sprintf("reservation violates %d booking rules: %s", len(e.Violations), strings.Join(parts, "; "))
+}
+
+// Unwrap позволяет достать конкретное нарушение через errors.As
+func (e PolicyViolationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		errs = append(errs, v)
+	}
+	return errs
+}
+
+func (e PolicyViolationError) Is(target error) bool {
+	if _, ok := target.(*PolicyViolationError); !ok {
+		return false
+	}
+	return true
+}
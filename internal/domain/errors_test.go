@@ -36,4 +36,146 @@ func Test_ReservationConflictError(t *testing.T) {
 
 	wrappedErr := fmt.Errorf("some error: %w", conflict)
 	assert.ErrorIs(t, wrappedErr, targetErr)
+
+	buffer := conflict
+	buffer.Reason = ConflictBuffer // note
+
+	assert.Equal(t, fmt.Sprintf(
+		"reservation with time [%s - %s] conflicts with room buffer around [%s - %s]",
+		tr1.Start.Format(time.DateTime),
+		tr1.End.Format(time.DateTime),
+		tr2.Start.Format(time.DateTime),
+		tr2.End.Format(time.DateTime),
+	), buffer.Error())
+	assert.ErrorIs(t, buffer, targetErr)
+
+	assert.Equal(t, "booking", ConflictBooking.String())
+	assert.Equal(t, "buffer", ConflictBuffer.String())
+}
+
+func Test_SeriesConflictError(t *testing.T) {
+	now := time.Now()
+
+	tr1, err := NewTimeRange(now, now.Add(1*time.Minute))
+	assert.NoError(t, err)
+
+	tr2, err := NewTimeRange(now, now.Add(30*time.Second))
+	assert.NoError(t, err)
+
+	conflict := ReservationConflictError{
+		Reservation:         tr1,
+		ConflictReservation: tr2,
+	}
+
+	series := SeriesConflictError{
+		Conflicts: []ReservationConflictError{conflict, conflict},
+	}
+
+	assert.Equal(t, fmt.Sprintf("2 occurrences of series conflict: %s; %s", conflict.Error(), conflict.Error()), series.Error())
+
+	targetErr := &SeriesConflictError{}
+	assert.True(t, series.Is(targetErr))
+	assert.False(t, series.Is(&ReservationConflictError{}))
+
+	wrappedErr := fmt.Errorf("some error: %w", series)
+	assert.ErrorIs(t, wrappedErr, targetErr)
+}
+
+func Test_BatchConflictError(t *testing.T) {
+	now := time.Now()
+
+	tr1, err := NewTimeRange(now, now.Add(1*time.Minute))
+	assert.NoError(t, err)
+
+	tr2, err := NewTimeRange(now, now.Add(30*time.Second))
+	assert.NoError(t, err)
+
+	conflict := ReservationConflictError{
+		Reservation:         tr1,
+		ConflictReservation: tr2,
+	}
+
+	batch := BatchConflictError{
+		Conflicts: []RoomConflict{
+			{RoomID: "hall", Conflict: conflict},
+			{RoomID: "overflow", Conflict: conflict},
+		},
+	}
+
+	assert.Equal(t, fmt.Sprintf("2 rooms of batch conflict: room hall: %s; room overflow: %s", conflict.Error(), conflict.Error()), batch.Error())
+
+	targetErr := &BatchConflictError{}
+	assert.True(t, batch.Is(targetErr))
+	assert.False(t, batch.Is(&ReservationConflictError{}))
+	assert.False(t, batch.Is(&SeriesConflictError{}))
+
+	wrappedErr := fmt.Errorf("some error: %w", batch)
+	assert.ErrorIs(t, wrappedErr, targetErr)
+}
+
+func Test_OutsideBookableHoursError(t *testing.T) {
+	now := time.Now()
+
+	tr, err := NewTimeRange(now, now.Add(1*time.Hour))
+	assert.NoError(t, err)
+
+	blackout, err := NewTimeRange(now.Add(30*time.Minute), now.Add(2*time.Hour))
+	assert.NoError(t, err)
+
+	outside := OutsideBookableHoursError{
+		Reservation: tr,
+	}
+
+	assert.Equal(t, fmt.Sprintf("reservation with time [%s] is outside room opening hours", tr.String()), outside.Error())
+
+	targetErr := &OutsideBookableHoursError{}
+	assert.True(t, outside.Is(targetErr))
+	assert.False(t, outside.Is(&ReservationConflictError{}))
+
+	inBlackout := outside
+	inBlackout.Blackout = &blackout // note
+
+	assert.Equal(t, fmt.Sprintf("reservation with time [%s] falls into room blackout [%s]", tr.String(), blackout.String()), inBlackout.Error())
+
+	wrappedErr := fmt.Errorf("some error: %w", inBlackout)
+	assert.ErrorIs(t, wrappedErr, targetErr)
+}
+
+func Test_PolicyViolationError(t *testing.T) {
+	short := MinDurationViolation{Min: 5 * time.Minute, Duration: time.Minute}
+	quota := OwnerQuotaViolation{Owner: "alice", Limit: 2, Active: 2}
+
+	violation := PolicyViolationError{
+		Violations: []PolicyViolation{short, quota},
+	}
+
+	assert.Equal(t, fmt.Sprintf("reservation violates 2 booking rules: min_duration: %s; owner_quota: %s", short.Error(), quota.Error()), violation.Error())
+
+	targetErr := &PolicyViolationError{}
+	assert.True(t, violation.Is(targetErr))
+	assert.False(t, violation.Is(&OutsideBookableHoursError{}))
+
+	wrappedErr := fmt.Errorf("some error: %w", violation)
+	assert.ErrorIs(t, wrappedErr, targetErr)
+
+	var quotaErr OwnerQuotaViolation
+	assert.ErrorAs(t, wrappedErr, &quotaErr)
+	assert.Equal(t, quota, quotaErr)
+}
+
+func Test_CapacityExceededError(t *testing.T) {
+	exceeded := CapacityExceededError{
+		RoomID:    "conf-a",
+		Capacity:  4,
+		Attendees: 5,
+	}
+
+	assert.Equal(t, "reservation has 5 attendees, room conf-a fits 4", exceeded.Error())
+
+	targetErr := &CapacityExceededError{}
+	assert.True(t, exceeded.Is(targetErr))
+	assert.False(t, exceeded.Is(&ReservationConflictError{}))
+
+	wrappedErr := fmt.Errorf("some error: %w", exceeded)
+	assert.ErrorIs(t, wrappedErr, targetErr)
 }
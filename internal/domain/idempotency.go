@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+const maxIdempotencyKeyLen = 255
+
+type IdempotencyKey string
+
+// NewIdempotencyKey ключ генерирует клиент (обычно UUID),
+// поэтому проверяем только длину и печатные ASCII символы
+func NewIdempotencyKey(key string) (IdempotencyKey, error) {
+	if len(key) == 0 || len(key) > maxIdempotencyKeyLen {
+		return "", fmt.Errorf("NewIdempotencyKey: %w: len of key should be in range [1, %d]", internal.ErrValidationFailed, maxIdempotencyKeyLen)
+	}
+	for _, c := range key {
+		if c < 0x21 || c > 0x7e {
+			return "", fmt.Errorf("NewIdempotencyKey: %w: key should contain only printable ASCII characters", internal.ErrValidationFailed)
+		}
+	}
+	return IdempotencyKey(key), nil
+}
+
+// IdempotentResponse ответ на первый запрос с ключом, отдается повторно без выполнения
+type IdempotentResponse struct {
+	StatusCode int
+	Location   string
+	Body       []byte
+}
+
+type IdempotencyRecord struct {
+	Key         IdempotencyKey
+	RequestHash string
+	// nil, пока первый запрос с этим ключом еще выполняется
+	Response  *IdempotentResponse
+	CreatedAt time.Time
+}
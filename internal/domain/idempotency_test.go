@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+func Test_IdempotencyKey(t *testing.T) {
+	testCases := []struct {
+		name        string
+		key         string
+		checkResult func(t *testing.T, key IdempotencyKey, err error)
+	}{
+		{
+			name: "OK uuid",
+			key:  "4f1c2a7e-9b7d-4e5a-8f3b-2d6c1e0a9b7d",
+			checkResult: func(t *testing.T, key IdempotencyKey, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, IdempotencyKey("4f1c2a7e-9b7d-4e5a-8f3b-2d6c1e0a9b7d"), key)
+			},
+		},
+		{
+			name: "OK max len",
+			key:  strings.Repeat("k", maxIdempotencyKeyLen),
+			checkResult: func(t *testing.T, key IdempotencyKey, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK empty",
+			key:  "", // note
+			checkResult: func(t *testing.T, key IdempotencyKey, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, key)
+			},
+		},
+		{
+			name: "NOT OK too long",
+			key:  strings.Repeat("k", maxIdempotencyKeyLen+1), // note
+			checkResult: func(t *testing.T, key IdempotencyKey, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, key)
+			},
+		},
+		{
+			name: "NOT OK with space",
+			key:  "some key", // note
+			checkResult: func(t *testing.T, key IdempotencyKey, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, key)
+			},
+		},
+		{
+			name: "NOT OK non ascii",
+			key:  "ключ", // note
+			checkResult: func(t *testing.T, key IdempotencyKey, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, key)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := NewIdempotencyKey(tc.key)
+			tc.checkResult(t, key, err)
+		})
+	}
+}
@@ -7,6 +7,7 @@ package mock_domain
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/ynuraddi/test-kami/internal/domain"
@@ -35,31 +36,582 @@ func (m *MockReservationRepository) EXPECT() *MockReservationRepositoryMockRecor
 	return m.recorder
 }
 
+// Confirm mocks base method.
+func (m *MockReservationRepository) Confirm(ctx context.Context, id, version int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, id, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockReservationRepositoryMockRecorder) Confirm(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockReservationRepository)(nil).Confirm), ctx, id, version)
+}
+
+// CountActiveByOwner mocks base method.
+func (m *MockReservationRepository) CountActiveByOwner(ctx context.Context, owner string, excludeID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveByOwner", ctx, owner, excludeID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveByOwner indicates an expected call of CountActiveByOwner.
+func (mr *MockReservationRepositoryMockRecorder) CountActiveByOwner(ctx, owner, excludeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveByOwner", reflect.TypeOf((*MockReservationRepository)(nil).CountActiveByOwner), ctx, owner, excludeID)
+}
+
 // Create mocks base method.
-func (m *MockReservationRepository) Create(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) error {
+func (m *MockReservationRepository) Create(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange, details domain.ReservationDetails) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, roomID, timeRange, details)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReservationRepositoryMockRecorder) Create(ctx, roomID, timeRange, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReservationRepository)(nil).Create), ctx, roomID, timeRange, details)
+}
+
+// CreateHold mocks base method.
+func (m *MockReservationRepository) CreateHold(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange, details domain.ReservationDetails, ttl time.Duration) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, roomID, timeRange, details, ttl)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockReservationRepositoryMockRecorder) CreateHold(ctx, roomID, timeRange, details, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockReservationRepository)(nil).CreateHold), ctx, roomID, timeRange, details, ttl)
+}
+
+// CreateSeries mocks base method.
+func (m *MockReservationRepository) CreateSeries(ctx context.Context, roomID domain.RoomID, rule string, timeRanges []domain.TimeRange, details domain.ReservationDetails) (domain.ReservationSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", ctx, roomID, rule, timeRanges, details)
+	ret0, _ := ret[0].(domain.ReservationSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockReservationRepositoryMockRecorder) CreateSeries(ctx, roomID, rule, timeRanges, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockReservationRepository)(nil).CreateSeries), ctx, roomID, rule, timeRanges, details)
+}
+
+// Delete mocks base method.
+func (m *MockReservationRepository) Delete(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, roomID, timeRange)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockReservationRepositoryMockRecorder) Create(ctx, roomID, timeRange interface{}) *gomock.Call {
+// Delete indicates an expected call of Delete.
+func (mr *MockReservationRepositoryMockRecorder) Delete(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReservationRepository)(nil).Delete), ctx, id, version)
+}
+
+// DeleteExpiredHolds mocks base method.
+func (m *MockReservationRepository) DeleteExpiredHolds(ctx context.Context, roomID domain.RoomID) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredHolds", ctx, roomID)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredHolds indicates an expected call of DeleteExpiredHolds.
+func (mr *MockReservationRepositoryMockRecorder) DeleteExpiredHolds(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredHolds", reflect.TypeOf((*MockReservationRepository)(nil).DeleteExpiredHolds), ctx, roomID)
+}
+
+// FindOverlapping mocks base method.
+func (m *MockReservationRepository) FindOverlapping(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOverlapping", ctx, roomID, timeRange)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOverlapping indicates an expected call of FindOverlapping.
+func (mr *MockReservationRepositoryMockRecorder) FindOverlapping(ctx, roomID, timeRange interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReservationRepository)(nil).Create), ctx, roomID, timeRange)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOverlapping", reflect.TypeOf((*MockReservationRepository)(nil).FindOverlapping), ctx, roomID, timeRange)
+}
+
+// GetByID mocks base method.
+func (m *MockReservationRepository) GetByID(ctx context.Context, id int64) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockReservationRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockReservationRepository)(nil).GetByID), ctx, id)
 }
 
 // ListByRoom mocks base method.
-func (m *MockReservationRepository) ListByRoom(ctx context.Context, roomID domain.RoomID) ([]domain.Reservation, error) {
+func (m *MockReservationRepository) ListByRoom(ctx context.Context, query domain.ReservationQuery) ([]domain.Reservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByRoom", ctx, roomID)
+	ret := m.ctrl.Call(m, "ListByRoom", ctx, query)
 	ret0, _ := ret[0].([]domain.Reservation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByRoom indicates an expected call of ListByRoom.
-func (mr *MockReservationRepositoryMockRecorder) ListByRoom(ctx, roomID interface{}) *gomock.Call {
+func (mr *MockReservationRepositoryMockRecorder) ListByRoom(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRoom", reflect.TypeOf((*MockReservationRepository)(nil).ListByRoom), ctx, query)
+}
+
+// ListExpiredHoldRooms mocks base method.
+func (m *MockReservationRepository) ListExpiredHoldRooms(ctx context.Context) ([]domain.RoomID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredHoldRooms", ctx)
+	ret0, _ := ret[0].([]domain.RoomID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredHoldRooms indicates an expected call of ListExpiredHoldRooms.
+func (mr *MockReservationRepositoryMockRecorder) ListExpiredHoldRooms(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredHoldRooms", reflect.TypeOf((*MockReservationRepository)(nil).ListExpiredHoldRooms), ctx)
+}
+
+// LockOwner mocks base method.
+func (m *MockReservationRepository) LockOwner(ctx context.Context, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOwner", ctx, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockOwner indicates an expected call of LockOwner.
+func (mr *MockReservationRepositoryMockRecorder) LockOwner(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOwner", reflect.TypeOf((*MockReservationRepository)(nil).LockOwner), ctx, owner)
+}
+
+// Update mocks base method.
+func (m *MockReservationRepository) Update(ctx context.Context, id int64, timeRange domain.TimeRange, version int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, timeRange, version)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReservationRepositoryMockRecorder) Update(ctx, id, timeRange, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReservationRepository)(nil).Update), ctx, id, timeRange, version)
+}
+
+// MockRoomRepository is a mock of RoomRepository interface.
+type MockRoomRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomRepositoryMockRecorder
+}
+
+// MockRoomRepositoryMockRecorder is the mock recorder for MockRoomRepository.
+type MockRoomRepositoryMockRecorder struct {
+	mock *MockRoomRepository
+}
+
+// NewMockRoomRepository creates a new mock instance.
+func NewMockRoomRepository(ctrl *gomock.Controller) *MockRoomRepository {
+	mock := &MockRoomRepository{ctrl: ctrl}
+	mock.recorder = &MockRoomRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomRepository) EXPECT() *MockRoomRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRoomRepository) Create(ctx context.Context, room domain.Room) (domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, room)
+	ret0, _ := ret[0].(domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoomRepositoryMockRecorder) Create(ctx, room interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoomRepository)(nil).Create), ctx, room)
+}
+
+// Delete mocks base method.
+func (m *MockRoomRepository) Delete(ctx context.Context, id domain.RoomID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoomRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoomRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockRoomRepository) GetByID(ctx context.Context, id domain.RoomID) (domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRoomRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRoomRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockRoomRepository) List(ctx context.Context) ([]domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoomRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoomRepository)(nil).List), ctx)
+}
+
+// ListAvailable mocks base method.
+func (m *MockRoomRepository) ListAvailable(ctx context.Context, timeRange domain.TimeRange, minCapacity int) ([]domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailable", ctx, timeRange, minCapacity)
+	ret0, _ := ret[0].([]domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailable indicates an expected call of ListAvailable.
+func (mr *MockRoomRepositoryMockRecorder) ListAvailable(ctx, timeRange, minCapacity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailable", reflect.TypeOf((*MockRoomRepository)(nil).ListAvailable), ctx, timeRange, minCapacity)
+}
+
+// Update mocks base method.
+func (m *MockRoomRepository) Update(ctx context.Context, room domain.Room) (domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, room)
+	ret0, _ := ret[0].(domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoomRepositoryMockRecorder) Update(ctx, room interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoomRepository)(nil).Update), ctx, room)
+}
+
+// MockScheduleRepository is a mock of ScheduleRepository interface.
+type MockScheduleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleRepositoryMockRecorder
+}
+
+// MockScheduleRepositoryMockRecorder is the mock recorder for MockScheduleRepository.
+type MockScheduleRepositoryMockRecorder struct {
+	mock *MockScheduleRepository
+}
+
+// NewMockScheduleRepository creates a new mock instance.
+func NewMockScheduleRepository(ctrl *gomock.Controller) *MockScheduleRepository {
+	mock := &MockScheduleRepository{ctrl: ctrl}
+	mock.recorder = &MockScheduleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleRepository) EXPECT() *MockScheduleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateBlackout mocks base method.
+func (m *MockScheduleRepository) CreateBlackout(ctx context.Context, blackout domain.Blackout) (domain.Blackout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlackout", ctx, blackout)
+	ret0, _ := ret[0].(domain.Blackout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlackout indicates an expected call of CreateBlackout.
+func (mr *MockScheduleRepositoryMockRecorder) CreateBlackout(ctx, blackout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlackout", reflect.TypeOf((*MockScheduleRepository)(nil).CreateBlackout), ctx, blackout)
+}
+
+// DeleteBlackout mocks base method.
+func (m *MockScheduleRepository) DeleteBlackout(ctx context.Context, roomID domain.RoomID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlackout", ctx, roomID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlackout indicates an expected call of DeleteBlackout.
+func (mr *MockScheduleRepositoryMockRecorder) DeleteBlackout(ctx, roomID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlackout", reflect.TypeOf((*MockScheduleRepository)(nil).DeleteBlackout), ctx, roomID, id)
+}
+
+// FindBlackouts mocks base method.
+func (m *MockScheduleRepository) FindBlackouts(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) ([]domain.Blackout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBlackouts", ctx, roomID, timeRange)
+	ret0, _ := ret[0].([]domain.Blackout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBlackouts indicates an expected call of FindBlackouts.
+func (mr *MockScheduleRepositoryMockRecorder) FindBlackouts(ctx, roomID, timeRange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBlackouts", reflect.TypeOf((*MockScheduleRepository)(nil).FindBlackouts), ctx, roomID, timeRange)
+}
+
+// GetSchedule mocks base method.
+func (m *MockScheduleRepository) GetSchedule(ctx context.Context, roomID domain.RoomID) (domain.RoomSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", ctx, roomID)
+	ret0, _ := ret[0].(domain.RoomSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockScheduleRepositoryMockRecorder) GetSchedule(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockScheduleRepository)(nil).GetSchedule), ctx, roomID)
+}
+
+// ListSchedules mocks base method.
+func (m *MockScheduleRepository) ListSchedules(ctx context.Context, roomIDs []domain.RoomID) (map[domain.RoomID]domain.RoomSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedules", ctx, roomIDs)
+	ret0, _ := ret[0].(map[domain.RoomID]domain.RoomSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockScheduleRepositoryMockRecorder) ListSchedules(ctx, roomIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockScheduleRepository)(nil).ListSchedules), ctx, roomIDs)
+}
+
+// SetSchedule mocks base method.
+func (m *MockScheduleRepository) SetSchedule(ctx context.Context, schedule domain.RoomSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSchedule indicates an expected call of SetSchedule.
+func (mr *MockScheduleRepositoryMockRecorder) SetSchedule(ctx, schedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSchedule", reflect.TypeOf((*MockScheduleRepository)(nil).SetSchedule), ctx, schedule)
+}
+
+// MockWaitlistRepository is a mock of WaitlistRepository interface.
+type MockWaitlistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWaitlistRepositoryMockRecorder
+}
+
+// MockWaitlistRepositoryMockRecorder is the mock recorder for MockWaitlistRepository.
+type MockWaitlistRepositoryMockRecorder struct {
+	mock *MockWaitlistRepository
+}
+
+// NewMockWaitlistRepository creates a new mock instance.
+func NewMockWaitlistRepository(ctrl *gomock.Controller) *MockWaitlistRepository {
+	mock := &MockWaitlistRepository{ctrl: ctrl}
+	mock.recorder = &MockWaitlistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWaitlistRepository) EXPECT() *MockWaitlistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWaitlistRepository) Create(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange, details domain.ReservationDetails) (domain.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, roomID, timeRange, details)
+	ret0, _ := ret[0].(domain.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWaitlistRepositoryMockRecorder) Create(ctx, roomID, timeRange, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWaitlistRepository)(nil).Create), ctx, roomID, timeRange, details)
+}
+
+// FindWaiting mocks base method.
+func (m *MockWaitlistRepository) FindWaiting(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) ([]domain.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWaiting", ctx, roomID, timeRange)
+	ret0, _ := ret[0].([]domain.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWaiting indicates an expected call of FindWaiting.
+func (mr *MockWaitlistRepositoryMockRecorder) FindWaiting(ctx, roomID, timeRange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWaiting", reflect.TypeOf((*MockWaitlistRepository)(nil).FindWaiting), ctx, roomID, timeRange)
+}
+
+// GetByID mocks base method.
+func (m *MockWaitlistRepository) GetByID(ctx context.Context, id int64) (domain.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(domain.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWaitlistRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWaitlistRepository)(nil).GetByID), ctx, id)
+}
+
+// Promote mocks base method.
+func (m *MockWaitlistRepository) Promote(ctx context.Context, id, reservationID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Promote", ctx, id, reservationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Promote indicates an expected call of Promote.
+func (mr *MockWaitlistRepositoryMockRecorder) Promote(ctx, id, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockWaitlistRepository)(nil).Promote), ctx, id, reservationID)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockIdempotencyRepository) Claim(ctx context.Context, key domain.IdempotencyKey, requestHash string, abandonAfter time.Duration) (domain.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, key, requestHash, abandonAfter)
+	ret0, _ := ret[0].(domain.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockIdempotencyRepositoryMockRecorder) Claim(ctx, key, requestHash, abandonAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockIdempotencyRepository)(nil).Claim), ctx, key, requestHash, abandonAfter)
+}
+
+// Delete mocks base method.
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, key domain.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Delete), ctx, key)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, createdBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, createdBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpired(ctx, createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpired), ctx, createdBefore)
+}
+
+// SaveResponse mocks base method.
+func (m *MockIdempotencyRepository) SaveResponse(ctx context.Context, key domain.IdempotencyKey, response domain.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResponse", ctx, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResponse indicates an expected call of SaveResponse.
+func (mr *MockIdempotencyRepositoryMockRecorder) SaveResponse(ctx, key, response interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRoom", reflect.TypeOf((*MockReservationRepository)(nil).ListByRoom), ctx, roomID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResponse", reflect.TypeOf((*MockIdempotencyRepository)(nil).SaveResponse), ctx, key, response)
 }
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Booking то, что проверяет BookingPolicy: кто, какую комнату и на какое время бронирует.
+// Правила не ходят в базу и не смотрят на часы, все факты собирает сервис
+type Booking struct {
+	RoomID    RoomID
+	Owner     string
+	TimeRange TimeRange
+	// Now момент проверки
+	Now time.Time
+	// OwnerActive сколько у Owner уже незакончившихся бронирований, не считая проверяемого
+	OwnerActive int
+}
+
+// BookingPolicy правило бронирования. Check возвращает все нарушения правила, nil - бронирование разрешено
+type BookingPolicy interface {
+	Check(booking Booking) []PolicyViolation
+}
+
+// BookingPolicies проверяет все правила, чтобы клиент сразу увидел все нарушения, а не только первое
+type BookingPolicies []BookingPolicy
+
+func (p BookingPolicies) Check(booking Booking) []PolicyViolation {
+	var violations []PolicyViolation
+	for _, policy := range p {
+		violations = append(violations, policy.Check(booking)...)
+	}
+	return violations
+}
+
+// CheckPolicy nil или PolicyViolationError со всеми нарушениями policy
+func CheckPolicy(policy BookingPolicy, booking Booking) error {
+	if violations := policy.Check(booking); len(violations) > 0 {
+		return PolicyViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// MinDuration бронирование не короче заданного, 0 - без ограничения
+type MinDuration time.Duration
+
+func (r MinDuration) Check(booking Booking) []PolicyViolation {
+	duration := booking.TimeRange.End.Sub(booking.TimeRange.Start)
+	if r > 0 && duration < time.Duration(r) {
+		return []PolicyViolation{MinDurationViolation{Min: time.Duration(r), Duration: duration}}
+	}
+	return nil
+}
+
+// MaxDuration бронирование не длиннее заданного, 0 - без ограничения
+type MaxDuration time.Duration
+
+func (r MaxDuration) Check(booking Booking) []PolicyViolation {
+	duration := booking.TimeRange.End.Sub(booking.TimeRange.Start)
+	if r > 0 && duration > time.Duration(r) {
+		return []PolicyViolation{MaxDurationViolation{Max: time.Duration(r), Duration: duration}}
+	}
+	return nil
+}
+
+// AdvanceWindow насколько заранее можно бронировать: начало не позже Now + окно, 0 - без ограничения
+type AdvanceWindow time.Duration
+
+func (r AdvanceWindow) Check(booking Booking) []PolicyViolation {
+	if r > 0 && booking.TimeRange.Start.After(booking.Now.Add(time.Duration(r))) {
+		return []PolicyViolation{AdvanceWindowViolation{Window: time.Duration(r), Start: booking.TimeRange.Start}}
+	}
+	return nil
+}
+
+// NoPastBookings бронирование не может начинаться раньше Now
+type NoPastBookings struct{}
+
+func (NoPastBookings) Check(booking Booking) []PolicyViolation {
+	if booking.TimeRange.Start.Before(booking.Now) {
+		return []PolicyViolation{PastBookingViolation{Start: booking.TimeRange.Start, Now: booking.Now}}
+	}
+	return nil
+}
+
+// OwnerQuota сколько незакончившихся бронирований может быть у одного владельца, 0 - без ограничения.
+// Бронирования без владельца не ограничиваются
+type OwnerQuota int
+
+func (r OwnerQuota) Check(booking Booking) []PolicyViolation {
+	if r > 0 && booking.Owner != "" && booking.OwnerActive >= int(r) {
+		return []PolicyViolation{OwnerQuotaViolation{Owner: booking.Owner, Limit: int(r), Active: booking.OwnerActive}}
+	}
+	return nil
+}
+
+// PolicyViolation нарушение одного правила BookingPolicy
+type PolicyViolation interface {
+	error
+	// Rule имя правила для клиента
+	Rule() string
+}
+
+type MinDurationViolation struct {
+	Min      time.Duration
+	Duration time.Duration
+}
+
+func (v MinDurationViolation) Rule() string { return "min_duration" }
+
+func (v MinDurationViolation) Error() string {
+	return fmt.Sprintf("reservation lasts %s, minimum is %s", v.Duration, v.Min)
+}
+
+type MaxDurationViolation struct {
+	Max      time.Duration
+	Duration time.Duration
+}
+
+func (v MaxDurationViolation) Rule() string { return "max_duration" }
+
+func (v MaxDurationViolation) Error() string {
+	return fmt.Sprintf("reservation lasts %s, maximum is %s", v.Duration, v.Max)
+}
+
+type AdvanceWindowViolation struct {
+	Window time.Duration
+	Start  time.Time
+}
+
+func (v AdvanceWindowViolation) Rule() string { return "advance_window" }
+
+func (v AdvanceWindowViolation) Error() string {
+	return fmt.Sprintf("reservation starts at %s, bookings are allowed at most %s ahead",
+		v.Start.Format(time.DateTime), v.Window)
+}
+
+type PastBookingViolation struct {
+	Start time.Time
+	Now   time.Time
+}
+
+func (v PastBookingViolation) Rule() string { return "no_past_bookings" }
+
+func (v PastBookingViolation) Error() string {
+	return fmt.Sprintf("reservation starts at %s, which is in the past", v.Start.Format(time.DateTime))
+}
+
+type OwnerQuotaViolation struct {
+	Owner  string
+	Limit  int
+	Active int
+}
+
+func (v OwnerQuotaViolation) Rule() string { return "owner_quota" }
+
+func (v OwnerQuotaViolation) Error() string {
+	return fmt.Sprintf("owner %q already has %d active reservations, limit is %d", v.Owner, v.Active, v.Limit)
+}
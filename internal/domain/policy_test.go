@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BookingPolicies(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	policy := BookingPolicies{
+		NoPastBookings{},
+		MinDuration(15 * time.Minute),
+		MaxDuration(4 * time.Hour),
+		AdvanceWindow(30 * 24 * time.Hour),
+		OwnerQuota(2),
+	}
+
+	booking := func(start time.Time, duration time.Duration) Booking {
+		return Booking{
+			RoomID:    "conf-a",
+			Owner:     "alice",
+			TimeRange: TimeRange{Start: start, End: start.Add(duration)},
+			Now:       now,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		booking     Booking
+		checkResult func(t *testing.T, violations []PolicyViolation)
+	}{
+		{
+			name:    "OK",
+			booking: booking(now.Add(time.Hour), time.Hour),
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Empty(t, violations)
+			},
+		},
+		{
+			name:    "OK exactly on limits",
+			booking: booking(now.Add(30*24*time.Hour), 4*time.Hour), // note
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Empty(t, violations)
+			},
+		},
+		{
+			name:    "OK starts now",
+			booking: booking(now, 15*time.Minute), // note
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Empty(t, violations)
+			},
+		},
+		{
+			name:    "NOT OK too short",
+			booking: booking(now.Add(time.Hour), 10*time.Minute), // note
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Equal(t, []PolicyViolation{
+					MinDurationViolation{Min: 15 * time.Minute, Duration: 10 * time.Minute},
+				}, violations)
+			},
+		},
+		{
+			name:    "NOT OK too long",
+			booking: booking(now.Add(time.Hour), 5*time.Hour), // note
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Equal(t, []PolicyViolation{
+					MaxDurationViolation{Max: 4 * time.Hour, Duration: 5 * time.Hour},
+				}, violations)
+			},
+		},
+		{
+			name:    "NOT OK too far ahead",
+			booking: booking(now.Add(31*24*time.Hour), time.Hour), // note
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Equal(t, []PolicyViolation{
+					AdvanceWindowViolation{Window: 30 * 24 * time.Hour, Start: now.Add(31 * 24 * time.Hour)},
+				}, violations)
+			},
+		},
+		{
+			name: "NOT OK quota exhausted",
+			booking: func() Booking {
+				b := booking(now.Add(time.Hour), time.Hour)
+				b.OwnerActive = 2 // note
+				return b
+			}(),
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Equal(t, []PolicyViolation{
+					OwnerQuotaViolation{Owner: "alice", Limit: 2, Active: 2},
+				}, violations)
+			},
+		},
+		{
+			name: "OK quota not applied without owner",
+			booking: func() Booking {
+				b := booking(now.Add(time.Hour), time.Hour)
+				b.Owner = "" // note
+				b.OwnerActive = 10
+				return b
+			}(),
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Empty(t, violations)
+			},
+		},
+		{
+			name:    "NOT OK all violations reported",
+			booking: booking(now.Add(-time.Hour), 10*time.Minute), // note
+			checkResult: func(t *testing.T, violations []PolicyViolation) {
+				assert.Equal(t, []PolicyViolation{
+					PastBookingViolation{Start: now.Add(-time.Hour), Now: now},
+					MinDurationViolation{Min: 15 * time.Minute, Duration: 10 * time.Minute},
+				}, violations)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkResult(t, policy.Check(tc.booking))
+		})
+	}
+}
+
+func Test_BookingPoliciesDisabled(t *testing.T) {
+	now := time.Now()
+
+	policy := BookingPolicies{MinDuration(0), MaxDuration(0), AdvanceWindow(0), OwnerQuota(0)}
+
+	assert.Empty(t, policy.Check(Booking{
+		Owner:       "alice",
+		TimeRange:   TimeRange{Start: now.Add(10 * 365 * 24 * time.Hour), End: now.Add(11 * 365 * 24 * time.Hour)},
+		Now:         now,
+		OwnerActive: 1000,
+	}))
+}
+
+func Test_CheckPolicy(t *testing.T) {
+	now := time.Now()
+	tr := TimeRange{Start: now.Add(time.Hour), End: now.Add(time.Hour + time.Minute)}
+
+	assert.NoError(t, CheckPolicy(BookingPolicies{}, Booking{TimeRange: tr, Now: now}))
+
+	err := CheckPolicy(MinDuration(5*time.Minute), Booking{TimeRange: tr, Now: now})
+	assert.ErrorIs(t, err, &PolicyViolationError{})
+
+	var violation PolicyViolationError
+	assert.ErrorAs(t, err, &violation)
+	assert.Len(t, violation.Violations, 1)
+	assert.Equal(t, "min_duration", violation.Violations[0].Rule())
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+const (
+	DefaultReservationsLimit = 100
+	MaxReservationsLimit     = 1000
+
+	// окно поиска свободного времени, чтобы не поднимать всю историю комнаты
+	MaxAvailabilityWindow = 31 * 24 * time.Hour
+)
+
+// ReservationCursor позиция в выдаче для keyset пагинации по (start_time, id)
+type ReservationCursor struct {
+	StartTime time.Time
+	ID        int64
+}
+
+// ReservationFilter нулевые From/To означают отсутствие ограничения с этой стороны,
+// нулевой Limit в запросе к репозиторию - выдачу без ограничения
+type ReservationFilter struct {
+	From  time.Time
+	To    time.Time
+	After *ReservationCursor
+	Limit int
+}
+
+type ReservationQuery struct {
+	RoomID RoomID
+	ReservationFilter
+}
+
+func NewReservationQuery(roomID string, filter ReservationFilter) (ReservationQuery, error) {
+	rID, err := NewRoomID(roomID)
+	if err != nil {
+		return ReservationQuery{}, err
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return ReservationQuery{},
+			fmt.Errorf("NewReservationQuery: %w: from is after to", internal.ErrValidationFailed)
+	}
+	if filter.After != nil && filter.After.ID <= 0 {
+		return ReservationQuery{},
+			fmt.Errorf("NewReservationQuery: %w: cursor ID should be positive number", internal.ErrValidationFailed)
+	}
+
+	switch {
+	case filter.Limit < 0 || filter.Limit > MaxReservationsLimit:
+		return ReservationQuery{},
+			fmt.Errorf("NewReservationQuery: %w: limit should be in range [1, %d]", internal.ErrValidationFailed, MaxReservationsLimit)
+	case filter.Limit == 0:
+		filter.Limit = DefaultReservationsLimit
+	}
+
+	return ReservationQuery{
+		RoomID:            rID,
+		ReservationFilter: filter,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+func Test_ReservationQuery(t *testing.T) {
+	type args struct {
+		roomID string
+		filter ReservationFilter
+	}
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultArgs := args{
+		roomID: "1",
+		filter: ReservationFilter{
+			From:  now,
+			To:    now.Add(1 * time.Hour),
+			After: &ReservationCursor{StartTime: now, ID: 1},
+			Limit: 10,
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		checkResult func(t *testing.T, query ReservationQuery, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, ReservationQuery{
+					RoomID:            RoomID(defaultArgs.roomID),
+					ReservationFilter: defaultArgs.filter,
+				}, query)
+			},
+		},
+		{
+			name: "OK default limit",
+			args: args{
+				roomID: defaultArgs.roomID,
+				filter: ReservationFilter{}, // note
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, DefaultReservationsLimit, query.Limit)
+				assert.True(t, query.From.IsZero())
+				assert.True(t, query.To.IsZero())
+				assert.Nil(t, query.After)
+			},
+		},
+		{
+			name: "OK only from",
+			args: args{
+				roomID: defaultArgs.roomID,
+				filter: ReservationFilter{From: now}, // note
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, now, query.From)
+			},
+		},
+		{
+			name: "NOT OK error from NewRoomID",
+			args: args{
+				roomID: "", // note
+				filter: defaultArgs.filter,
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, query)
+			},
+		},
+		{
+			name: "NOT OK from equal to",
+			args: args{
+				roomID: defaultArgs.roomID,
+				filter: ReservationFilter{From: now, To: now}, // note
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, query)
+			},
+		},
+		{
+			name: "NOT OK cursor id",
+			args: args{
+				roomID: defaultArgs.roomID,
+				filter: ReservationFilter{After: &ReservationCursor{StartTime: now}}, // note
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, query)
+			},
+		},
+		{
+			name: "NOT OK negative limit",
+			args: args{
+				roomID: defaultArgs.roomID,
+				filter: ReservationFilter{Limit: -1}, // note
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, query)
+			},
+		},
+		{
+			name: "NOT OK too big limit",
+			args: args{
+				roomID: defaultArgs.roomID,
+				filter: ReservationFilter{Limit: MaxReservationsLimit + 1}, // note
+			},
+			checkResult: func(t *testing.T, query ReservationQuery, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, query)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := NewReservationQuery(tc.args.roomID, tc.args.filter)
+			tc.checkResult(t, query, err)
+		})
+	}
+}
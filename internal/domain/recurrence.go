@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+// ограничения серии, чтобы один запрос не раздувал таблицу бронирований
+const (
+	MaxRecurrenceOccurrences = 366
+	maxRecurrenceRuleLen     = 256
+	maxRecurrenceDays        = 5 * 366
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+// RecurrenceRule подмножество RRULE из RFC 5545:
+// FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, COUNT или UNTIL, BYDAY без порядковых номеров
+type RecurrenceRule struct {
+	Freq     Frequency
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+func ParseRecurrenceRule(rule string) (RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: empty rule", internal.ErrValidationFailed)
+	}
+	if len(rule) > maxRecurrenceRuleLen {
+		return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: rule is longer than %d", internal.ErrValidationFailed, maxRecurrenceRuleLen)
+	}
+
+	r := RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: invalid part %q", internal.ErrValidationFailed, part)
+		}
+		key = strings.ToUpper(key)
+		value = strings.ToUpper(value)
+
+		if seen[key] {
+			return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: duplicated %s", internal.ErrValidationFailed, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if r.Freq != FrequencyDaily && r.Freq != FrequencyWeekly && r.Freq != FrequencyMonthly {
+				err = fmt.Errorf("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval <= 0 {
+				err = fmt.Errorf("INTERVAL should be positive number")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && (r.Count <= 0 || r.Count > MaxRecurrenceOccurrences) {
+				err = fmt.Errorf("COUNT should be in range [1, %d]", MaxRecurrenceOccurrences)
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					err = fmt.Errorf("unsupported BYDAY value %s", day)
+					break
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: %s", internal.ErrValidationFailed, err.Error())
+		}
+	}
+
+	if r.Freq == "" {
+		return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: FREQ is required", internal.ErrValidationFailed)
+	}
+	// по RFC 5545 COUNT и UNTIL взаимоисключающие, а бесконечные серии мы не храним
+	if r.Count > 0 && !r.Until.IsZero() {
+		return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: COUNT and UNTIL are mutually exclusive", internal.ErrValidationFailed)
+	}
+	if r.Count == 0 && r.Until.IsZero() {
+		return RecurrenceRule{}, fmt.Errorf("ParseRecurrenceRule: %w: COUNT or UNTIL is required", internal.ErrValidationFailed)
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	// UNTIL в виде даты включает весь день
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL %s", value)
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// Expand разворачивает правило в отрезки той же длины, что и first.
+// Первое вхождение - первый подходящий под правило день начиная с first.Start.
+// Дни и время начала считаются по часам в поясе loc (nil - UTC): встреча в 17:00
+// остается в 17:00 и после перевода часов, отрезки возвращаются в UTC
+func (r RecurrenceRule) Expand(first TimeRange, loc *time.Location) ([]TimeRange, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	duration := first.End.Sub(first.Start)
+	start := first.Start.In(loc)
+	// календарные дни в поясе loc ведем в UTC, чтобы в сутках всегда было 24 часа
+	day0 := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	var out []TimeRange
+	for i := 0; i < maxRecurrenceDays; i++ {
+		day := day0.AddDate(0, 0, i)
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(),
+			start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), loc).UTC()
+
+		if !r.Until.IsZero() && occurrence.After(r.Until) {
+			return out, r.checkNotEmpty(out)
+		}
+		if !r.matches(start, day0, day) {
+			continue
+		}
+
+		out = append(out, TimeRange{Start: occurrence, End: occurrence.Add(duration)})
+		if len(out) > MaxRecurrenceOccurrences {
+			return nil, fmt.Errorf("Expand: %w: more than %d occurrences", internal.ErrValidationFailed, MaxRecurrenceOccurrences)
+		}
+		if r.Count > 0 && len(out) == r.Count {
+			return out, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Expand: %w: series is longer than %d days", internal.ErrValidationFailed, maxRecurrenceDays)
+}
+
+func (r RecurrenceRule) checkNotEmpty(out []TimeRange) error {
+	if len(out) == 0 {
+		return fmt.Errorf("Expand: %w: rule produces no occurrences", internal.ErrValidationFailed)
+	}
+	return nil
+}
+
+func (r RecurrenceRule) matches(start, day0, day time.Time) bool {
+	var period int
+	switch r.Freq {
+	case FrequencyDaily:
+		period = int(day.Sub(day0).Hours() / 24)
+	case FrequencyWeekly:
+		// неделя начинается с понедельника (WKST=MO)
+		period = int(weekStart(day).Sub(weekStart(day0)).Hours() / (24 * 7))
+	case FrequencyMonthly:
+		period = (day.Year()-day0.Year())*12 + int(day.Month()-day0.Month())
+	}
+	if period%r.Interval != 0 {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		for _, wd := range r.ByDay {
+			if day.Weekday() == wd {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch r.Freq {
+	case FrequencyWeekly:
+		return day.Weekday() == start.Weekday()
+	case FrequencyMonthly:
+		// месяцы без такого числа пропускаются, как и в RFC 5545
+		return day.Day() == start.Day()
+	}
+	return true
+}
+
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+type ReservationSeries struct {
+	ID           int64
+	RoomID       RoomID
+	Rule         string
+	Reservations []Reservation
+}
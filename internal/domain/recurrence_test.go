@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+func Test_ParseRecurrenceRule(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rule        string
+		checkResult func(t *testing.T, rule RecurrenceRule, err error)
+	}{
+		{
+			name: "OK weekly with count and byday",
+			rule: "FREQ=WEEKLY;COUNT=52;BYDAY=MO,WE",
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, RecurrenceRule{
+					Freq:     FrequencyWeekly,
+					Interval: 1,
+					Count:    52,
+					ByDay:    []time.Weekday{time.Monday, time.Wednesday},
+				}, rule)
+			},
+		},
+		{
+			name: "OK with prefix, interval and until datetime",
+			rule: "RRULE:FREQ=DAILY;INTERVAL=2;UNTIL=20240110T100000Z",
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, FrequencyDaily, rule.Freq)
+				assert.Equal(t, 2, rule.Interval)
+				assert.Equal(t, time.Date(2024, time.January, 10, 10, 0, 0, 0, time.UTC), rule.Until)
+			},
+		},
+		{
+			name: "OK until date includes whole day",
+			rule: "freq=monthly;until=20240110",
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, FrequencyMonthly, rule.Freq)
+				assert.Equal(t, time.Date(2024, time.January, 10, 23, 59, 59, 0, time.UTC), rule.Until)
+			},
+		},
+		{
+			name: "NOT OK empty rule",
+			rule: "", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK without freq",
+			rule: "COUNT=10", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK unsupported freq",
+			rule: "FREQ=YEARLY;COUNT=10", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK infinite series",
+			rule: "FREQ=DAILY", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK count and until",
+			rule: "FREQ=DAILY;COUNT=2;UNTIL=20240110", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK count too big",
+			rule: "FREQ=DAILY;COUNT=367", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK zero interval",
+			rule: "FREQ=DAILY;COUNT=2;INTERVAL=0", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK byday with ordinal",
+			rule: "FREQ=MONTHLY;COUNT=2;BYDAY=1MO", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK unsupported part",
+			rule: "FREQ=DAILY;COUNT=2;BYHOUR=10", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK duplicated part",
+			rule: "FREQ=DAILY;COUNT=2;COUNT=3", // note
+			checkResult: func(t *testing.T, rule RecurrenceRule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tc.rule)
+			tc.checkResult(t, rule, err)
+		})
+	}
+}
+
+func Test_RecurrenceRuleExpand(t *testing.T) {
+	// понедельник
+	start := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	first := TimeRange{Start: start, End: start.Add(30 * time.Minute)}
+
+	at := func(year int, month time.Month, day int) TimeRange {
+		s := time.Date(year, month, day, 10, 0, 0, 0, time.UTC)
+		return TimeRange{Start: s, End: s.Add(30 * time.Minute)}
+	}
+
+	testCases := []struct {
+		name        string
+		rule        string
+		checkResult func(t *testing.T, ranges []TimeRange, err error)
+	}{
+		{
+			name: "OK daily count",
+			rule: "FREQ=DAILY;COUNT=3",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 1), at(2024, 1, 2), at(2024, 1, 3)}, ranges)
+			},
+		},
+		{
+			name: "OK daily interval until",
+			rule: "FREQ=DAILY;INTERVAL=2;UNTIL=20240105T100000Z",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 1), at(2024, 1, 3), at(2024, 1, 5)}, ranges)
+			},
+		},
+		{
+			name: "OK weekly without byday keeps weekday",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 1), at(2024, 1, 8), at(2024, 1, 15)}, ranges)
+			},
+		},
+		{
+			name: "OK weekly byday",
+			rule: "FREQ=WEEKLY;COUNT=4;BYDAY=MO,FR",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 1), at(2024, 1, 5), at(2024, 1, 8), at(2024, 1, 12)}, ranges)
+			},
+		},
+		{
+			name: "OK biweekly byday skips weeks",
+			rule: "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,TH",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 2), at(2024, 1, 4), at(2024, 1, 16), at(2024, 1, 18)}, ranges)
+			},
+		},
+		{
+			name: "OK weekly 52 standups",
+			rule: "FREQ=WEEKLY;COUNT=52",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, ranges, 52)
+				assert.Equal(t, at(2024, 12, 23), ranges[51])
+			},
+		},
+		{
+			name: "OK monthly",
+			rule: "FREQ=MONTHLY;COUNT=3",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 1), at(2024, 2, 1), at(2024, 3, 1)}, ranges)
+			},
+		},
+		{
+			name: "OK monthly byday every monday of month",
+			rule: "FREQ=MONTHLY;UNTIL=20240131;BYDAY=MO",
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []TimeRange{at(2024, 1, 1), at(2024, 1, 8), at(2024, 1, 15), at(2024, 1, 22), at(2024, 1, 29)}, ranges)
+			},
+		},
+		{
+			name: "NOT OK until before first occurrence",
+			rule: "FREQ=DAILY;UNTIL=20231231", // note
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name: "NOT OK too many occurrences",
+			rule: "FREQ=DAILY;UNTIL=20260101", // note
+			checkResult: func(t *testing.T, ranges []TimeRange, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tc.rule)
+			assert.NoError(t, err)
+
+			ranges, err := rule.Expand(first, time.UTC)
+			tc.checkResult(t, ranges, err)
+		})
+	}
+
+	t.Run("OK monthly skips short months", func(t *testing.T) {
+		s := time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)
+
+		rule, err := ParseRecurrenceRule("FREQ=MONTHLY;COUNT=3")
+		assert.NoError(t, err)
+
+		ranges, err := rule.Expand(TimeRange{Start: s, End: s.Add(30 * time.Minute)}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimeRange{at(2024, 1, 31), at(2024, 3, 31), at(2024, 5, 31)}, ranges)
+	})
+
+	t.Run("OK weekly keeps local time across DST", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+
+		// 17:00-18:00 по Берлину, 31 марта 2024 часы переводятся на летнее время
+		local := func(day int) TimeRange {
+			s := time.Date(2024, time.March, day, 17, 0, 0, 0, berlin)
+			return TimeRange{Start: s.UTC(), End: s.Add(1 * time.Hour).UTC()}
+		}
+
+		rule, err := ParseRecurrenceRule("FREQ=WEEKLY;COUNT=3")
+		assert.NoError(t, err)
+
+		ranges, err := rule.Expand(local(25), berlin)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimeRange{local(25), local(32), local(39)}, ranges)
+		assert.Equal(t, 16, ranges[0].Start.Hour())
+		assert.Equal(t, 15, ranges[1].Start.Hour())
+	})
+}
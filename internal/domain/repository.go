@@ -1,8 +1,72 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type ReservationRepository interface {
-	Create(ctx context.Context, roomID RoomID, timeRange TimeRange) error
-	ListByRoom(ctx context.Context, roomID RoomID) ([]Reservation, error)
+	Create(ctx context.Context, roomID RoomID, timeRange TimeRange, details ReservationDetails) (Reservation, error)
+	// CreateHold создает held бронирование, которое истекает через ttl по часам базы
+	CreateHold(ctx context.Context, roomID RoomID, timeRange TimeRange, details ReservationDetails, ttl time.Duration) (Reservation, error)
+	CreateSeries(ctx context.Context, roomID RoomID, rule string, timeRanges []TimeRange, details ReservationDetails) (ReservationSeries, error)
+	GetByID(ctx context.Context, id int64) (Reservation, error)
+	ListByRoom(ctx context.Context, query ReservationQuery) ([]Reservation, error)
+	FindOverlapping(ctx context.Context, roomID RoomID, timeRange TimeRange) ([]Reservation, error)
+	// Update и Delete выполняются, только если version совпадает с текущей версией бронирования,
+	// иначе ErrReservationVersionMismatch. Update возвращает новую версию
+	Update(ctx context.Context, id int64, timeRange TimeRange, version int64) (int64, error)
+	Delete(ctx context.Context, id int64, version int64) error
+	// Confirm подтверждает неистекшую held бронь и возвращает ее новую версию.
+	// Истекшая бронь - ErrHoldExpired, уже подтвержденная - ErrReservationNotHeld
+	Confirm(ctx context.Context, id int64, version int64) (int64, error)
+	// ListExpiredHoldRooms комнаты, в которых есть истекшие held брони
+	ListExpiredHoldRooms(ctx context.Context) ([]RoomID, error)
+	// DeleteExpiredHolds удаляет истекшие held брони комнаты и возвращает их
+	DeleteExpiredHolds(ctx context.Context, roomID RoomID) ([]Reservation, error)
+	// CountActiveByOwner сколько у owner незакончившихся бронирований, кроме excludeID (0 - считаются все)
+	CountActiveByOwner(ctx context.Context, owner string, excludeID int64) (int, error)
+	// LockOwner блокирует бронирования owner до конца транзакции, чтобы квоту
+	// не обошли параллельными бронированиями разных комнат
+	LockOwner(ctx context.Context, owner string) error
+}
+
+type RoomRepository interface {
+	Create(ctx context.Context, room Room) (Room, error)
+	GetByID(ctx context.Context, id RoomID) (Room, error)
+	List(ctx context.Context) ([]Room, error)
+	ListAvailable(ctx context.Context, timeRange TimeRange, minCapacity int) ([]Room, error)
+	Update(ctx context.Context, room Room) (Room, error)
+	Delete(ctx context.Context, id RoomID) error
+}
+
+type ScheduleRepository interface {
+	// GetSchedule расписание комнаты, у комнаты без часов работы Hours пустой
+	GetSchedule(ctx context.Context, roomID RoomID) (RoomSchedule, error)
+	// ListSchedules расписания нескольких комнат одним запросом, несуществующих комнат в результате нет
+	ListSchedules(ctx context.Context, roomIDs []RoomID) (map[RoomID]RoomSchedule, error)
+	// SetSchedule полностью заменяет часы работы и часовой пояс комнаты
+	SetSchedule(ctx context.Context, schedule RoomSchedule) error
+	FindBlackouts(ctx context.Context, roomID RoomID, timeRange TimeRange) ([]Blackout, error)
+	CreateBlackout(ctx context.Context, blackout Blackout) (Blackout, error)
+	DeleteBlackout(ctx context.Context, roomID RoomID, id int64) error
+}
+
+type WaitlistRepository interface {
+	Create(ctx context.Context, roomID RoomID, timeRange TimeRange, details ReservationDetails) (WaitlistEntry, error)
+	GetByID(ctx context.Context, id int64) (WaitlistEntry, error)
+	// FindWaiting ждущие запросы комнаты, пересекающиеся с timeRange, в порядке постановки в очередь
+	FindWaiting(ctx context.Context, roomID RoomID, timeRange TimeRange) ([]WaitlistEntry, error)
+	// Promote отмечает ждущий запрос как ставший бронированием reservationID
+	Promote(ctx context.Context, id int64, reservationID int64) error
+}
+
+type IdempotencyRepository interface {
+	// Claim сохраняет ключ без ответа, если его еще нет (claimed = true),
+	// иначе возвращает уже сохраненную запись. Ключ того же запроса, оставшийся без ответа
+	// дольше abandonAfter, считается брошенным и занимается заново
+	Claim(ctx context.Context, key IdempotencyKey, requestHash string, abandonAfter time.Duration) (record IdempotencyRecord, claimed bool, err error)
+	SaveResponse(ctx context.Context, key IdempotencyKey, response IdempotentResponse) error
+	Delete(ctx context.Context, key IdempotencyKey) error
+	DeleteExpired(ctx context.Context, createdBefore time.Time) (int64, error)
 }
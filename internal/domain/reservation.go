@@ -2,15 +2,95 @@ package domain
 
 import (
 	"fmt"
+	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ynuraddi/test-kami/internal"
 )
 
+// ReservationStatus held - временная бронь, которая снимается после ExpiresAt,
+// если ее не подтвердили
+type ReservationStatus string
+
+const (
+	StatusConfirmed ReservationStatus = "confirmed"
+	StatusHeld      ReservationStatus = "held"
+
+	// MaxHoldTTL сколько максимум может висеть неподтвержденная бронь
+	MaxHoldTTL = 1 * time.Hour
+)
+
 type Reservation struct {
 	ID        int64
 	RoomID    RoomID
 	TimeRange TimeRange
+	// Version увеличивается при каждом изменении, изменения с устаревшей версией отклоняются
+	Version int64
+	Status  ReservationStatus
+	// ExpiresAt задан только у held
+	ExpiresAt *time.Time
+	Details   ReservationDetails
+}
+
+const (
+	maxOwnerLen       = 128
+	maxTitleLen       = 200
+	maxDescriptionLen = 2000
+	maxAttendeeLen    = 128
+
+	// MaxAttendees сколько участников можно указать в одном бронировании
+	MaxAttendees = 100
+)
+
+// ReservationDetails кто бронирует, зачем и кто придет.
+// Пустой Owner - анонимное бронирование, все поля необязательные
+type ReservationDetails struct {
+	Owner       string
+	Title       string
+	Description string
+	// Attendees участники встречи, без повторов
+	Attendees []string
+}
+
+func (d ReservationDetails) Validate() error {
+	if utf8.RuneCountInString(d.Owner) > maxOwnerLen {
+		return fmt.Errorf("ReservationDetails: %w: len of owner should be less than %d", internal.ErrValidationFailed, maxOwnerLen)
+	}
+	if utf8.RuneCountInString(d.Title) > maxTitleLen {
+		return fmt.Errorf("ReservationDetails: %w: len of title should be less than %d", internal.ErrValidationFailed, maxTitleLen)
+	}
+	if utf8.RuneCountInString(d.Description) > maxDescriptionLen {
+		return fmt.Errorf("ReservationDetails: %w: len of description should be less than %d", internal.ErrValidationFailed, maxDescriptionLen)
+	}
+	if len(d.Attendees) > MaxAttendees {
+		return fmt.Errorf("ReservationDetails: %w: attendees count should be less than %d", internal.ErrValidationFailed, MaxAttendees)
+	}
+
+	seen := make(map[string]struct{}, len(d.Attendees))
+	for _, attendee := range d.Attendees {
+		if utf8.RuneCountInString(attendee) == 0 || utf8.RuneCountInString(attendee) > maxAttendeeLen {
+			return fmt.Errorf("ReservationDetails: %w: len of attendee should be in range [1, %d]", internal.ErrValidationFailed, maxAttendeeLen)
+		}
+		if _, ok := seen[attendee]; ok {
+			return fmt.Errorf("ReservationDetails: %w: attendee %q is listed twice", internal.ErrValidationFailed, attendee)
+		}
+		seen[attendee] = struct{}{}
+	}
+	return nil
+}
+
+// Headcount сколько человек придет. Владелец, если придет сам, указывается среди Attendees
+func (d ReservationDetails) Headcount() int {
+	return len(d.Attendees)
+}
+
+// CheckHoldTTL срок временной брони должен быть в (0, MaxHoldTTL]
+func CheckHoldTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxHoldTTL {
+		return fmt.Errorf("CheckHoldTTL: %w: hold ttl should be in range (0, %s]", internal.ErrValidationFailed, MaxHoldTTL)
+	}
+	return nil
 }
 
 func NewReservation(id int64, roomUUID string, from, to time.Time) (Reservation, error) {
@@ -52,6 +132,60 @@ func (t TimeRange) CrossWith(other TimeRange) bool {
 	return t.Start.Before(other.End) && other.Start.Before(t.End)
 }
 
+func (t TimeRange) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// MergeTimeRanges склеивает пересекающиеся и соприкасающиеся отрезки,
+// результат отсортирован по началу
+func MergeTimeRanges(ranges []TimeRange) []TimeRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sorted := make([]TimeRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	merged := []TimeRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start.After(last.End) {
+			merged = append(merged, r)
+			continue
+		}
+		if r.End.After(last.End) {
+			last.End = r.End
+		}
+	}
+	return merged
+}
+
+// Subtract возвращает части t, не покрытые ни одним из others
+func (t TimeRange) Subtract(others []TimeRange) []TimeRange {
+	var free []TimeRange
+
+	cursor := t.Start
+	for _, busy := range MergeTimeRanges(others) {
+		if !busy.CrossWith(t) {
+			continue
+		}
+		if busy.Start.After(cursor) {
+			free = append(free, TimeRange{Start: cursor, End: busy.Start})
+		}
+		if busy.End.After(cursor) {
+			cursor = busy.End
+		}
+	}
+	if cursor.Before(t.End) {
+		free = append(free, TimeRange{Start: cursor, End: t.End})
+	}
+
+	return free
+}
+
 func NewTimeRange(from, to time.Time) (TimeRange, error) {
 	if from.After(to) || from.Equal(to) {
 		return TimeRange{},
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -264,6 +265,131 @@ func Test_TimeRange_IsCross(t *testing.T) {
 	}
 }
 
+func Test_MergeTimeRanges(t *testing.T) {
+	base := time.Now().Truncate(time.Hour).UTC() // image its 12:00
+
+	at := func(from, to int) TimeRange {
+		return TimeRange{
+			Start: base.Add(time.Duration(from) * time.Minute),
+			End:   base.Add(time.Duration(to) * time.Minute),
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		ranges []TimeRange
+		want   []TimeRange
+	}{
+		{
+			name:   "OK empty",
+			ranges: nil,
+			want:   nil,
+		},
+		{
+			name:   "OK single",
+			ranges: []TimeRange{at(0, 30)},
+			want:   []TimeRange{at(0, 30)},
+		},
+		{
+			name:   "OK disjoint unsorted",
+			ranges: []TimeRange{at(60, 90), at(0, 30)}, // 13:00 - 13:30, 12:00 - 12:30
+			want:   []TimeRange{at(0, 30), at(60, 90)},
+		},
+		{
+			name:   "OK overlapping",
+			ranges: []TimeRange{at(0, 30), at(15, 45)}, // 12:00 - 12:30, 12:15 - 12:45
+			want:   []TimeRange{at(0, 45)},
+		},
+		{
+			name:   "OK touching",
+			ranges: []TimeRange{at(0, 30), at(30, 60)}, // 12:00 - 12:30, 12:30 - 13:00
+			want:   []TimeRange{at(0, 60)},
+		},
+		{
+			name:   "OK nested",
+			ranges: []TimeRange{at(0, 60), at(15, 30)}, // 12:00 - 13:00, 12:15 - 12:30
+			want:   []TimeRange{at(0, 60)},
+		},
+		{
+			name:   "OK chain",
+			ranges: []TimeRange{at(40, 70), at(0, 20), at(10, 45), at(90, 100)},
+			want:   []TimeRange{at(0, 70), at(90, 100)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MergeTimeRanges(tc.ranges))
+		})
+	}
+}
+
+func Test_TimeRange_Subtract(t *testing.T) {
+	base := time.Now().Truncate(time.Hour).UTC() // image its 12:00
+
+	at := func(from, to int) TimeRange {
+		return TimeRange{
+			Start: base.Add(time.Duration(from) * time.Minute),
+			End:   base.Add(time.Duration(to) * time.Minute),
+		}
+	}
+
+	window := at(0, 120) // 12:00 - 14:00
+
+	testCases := []struct {
+		name   string
+		others []TimeRange
+		want   []TimeRange
+	}{
+		{
+			name:   "OK nothing busy",
+			others: nil,
+			want:   []TimeRange{window},
+		},
+		{
+			name:   "OK busy outside window",
+			others: []TimeRange{at(-60, 0), at(120, 180)}, // 11:00 - 12:00, 14:00 - 15:00
+			want:   []TimeRange{window},
+		},
+		{
+			name:   "OK busy in the middle",
+			others: []TimeRange{at(30, 60)}, // 12:30 - 13:00
+			want:   []TimeRange{at(0, 30), at(60, 120)},
+		},
+		{
+			name:   "OK busy crossing start",
+			others: []TimeRange{at(-30, 30)}, // 11:30 - 12:30
+			want:   []TimeRange{at(30, 120)},
+		},
+		{
+			name:   "OK busy crossing end",
+			others: []TimeRange{at(90, 150)}, // 13:30 - 14:30
+			want:   []TimeRange{at(0, 90)},
+		},
+		{
+			name:   "OK overlapping busy merged",
+			others: []TimeRange{at(40, 70), at(20, 50), at(100, 110)},
+			want:   []TimeRange{at(0, 20), at(70, 100), at(110, 120)},
+		},
+		{
+			name:   "OK whole window busy",
+			others: []TimeRange{at(-10, 130)},
+			want:   nil,
+		},
+		{
+			name:   "OK back to back busy cover window",
+			others: []TimeRange{at(0, 60), at(60, 120)},
+			want:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, window.Subtract(tc.others))
+		})
+	}
+}
+
 func Test_TimeRange_String(t *testing.T) {
 	from := time.Now().Truncate(time.Second).UTC()
 	to := from.Add(time.Minute)
@@ -327,3 +453,103 @@ func Test_RoomID(t *testing.T) {
 		})
 	}
 }
+
+func Test_CheckHoldTTL(t *testing.T) {
+	assert.NoError(t, CheckHoldTTL(time.Minute))
+	assert.NoError(t, CheckHoldTTL(MaxHoldTTL))
+
+	for _, invalid := range []time.Duration{0, -time.Minute, MaxHoldTTL + time.Second} {
+		assert.ErrorIs(t, CheckHoldTTL(invalid), internal.ErrValidationFailed)
+	}
+}
+
+func Test_ReservationDetails(t *testing.T) {
+	attendees := func(n int) []string {
+		out := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			out = append(out, fmt.Sprintf("user-%d", i))
+		}
+		return out
+	}
+
+	testCases := []struct {
+		name        string
+		details     ReservationDetails
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name:    "OK empty",
+			details: ReservationDetails{},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "OK on limits",
+			details: ReservationDetails{
+				Owner:       strings.Repeat("я", maxOwnerLen),
+				Title:       strings.Repeat("я", maxTitleLen),
+				Description: strings.Repeat("я", maxDescriptionLen),
+				Attendees:   attendees(MaxAttendees),
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "NOT OK owner too long",
+			details: ReservationDetails{Owner: strings.Repeat("я", maxOwnerLen+1)}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "NOT OK title too long",
+			details: ReservationDetails{Title: strings.Repeat("я", maxTitleLen+1)}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "NOT OK description too long",
+			details: ReservationDetails{Description: strings.Repeat("я", maxDescriptionLen+1)}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "NOT OK too many attendees",
+			details: ReservationDetails{Attendees: attendees(MaxAttendees + 1)}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "NOT OK empty attendee",
+			details: ReservationDetails{Attendees: []string{"bob", ""}}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "NOT OK attendee too long",
+			details: ReservationDetails{Attendees: []string{strings.Repeat("a", maxAttendeeLen+1)}}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+		{
+			name:    "NOT OK duplicate attendee",
+			details: ReservationDetails{Attendees: []string{"bob", "carol", "bob"}}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkResult(t, tc.details.Validate())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+const (
+	maxRoomNameLen     = 128
+	maxRoomLocationLen = 256
+
+	MaxRoomBuffer = 4 * time.Hour
+)
+
+type Room struct {
+	ID       RoomID
+	Name     string
+	Capacity int
+	Location string
+	Active   bool
+	Buffer   RoomBuffer
+}
+
+func NewRoom(roomID, name string, capacity int, location string, active bool, buffer RoomBuffer) (Room, error) {
+	rID, err := NewRoomID(roomID)
+	if err != nil {
+		return Room{}, err
+	}
+
+	if utf8.RuneCountInString(name) == 0 || utf8.RuneCountInString(name) > maxRoomNameLen {
+		return Room{},
+			fmt.Errorf("NewRoom: %w: len of name should be in range [1, %d]", internal.ErrValidationFailed, maxRoomNameLen)
+	}
+	if capacity <= 0 {
+		return Room{},
+			fmt.Errorf("NewRoom: %w: capacity should be positive number", internal.ErrValidationFailed)
+	}
+	if utf8.RuneCountInString(location) > maxRoomLocationLen {
+		return Room{},
+			fmt.Errorf("NewRoom: %w: len of location should be less than %d", internal.ErrValidationFailed, maxRoomLocationLen)
+	}
+
+	return Room{
+		ID:       rID,
+		Name:     name,
+		Capacity: capacity,
+		Location: location,
+		Active:   active,
+		Buffer:   buffer,
+	}, nil
+}
+
+// CheckCapacity комната должна вместить всех участников, полностью заполненная комната подходит
+func (r Room) CheckCapacity(details ReservationDetails) error {
+	if headcount := details.Headcount(); headcount > r.Capacity {
+		return CapacityExceededError{RoomID: r.ID, Capacity: r.Capacity, Attendees: headcount}
+	}
+	return nil
+}
+
+// RoomBuffer время на подготовку комнаты до и уборку после каждого бронирования
+type RoomBuffer struct {
+	Before time.Duration
+	After  time.Duration
+}
+
+func NewRoomBuffer(before, after time.Duration) (RoomBuffer, error) {
+	if before < 0 || before > MaxRoomBuffer {
+		return RoomBuffer{},
+			fmt.Errorf("NewRoomBuffer: %w: buffer before should be in range [0, %s]", internal.ErrValidationFailed, MaxRoomBuffer)
+	}
+	if after < 0 || after > MaxRoomBuffer {
+		return RoomBuffer{},
+			fmt.Errorf("NewRoomBuffer: %w: buffer after should be in range [0, %s]", internal.ErrValidationFailed, MaxRoomBuffer)
+	}
+
+	return RoomBuffer{
+		Before: before,
+		After:  after,
+	}, nil
+}
+
+// Occupied отрезок, который бронирование tr занимает вместе с буфером
+func (b RoomBuffer) Occupied(tr TimeRange) TimeRange {
+	return TimeRange{
+		Start: tr.Start.Add(-b.Before),
+		End:   tr.End.Add(b.After),
+	}
+}
+
+// Widen расширяет tr на оба буфера с каждой стороны: бронирования комнаты,
+// которые могут конфликтовать с tr, целиком пересекаются с этим отрезком
+func (b RoomBuffer) Widen(tr TimeRange) TimeRange {
+	total := b.Before + b.After
+	return TimeRange{
+		Start: tr.Start.Add(-total),
+		End:   tr.End.Add(total),
+	}
+}
+
+// Conflict пересекается ли tr с existing: сами бронирования или занятые с буфером отрезки
+func (b RoomBuffer) Conflict(tr, existing TimeRange) (ConflictReason, bool) {
+	if tr.CrossWith(existing) {
+		return ConflictBooking, true
+	}
+	if b.Occupied(tr).CrossWith(b.Occupied(existing)) {
+		return ConflictBuffer, true
+	}
+	return ConflictBooking, false
+}
@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+func Test_Room(t *testing.T) {
+	type args struct {
+		roomID   string
+		name     string
+		capacity int
+		location string
+		active   bool
+		buffer   RoomBuffer
+	}
+
+	defaultArgs := args{
+		roomID:   "conf-a",
+		name:     "Conference A",
+		capacity: 10,
+		location: "2nd floor",
+		active:   true,
+		buffer:   RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute},
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		checkResult func(t *testing.T, room Room, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, Room{
+					ID:       RoomID(defaultArgs.roomID),
+					Name:     defaultArgs.name,
+					Capacity: defaultArgs.capacity,
+					Location: defaultArgs.location,
+					Active:   defaultArgs.active,
+					Buffer:   defaultArgs.buffer,
+				}, room)
+			},
+		},
+		{
+			name: "OK empty location",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				name:     defaultArgs.name,
+				capacity: defaultArgs.capacity,
+				location: "", // note
+			},
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, room.Location)
+				assert.False(t, room.Active)
+			},
+		},
+		{
+			name: "NOT OK error from NewRoomID",
+			args: args{
+				roomID:   "", // note
+				name:     defaultArgs.name,
+				capacity: defaultArgs.capacity,
+			},
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK empty name",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				name:     "", // note
+				capacity: defaultArgs.capacity,
+			},
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK too long name",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				name:     strings.Repeat("a", maxRoomNameLen+1), // note
+				capacity: defaultArgs.capacity,
+			},
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK zero capacity",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				name:     defaultArgs.name,
+				capacity: 0, // note
+			},
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK too long location",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				name:     defaultArgs.name,
+				capacity: defaultArgs.capacity,
+				location: strings.Repeat("a", maxRoomLocationLen+1), // note
+			},
+			checkResult: func(t *testing.T, room Room, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			room, err := NewRoom(tc.args.roomID, tc.args.name, tc.args.capacity, tc.args.location, tc.args.active, tc.args.buffer)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_RoomBuffer(t *testing.T) {
+	buffer, err := NewRoomBuffer(5*time.Minute, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute}, buffer)
+
+	buffer, err = NewRoomBuffer(0, MaxRoomBuffer)
+	assert.NoError(t, err)
+	assert.Equal(t, RoomBuffer{After: MaxRoomBuffer}, buffer)
+
+	for _, invalid := range [][2]time.Duration{
+		{-time.Minute, 0},
+		{0, -time.Minute},
+		{MaxRoomBuffer + time.Second, 0},
+		{0, MaxRoomBuffer + time.Second},
+	} {
+		buffer, err := NewRoomBuffer(invalid[0], invalid[1])
+		assert.ErrorIs(t, err, internal.ErrValidationFailed)
+		assert.Empty(t, buffer)
+	}
+}
+
+func Test_RoomCheckCapacity(t *testing.T) {
+	room := Room{ID: "conf-a", Capacity: 2}
+
+	assert.NoError(t, room.CheckCapacity(ReservationDetails{}))
+	// полностью заполненная комната подходит
+	assert.NoError(t, room.CheckCapacity(ReservationDetails{Attendees: []string{"bob", "carol"}}))
+
+	err := room.CheckCapacity(ReservationDetails{Attendees: []string{"bob", "carol", "dave"}})
+	assert.Equal(t, CapacityExceededError{RoomID: "conf-a", Capacity: 2, Attendees: 3}, err)
+}
+
+func Test_RoomBufferConflict(t *testing.T) {
+	now := time.Now().Truncate(time.Hour).UTC()
+
+	// до каждой встречи 5 минут подготовки, после - 15 минут уборки
+	buffer := RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute}
+
+	existing := TimeRange{Start: now, End: now.Add(1 * time.Hour)}
+
+	assert.Equal(t, TimeRange{Start: now.Add(-5 * time.Minute), End: now.Add(75 * time.Minute)}, buffer.Occupied(existing))
+	assert.Equal(t, TimeRange{Start: now.Add(-20 * time.Minute), End: now.Add(80 * time.Minute)}, buffer.Widen(existing))
+
+	testCases := []struct {
+		name     string
+		buffer   RoomBuffer
+		tr       TimeRange
+		conflict bool
+		reason   ConflictReason
+	}{
+		{
+			name:     "overlap",
+			buffer:   buffer,
+			tr:       TimeRange{Start: now.Add(30 * time.Minute), End: now.Add(90 * time.Minute)},
+			conflict: true,
+			reason:   ConflictBooking,
+		},
+		{
+			name:     "back to back after",
+			buffer:   buffer,
+			tr:       TimeRange{Start: existing.End, End: existing.End.Add(1 * time.Hour)},
+			conflict: true,
+			reason:   ConflictBuffer,
+		},
+		{
+			name:     "back to back before",
+			buffer:   buffer,
+			tr:       TimeRange{Start: now.Add(-1 * time.Hour), End: now},
+			conflict: true,
+			reason:   ConflictBuffer,
+		},
+		{
+			name:     "exactly both buffers after",
+			buffer:   buffer,
+			tr:       TimeRange{Start: existing.End.Add(20 * time.Minute), End: existing.End.Add(80 * time.Minute)},
+			conflict: false,
+		},
+		{
+			name:     "one minute inside buffers after",
+			buffer:   buffer,
+			tr:       TimeRange{Start: existing.End.Add(19 * time.Minute), End: existing.End.Add(80 * time.Minute)},
+			conflict: true,
+			reason:   ConflictBuffer,
+		},
+		{
+			name:     "exactly both buffers before",
+			buffer:   buffer,
+			tr:       TimeRange{Start: now.Add(-80 * time.Minute), End: now.Add(-20 * time.Minute)},
+			conflict: false,
+		},
+		{
+			name:     "back to back without buffer",
+			buffer:   RoomBuffer{},
+			tr:       TimeRange{Start: existing.End, End: existing.End.Add(1 * time.Hour)},
+			conflict: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, conflict := tc.buffer.Conflict(tc.tr, existing)
+			assert.Equal(t, tc.conflict, conflict)
+			if tc.conflict {
+				assert.Equal(t, tc.reason, reason)
+			}
+		})
+	}
+}
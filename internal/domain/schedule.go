@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+const (
+	DefaultTimeZone = "UTC"
+
+	maxTimeZoneLen       = 64
+	maxBlackoutReasonLen = 256
+)
+
+// OpeningHours интервал [Open, Close) дня недели, время отсчитывается
+// от полуночи в часовом поясе комнаты
+type OpeningHours struct {
+	Weekday time.Weekday
+	Open    time.Duration
+	Close   time.Duration
+}
+
+// RoomSchedule часы работы комнаты. Комната без часов работы доступна круглосуточно
+type RoomSchedule struct {
+	RoomID   RoomID
+	Location *time.Location
+	Hours    []OpeningHours
+}
+
+func NewRoomSchedule(roomID, timeZone string, hours []OpeningHours) (RoomSchedule, error) {
+	rID, err := NewRoomID(roomID)
+	if err != nil {
+		return RoomSchedule{}, err
+	}
+
+	if timeZone == "" {
+		timeZone = DefaultTimeZone
+	}
+	if len(timeZone) > maxTimeZoneLen {
+		return RoomSchedule{},
+			fmt.Errorf("NewRoomSchedule: %w: len of time zone should be less than %d", internal.ErrValidationFailed, maxTimeZoneLen)
+	}
+	// "Local" зависит от окружения сервера, поэтому не считается IANA поясом
+	if timeZone == "Local" {
+		return RoomSchedule{},
+			fmt.Errorf("NewRoomSchedule: %w: unknown time zone %q", internal.ErrValidationFailed, timeZone)
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return RoomSchedule{},
+			fmt.Errorf("NewRoomSchedule: %w: unknown time zone %q", internal.ErrValidationFailed, timeZone)
+	}
+
+	sorted := make([]OpeningHours, len(hours))
+	copy(sorted, hours)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].Open < sorted[j].Open
+	})
+
+	for i, h := range sorted {
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return RoomSchedule{},
+				fmt.Errorf("NewRoomSchedule: %w: invalid weekday %d", internal.ErrValidationFailed, h.Weekday)
+		}
+		if h.Open < 0 || h.Close > 24*time.Hour || h.Open >= h.Close {
+			return RoomSchedule{},
+				fmt.Errorf("NewRoomSchedule: %w: %s hours should be in range [00:00, 24:00] and open before close", internal.ErrValidationFailed, h.Weekday)
+		}
+		if h.Open%time.Minute != 0 || h.Close%time.Minute != 0 {
+			return RoomSchedule{},
+				fmt.Errorf("NewRoomSchedule: %w: %s hours should be whole minutes", internal.ErrValidationFailed, h.Weekday)
+		}
+		if i > 0 && sorted[i-1].Weekday == h.Weekday && sorted[i-1].Close > h.Open {
+			return RoomSchedule{},
+				fmt.Errorf("NewRoomSchedule: %w: %s hours overlap", internal.ErrValidationFailed, h.Weekday)
+		}
+	}
+
+	return RoomSchedule{
+		RoomID:   rID,
+		Location: loc,
+		Hours:    sorted,
+	}, nil
+}
+
+// TimeZone имя часового пояса расписания
+func (s RoomSchedule) TimeZone() string {
+	if s.Location == nil {
+		return DefaultTimeZone
+	}
+	return s.Location.String()
+}
+
+// OpenIntervals отрезки окна window, в которые комната работает, в UTC.
+// Для комнаты без часов работы это все окно
+func (s RoomSchedule) OpenIntervals(window TimeRange) []TimeRange {
+	if len(s.Hours) == 0 {
+		return []TimeRange{window}
+	}
+
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var open []TimeRange
+	// с запасом в день с каждой стороны, чтобы не зависеть от смещения пояса
+	start := window.Start.In(loc).AddDate(0, 0, -1)
+	end := window.End.In(loc).AddDate(0, 0, 1)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, h := range s.Hours {
+			if h.Weekday != day.Weekday() {
+				continue
+			}
+			// время на часах, а не смещение от полуночи: в день перевода часов они расходятся
+			interval := TimeRange{
+				Start: time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(h.Open/time.Second), 0, loc).UTC(),
+				End:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(h.Close/time.Second), 0, loc).UTC(),
+			}
+			if !interval.CrossWith(window) {
+				continue
+			}
+			if interval.Start.Before(window.Start) {
+				interval.Start = window.Start
+			}
+			if interval.End.After(window.End) {
+				interval.End = window.End
+			}
+			open = append(open, interval)
+		}
+	}
+
+	// соседние дни с часами до 24:00 и с 00:00 склеиваются в один отрезок
+	return MergeTimeRanges(open)
+}
+
+// Covers целиком ли tr приходится на часы работы комнаты
+func (s RoomSchedule) Covers(tr TimeRange) bool {
+	return len(tr.Subtract(s.OpenIntervals(tr))) == 0
+}
+
+// CheckBookable бронирование tr должно целиком приходиться на часы работы
+// и не пересекаться ни с одним blackout из blackouts
+func (s RoomSchedule) CheckBookable(tr TimeRange, blackouts []Blackout) error {
+	for _, b := range blackouts {
+		if b.TimeRange.CrossWith(tr) {
+			blackout := b.TimeRange
+			return OutsideBookableHoursError{
+				Reservation: tr,
+				Blackout:    &blackout,
+			}
+		}
+	}
+	if !s.Covers(tr) {
+		return OutsideBookableHoursError{
+			Reservation: tr,
+		}
+	}
+	return nil
+}
+
+// Blackout период, когда комната недоступна независимо от часов работы, например обслуживание
+type Blackout struct {
+	ID        int64
+	RoomID    RoomID
+	TimeRange TimeRange
+	Reason    string
+}
+
+func NewBlackout(roomID string, from, to time.Time, reason string) (Blackout, error) {
+	rID, err := NewRoomID(roomID)
+	if err != nil {
+		return Blackout{}, err
+	}
+	tr, err := NewTimeRange(from, to)
+	if err != nil {
+		return Blackout{}, err
+	}
+	if utf8.RuneCountInString(reason) > maxBlackoutReasonLen {
+		return Blackout{},
+			fmt.Errorf("NewBlackout: %w: len of reason should be less than %d", internal.ErrValidationFailed, maxBlackoutReasonLen)
+	}
+
+	return Blackout{
+		RoomID:    rID,
+		TimeRange: tr,
+		Reason:    reason,
+	}, nil
+}
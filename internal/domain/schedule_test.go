@@ -0,0 +1,370 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+)
+
+func Test_RoomSchedule(t *testing.T) {
+	type args struct {
+		roomID   string
+		timeZone string
+		hours    []OpeningHours
+	}
+
+	defaultArgs := args{
+		roomID:   "conf-a",
+		timeZone: "Europe/Berlin",
+		hours: []OpeningHours{
+			{Weekday: time.Tuesday, Open: 9 * time.Hour, Close: 18 * time.Hour},
+			{Weekday: time.Monday, Open: 14 * time.Hour, Close: 18 * time.Hour},
+			{Weekday: time.Monday, Open: 9 * time.Hour, Close: 13 * time.Hour},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		checkResult func(t *testing.T, schedule RoomSchedule, err error)
+	}{
+		{
+			name: "OK hours sorted",
+			args: defaultArgs,
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, RoomID(defaultArgs.roomID), schedule.RoomID)
+				assert.Equal(t, defaultArgs.timeZone, schedule.TimeZone())
+				assert.Equal(t, []OpeningHours{
+					{Weekday: time.Monday, Open: 9 * time.Hour, Close: 13 * time.Hour},
+					{Weekday: time.Monday, Open: 14 * time.Hour, Close: 18 * time.Hour},
+					{Weekday: time.Tuesday, Open: 9 * time.Hour, Close: 18 * time.Hour},
+				}, schedule.Hours)
+			},
+		},
+		{
+			name: "OK default time zone and no hours",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: "", // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, DefaultTimeZone, schedule.TimeZone())
+				assert.Empty(t, schedule.Hours)
+			},
+		},
+		{
+			name: "OK hours until end of day",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: defaultArgs.timeZone,
+				hours:    []OpeningHours{{Weekday: time.Friday, Open: 0, Close: 24 * time.Hour}}, // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, schedule.Hours, 1)
+			},
+		},
+		{
+			name: "NOT OK error from NewRoomID",
+			args: args{
+				roomID:   "", // note
+				timeZone: defaultArgs.timeZone,
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK unknown time zone",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: "Mars/Olympus", // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK local time zone",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: "Local", // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK long time zone",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: strings.Repeat("a", maxTimeZoneLen+1), // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK invalid weekday",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: defaultArgs.timeZone,
+				hours:    []OpeningHours{{Weekday: 7, Open: 9 * time.Hour, Close: 18 * time.Hour}}, // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK open after close",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: defaultArgs.timeZone,
+				hours:    []OpeningHours{{Weekday: time.Monday, Open: 18 * time.Hour, Close: 9 * time.Hour}}, // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK close after end of day",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: defaultArgs.timeZone,
+				hours:    []OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 25 * time.Hour}}, // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK not whole minutes",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: defaultArgs.timeZone,
+				hours:    []OpeningHours{{Weekday: time.Monday, Open: 9*time.Hour + time.Second, Close: 18 * time.Hour}}, // note
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK overlapping hours",
+			args: args{
+				roomID:   defaultArgs.roomID,
+				timeZone: defaultArgs.timeZone,
+				hours: []OpeningHours{
+					{Weekday: time.Monday, Open: 9 * time.Hour, Close: 13 * time.Hour},
+					{Weekday: time.Monday, Open: 12 * time.Hour, Close: 18 * time.Hour}, // note
+				},
+			},
+			checkResult: func(t *testing.T, schedule RoomSchedule, err error) {
+				assert.ErrorIs(t, err, internal.ErrValidationFailed)
+				assert.Empty(t, schedule)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := NewRoomSchedule(tc.args.roomID, tc.args.timeZone, tc.args.hours)
+			tc.checkResult(t, schedule, err)
+		})
+	}
+}
+
+func Test_RoomScheduleOpenIntervals(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	// 2024-01-01 понедельник
+	monday := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		schedule RoomSchedule
+		window   TimeRange
+		expected []TimeRange
+	}{
+		{
+			name:     "OK no hours is whole window",
+			schedule: RoomSchedule{Location: time.UTC},
+			window:   TimeRange{Start: monday, End: monday.Add(48 * time.Hour)},
+			expected: []TimeRange{{Start: monday, End: monday.Add(48 * time.Hour)}},
+		},
+		{
+			name: "OK hours in room time zone",
+			schedule: RoomSchedule{
+				Location: berlin, // note
+				Hours:    []OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}},
+			},
+			window: TimeRange{Start: monday, End: monday.Add(7 * 24 * time.Hour)},
+			expected: []TimeRange{
+				{Start: monday.Add(8 * time.Hour), End: monday.Add(17 * time.Hour)},
+			},
+		},
+		{
+			name: "OK intervals cut by window",
+			schedule: RoomSchedule{
+				Location: time.UTC,
+				Hours:    []OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}},
+			},
+			window: TimeRange{Start: monday.Add(10 * time.Hour), End: monday.Add(12 * time.Hour)}, // note
+			expected: []TimeRange{
+				{Start: monday.Add(10 * time.Hour), End: monday.Add(12 * time.Hour)},
+			},
+		},
+		{
+			name: "OK hours across midnight merged",
+			schedule: RoomSchedule{
+				Location: time.UTC,
+				Hours: []OpeningHours{
+					{Weekday: time.Monday, Open: 20 * time.Hour, Close: 24 * time.Hour},
+					{Weekday: time.Tuesday, Open: 0, Close: 2 * time.Hour}, // note
+				},
+			},
+			window: TimeRange{Start: monday, End: monday.Add(48 * time.Hour)},
+			expected: []TimeRange{
+				{Start: monday.Add(20 * time.Hour), End: monday.Add(26 * time.Hour)},
+			},
+		},
+		{
+			// 10 марта 2024 в Нью-Йорке часы переводятся с 02:00 на 03:00
+			name: "OK daylight saving day is shorter",
+			schedule: RoomSchedule{
+				Location: newYork,
+				Hours:    []OpeningHours{{Weekday: time.Sunday, Open: 1 * time.Hour, Close: 4 * time.Hour}},
+			},
+			window: TimeRange{
+				Start: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+			},
+			expected: []TimeRange{
+				{
+					Start: time.Date(2024, time.March, 10, 6, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, time.March, 10, 8, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "OK closed in window",
+			schedule: RoomSchedule{
+				Location: time.UTC,
+				Hours:    []OpeningHours{{Weekday: time.Saturday, Open: 9 * time.Hour, Close: 18 * time.Hour}},
+			},
+			window:   TimeRange{Start: monday, End: monday.Add(24 * time.Hour)},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.schedule.OpenIntervals(tc.window))
+		})
+	}
+}
+
+func Test_RoomScheduleCheckBookable(t *testing.T) {
+	monday := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	schedule := RoomSchedule{
+		Location: time.UTC,
+		Hours: []OpeningHours{
+			{Weekday: time.Monday, Open: 9 * time.Hour, Close: 13 * time.Hour},
+			{Weekday: time.Monday, Open: 14 * time.Hour, Close: 18 * time.Hour},
+		},
+	}
+
+	blackout := Blackout{
+		ID:        1,
+		TimeRange: TimeRange{Start: monday.Add(16 * time.Hour), End: monday.Add(17 * time.Hour)},
+	}
+
+	testCases := []struct {
+		name        string
+		tr          TimeRange
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK inside hours",
+			tr:   TimeRange{Start: monday.Add(9 * time.Hour), End: monday.Add(13 * time.Hour)},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "OK ends at blackout start",
+			tr:   TimeRange{Start: monday.Add(15 * time.Hour), End: monday.Add(16 * time.Hour)}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK before opening",
+			tr:   TimeRange{Start: monday.Add(8 * time.Hour), End: monday.Add(10 * time.Hour)}, // note
+			checkResult: func(t *testing.T, err error) {
+				var outside OutsideBookableHoursError
+				assert.ErrorAs(t, err, &outside)
+				assert.Nil(t, outside.Blackout)
+			},
+		},
+		{
+			name: "NOT OK across lunch break",
+			tr:   TimeRange{Start: monday.Add(12 * time.Hour), End: monday.Add(15 * time.Hour)}, // note
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, &OutsideBookableHoursError{})
+			},
+		},
+		{
+			name: "NOT OK in blackout",
+			tr:   TimeRange{Start: monday.Add(16*time.Hour + 30*time.Minute), End: monday.Add(18 * time.Hour)}, // note
+			checkResult: func(t *testing.T, err error) {
+				var outside OutsideBookableHoursError
+				assert.ErrorAs(t, err, &outside)
+				assert.Equal(t, &blackout.TimeRange, outside.Blackout)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkResult(t, schedule.CheckBookable(tc.tr, []Blackout{blackout}))
+		})
+	}
+}
+
+func Test_Blackout(t *testing.T) {
+	now := time.Now().Truncate(time.Second).UTC()
+
+	blackout, err := NewBlackout("conf-a", now, now.Add(time.Hour), "maintenance")
+	assert.NoError(t, err)
+	assert.Equal(t, Blackout{
+		RoomID:    "conf-a",
+		TimeRange: TimeRange{Start: now, End: now.Add(time.Hour)},
+		Reason:    "maintenance",
+	}, blackout)
+
+	_, err = NewBlackout("", now, now.Add(time.Hour), "")
+	assert.ErrorIs(t, err, internal.ErrValidationFailed)
+
+	_, err = NewBlackout("conf-a", now.Add(time.Hour), now, "")
+	assert.ErrorIs(t, err, internal.ErrValidationFailed)
+
+	_, err = NewBlackout("conf-a", now, now.Add(time.Hour), strings.Repeat("a", maxBlackoutReasonLen+1))
+	assert.ErrorIs(t, err, internal.ErrValidationFailed)
+}
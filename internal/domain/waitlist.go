@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// WaitlistStatus waiting - запрос ждет, пока освободится слот,
+// promoted - по нему уже создано бронирование ReservationID
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting  WaitlistStatus = "waiting"
+	WaitlistPromoted WaitlistStatus = "promoted"
+)
+
+// WaitlistEntry запрос на бронирование, который конфликтовал с существующими
+// и был поставлен в очередь вместо отказа
+type WaitlistEntry struct {
+	ID        int64
+	RoomID    RoomID
+	TimeRange TimeRange
+	Status    WaitlistStatus
+	Details   ReservationDetails
+	// ReservationID задан только у promoted
+	ReservationID int64
+	CreatedAt     time.Time
+}
+
+// Event событие, о котором сервис сообщает наружу после commit
+type Event interface {
+	EventName() string
+}
+
+// WaitlistPromotedEvent запрос из листа ожидания стал бронированием,
+// потому что отменили бронирование CancelledID
+type WaitlistPromotedEvent struct {
+	Entry       WaitlistEntry
+	Reservation Reservation
+	CancelledID int64
+}
+
+func (WaitlistPromotedEvent) EventName() string {
+	return "waitlist.promoted"
+}
@@ -4,4 +4,7 @@ import "errors"
 
 var (
 	ErrValidationFailed = errors.New("validation failed")
+
+	// ErrLockTimeout контекст запроса истек, пока ждали блокировку комнаты
+	ErrLockTimeout = errors.New("room lock wait timeout")
 )
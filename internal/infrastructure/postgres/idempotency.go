@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type idempotencyKeys struct {
+	conn DBTX
+}
+
+func NewIdempotencyKeys(conn DBTX) *idempotencyKeys {
+	return &idempotencyKeys{
+		conn: conn,
+	}
+}
+
+func (r idempotencyKeys) Claim(ctx context.Context, key domain.IdempotencyKey, requestHash string, abandonAfter time.Duration) (domain.IdempotencyRecord, bool, error) {
+	tx := solveTx(r.conn, ctx)
+
+	// on conflict дождется параллельной вставки того же ключа, поэтому ключ достается ровно одному запросу.
+	// Ключ без ответа, который держат дольше abandonAfter, остался от упавшего процесса:
+	// его перезанимает повтор того же запроса, а не ждет очистки по ttl
+	query := `insert into idempotency_keys(key, request_hash)
+	values($1, $2)
+	on conflict (key) do update set created_at = excluded.created_at
+	where idempotency_keys.status_code is null
+	and idempotency_keys.request_hash = excluded.request_hash
+	and idempotency_keys.created_at < (now() at time zone 'utc') - make_interval(secs => $3)
+	returning created_at`
+
+	record := domain.IdempotencyRecord{Key: key, RequestHash: requestHash}
+
+	abandonSeconds := abandonAfter.Seconds()
+	err := tx.QueryRow(ctx, query, &key, &requestHash, &abandonSeconds).Scan(&record.CreatedAt)
+	if err == nil {
+		return record, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	query = `select key, request_hash, status_code, location, body, created_at from idempotency_keys
+	where key = $1`
+
+	record, err = scanIdempotencyRecord(tx.QueryRow(ctx, query, &key))
+	if err != nil {
+		// ключ успели освободить между запросами, клиент может повторить
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.IdempotencyRecord{}, false, domain.ErrIdempotencyKeyInProgress
+		}
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	return record, false, nil
+}
+
+func (r idempotencyKeys) SaveResponse(ctx context.Context, key domain.IdempotencyKey, response domain.IdempotentResponse) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `update idempotency_keys set status_code = $2, location = $3, body = $4
+	where key = $1`
+
+	_, err := tx.Exec(ctx, query, &key, &response.StatusCode, &response.Location, response.Body)
+	return err
+}
+
+func (r idempotencyKeys) Delete(ctx context.Context, key domain.IdempotencyKey) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `delete from idempotency_keys where key = $1`
+
+	_, err := tx.Exec(ctx, query, &key)
+	return err
+}
+
+func (r idempotencyKeys) DeleteExpired(ctx context.Context, createdBefore time.Time) (int64, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `delete from idempotency_keys where created_at < $1`
+
+	tag, err := tx.Exec(ctx, query, &createdBefore)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanIdempotencyRecord(row pgx.Row) (domain.IdempotencyRecord, error) {
+	var (
+		record     domain.IdempotencyRecord
+		statusCode *int
+		location   *string
+		body       []byte
+	)
+	if err := row.Scan(
+		&record.Key,
+		&record.RequestHash,
+		&statusCode,
+		&location,
+		&body,
+		&record.CreatedAt,
+	); err != nil {
+		return domain.IdempotencyRecord{}, err
+	}
+
+	if statusCode != nil {
+		record.Response = &domain.IdempotentResponse{
+			StatusCode: *statusCode,
+			Body:       body,
+		}
+		if location != nil {
+			record.Response.Location = *location
+		}
+	}
+	return record, nil
+}
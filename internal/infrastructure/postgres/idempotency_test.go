@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+var idempotencyColumns = []string{"key", "request_hash", "status_code", "location", "body", "created_at"}
+
+func Test_ClaimIdempotencyKey(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewIdempotencyKeys(mock)
+
+	unexpectedError := errors.New("unexpected error")
+
+	key := domain.IdempotencyKey("key")
+	hash := "hash"
+	createdAt := time.Now().Truncate(time.Second).UTC()
+	abandonAfter := 30 * time.Second
+	abandonSeconds := abandonAfter.Seconds()
+
+	claimQuery := `insert into idempotency_keys(.+)on conflict \(key\) do update set created_at = excluded.created_at\s+` +
+		`where idempotency_keys.status_code is null\s+and idempotency_keys.request_hash = excluded.request_hash\s+` +
+		`and idempotency_keys.created_at < (.+) - make_interval\(secs => \$3\)`
+
+	response := domain.IdempotentResponse{
+		StatusCode: 201,
+		Location:   "/api/v1/reservations/1",
+		Body:       []byte(`{"id":1}`),
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, record domain.IdempotencyRecord, claimed bool, err error)
+	}{
+		{
+			name: "OK claimed",
+			buildStubs: func() {
+				mock.ExpectQuery(claimQuery).
+					WithArgs(&key, &hash, &abandonSeconds).
+					WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+			},
+			checkResult: func(t *testing.T, record domain.IdempotencyRecord, claimed bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, claimed)
+				assert.Equal(t, domain.IdempotencyRecord{Key: key, RequestHash: hash, CreatedAt: createdAt}, record)
+			},
+		},
+		{
+			name: "OK existing with response",
+			buildStubs: func() {
+				mock.ExpectQuery(claimQuery).
+					WithArgs(&key, &hash, &abandonSeconds).
+					WillReturnError(pgx.ErrNoRows) // note
+				mock.ExpectQuery("select (.+) from idempotency_keys").
+					WithArgs(&key).
+					WillReturnRows(pgxmock.NewRows(idempotencyColumns).
+						AddRow(key, "other", &response.StatusCode, &response.Location, response.Body, createdAt))
+			},
+			checkResult: func(t *testing.T, record domain.IdempotencyRecord, claimed bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, claimed)
+				assert.Equal(t, domain.IdempotencyRecord{
+					Key:         key,
+					RequestHash: "other",
+					Response:    &response,
+					CreatedAt:   createdAt,
+				}, record)
+			},
+		},
+		{
+			name: "OK existing in progress",
+			buildStubs: func() {
+				mock.ExpectQuery(claimQuery).
+					WithArgs(&key, &hash, &abandonSeconds).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectQuery("select (.+) from idempotency_keys").
+					WithArgs(&key).
+					WillReturnRows(pgxmock.NewRows(idempotencyColumns).
+						AddRow(key, hash, nil, nil, nil, createdAt)) // note
+			},
+			checkResult: func(t *testing.T, record domain.IdempotencyRecord, claimed bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, claimed)
+				assert.Nil(t, record.Response)
+			},
+		},
+		{
+			name: "NOT OK released between queries",
+			buildStubs: func() {
+				mock.ExpectQuery(claimQuery).
+					WithArgs(&key, &hash, &abandonSeconds).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectQuery("select (.+) from idempotency_keys").
+					WithArgs(&key).
+					WillReturnError(pgx.ErrNoRows) // note
+			},
+			checkResult: func(t *testing.T, record domain.IdempotencyRecord, claimed bool, err error) {
+				assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInProgress)
+				assert.False(t, claimed)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(claimQuery).
+					WithArgs(&key, &hash, &abandonSeconds).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, record domain.IdempotencyRecord, claimed bool, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.False(t, claimed)
+				assert.Empty(t, record)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			record, claimed, err := repo.Claim(context.Background(), key, hash, abandonAfter)
+			tc.checkResult(t, record, claimed, err)
+		})
+	}
+}
+
+func Test_SaveIdempotentResponse(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewIdempotencyKeys(mock)
+
+	key := domain.IdempotencyKey("key")
+	response := domain.IdempotentResponse{
+		StatusCode: 201,
+		Location:   "/api/v1/reservations/1",
+		Body:       []byte(`{"id":1}`),
+	}
+
+	mock.ExpectExec("update idempotency_keys").
+		WithArgs(&key, &response.StatusCode, &response.Location, response.Body).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	assert.NoError(t, repo.SaveResponse(context.Background(), key, response))
+}
+
+func Test_DeleteExpiredIdempotencyKeys(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewIdempotencyKeys(mock)
+
+	unexpectedError := errors.New("unexpected error")
+	before := time.Now().Truncate(time.Second).UTC()
+
+	t.Run("OK", func(t *testing.T) {
+		mock.ExpectExec("delete from idempotency_keys where created_at").
+			WithArgs(&before).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+		deleted, err := repo.DeleteExpired(context.Background(), before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+	})
+
+	t.Run("NOT OK error unexpected", func(t *testing.T) {
+		mock.ExpectExec("delete from idempotency_keys where created_at").
+			WithArgs(&before).
+			WillReturnError(unexpectedError)
+
+		deleted, err := repo.DeleteExpired(context.Background(), before)
+		assert.ErrorIs(t, err, unexpectedError)
+		assert.Zero(t, deleted)
+	})
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+var errLockOutsideTx = errors.New("advisory lock requires transaction in context")
+
+// advisoryLocker блокировка комнаты общая для всех реплик приложения,
+// снимается самим postgres при commit/rollback транзакции
+type advisoryLocker struct{}
+
+func NewAdvisoryLocker() *advisoryLocker {
+	return &advisoryLocker{}
+}
+
+func (l advisoryLocker) Lock(ctx context.Context, roomID domain.RoomID) (func(), error) {
+	// вне транзакции xact lock отпустился бы сразу после запроса
+	tx := extractTx(ctx)
+	if tx == nil {
+		return nil, errLockOutsideTx
+	}
+
+	query := `select pg_advisory_xact_lock(hashtext($1))`
+
+	if _, err := tx.Exec(ctx, query, &roomID); err != nil {
+		// pgx отменяет запрос по контексту, пока он ждет блокировку
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("%w: %w", internal.ErrLockTimeout, ctxErr)
+		}
+		return nil, err
+	}
+
+	return func() {}, nil
+}
+
+// NeedsTx xact lock берется и отпускается вместе с транзакцией
+func (l advisoryLocker) NeedsTx() bool {
+	return true
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+func Test_AdvisoryLocker(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	locker := NewAdvisoryLocker()
+	assert.True(t, locker.NeedsTx())
+
+	targetQuery := "select pg_advisory_xact_lock\\(hashtext\\(\\$1\\)\\)"
+
+	unexpectedError := errors.New("unexpected error")
+
+	roomID := domain.RoomID("conf-a")
+
+	testCases := []struct {
+		name        string
+		inTx        bool
+		timeout     time.Duration
+		buildStubs  func()
+		checkResult func(t *testing.T, unlock func(), err error)
+	}{
+		{
+			name: "OK",
+			inTx: true,
+			buildStubs: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(targetQuery).
+					WithArgs(&roomID).
+					WillReturnResult(pgxmock.NewResult("SELECT", 1))
+			},
+			checkResult: func(t *testing.T, unlock func(), err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, unlock)
+				unlock()
+			},
+		},
+		{
+			name:       "NOT OK without transaction",
+			inTx:       false, // note
+			buildStubs: func() {},
+			checkResult: func(t *testing.T, unlock func(), err error) {
+				assert.ErrorIs(t, err, errLockOutsideTx)
+				assert.Nil(t, unlock)
+			},
+		},
+		{
+			name:    "NOT OK context expired while waiting",
+			inTx:    true,
+			timeout: 10 * time.Millisecond, // note
+			buildStubs: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(targetQuery).
+					WithArgs(&roomID).
+					WillReturnResult(pgxmock.NewResult("SELECT", 1)).
+					WillDelayFor(time.Second)
+			},
+			checkResult: func(t *testing.T, unlock func(), err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.Nil(t, unlock)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			inTx: true,
+			buildStubs: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(targetQuery).
+					WithArgs(&roomID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, unlock func(), err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, unlock)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			if tc.inTx {
+				tx, err := mock.Begin(ctx)
+				assert.NoError(t, err)
+				ctx = injectTx(ctx, tx)
+			}
+
+			unlock, err := locker.Lock(ctx, roomID)
+			tc.checkResult(t, unlock, err)
+		})
+	}
+}
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ynuraddi/test-kami/internal"
 	"github.com/ynuraddi/test-kami/internal/domain"
 )
 
@@ -24,39 +28,187 @@ func NewReservations(conn DBTX) *reservations {
 	}
 }
 
-func (r reservations) Create(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) (err error) {
+func (r reservations) Create(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange, details domain.ReservationDetails) (domain.Reservation, error) {
 	tx := solveTx(r.conn, ctx)
 
-	query := `insert into reservations(room_id, start_time, end_time)
-	values($1, $2, $3)`
+	query := `insert into reservations(room_id, start_time, end_time, owner, title, description, attendees)
+	values($1, $2, $3, $4, $5, $6, coalesce($7::text[], '{}'))
+	returning id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees`
 
-	if _, err := tx.Exec(ctx, query, &roomID, &timeRange.Start, &timeRange.End); err != nil {
-		return err
+	reservation, err := scanReservation(tx.QueryRow(ctx, query, &roomID, &timeRange.Start, &timeRange.End,
+		&details.Owner, &details.Title, &details.Description, details.Attendees))
+	if err != nil {
+		return domain.Reservation{}, err
 	}
-	return nil
+
+	return reservation, nil
 }
 
-func (r reservations) ListByRoom(ctx context.Context, roomID domain.RoomID) ([]domain.Reservation, error) {
+// CreateHold срок считается по часам базы, как и в FindOverlapping,
+// чтобы бронь истекала ровно тогда, когда перестает блокировать слот
+func (r reservations) CreateHold(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange, details domain.ReservationDetails, ttl time.Duration) (domain.Reservation, error) {
 	tx := solveTx(r.conn, ctx)
 
-	query := `select id, room_id, start_time, end_time from reservations
-	where room_id = $1`
+	query := `insert into reservations(room_id, start_time, end_time, owner, title, description, attendees, status, expires_at)
+	values($1, $2, $3, $4, $5, $6, coalesce($7::text[], '{}'), 'held', statement_timestamp() at time zone 'utc' + make_interval(secs => $8))
+	returning id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees`
 
-	rows, err := tx.Query(ctx, query, &roomID)
+	ttlSeconds := ttl.Seconds()
+	reservation, err := scanReservation(tx.QueryRow(ctx, query, &roomID, &timeRange.Start, &timeRange.End,
+		&details.Owner, &details.Title, &details.Description, details.Attendees, &ttlSeconds))
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	return reservation, nil
+}
+
+// CreateSeries сохраняет серию и все ее вхождения,
+// вызывается внутри транзакции, чтобы серия создавалась целиком. Details у всех вхождений общие
+func (r reservations) CreateSeries(ctx context.Context, roomID domain.RoomID, rule string, timeRanges []domain.TimeRange, details domain.ReservationDetails) (domain.ReservationSeries, error) {
+	tx := solveTx(r.conn, ctx)
+
+	series := domain.ReservationSeries{
+		RoomID: roomID,
+		Rule:   rule,
+	}
+
+	query := `insert into reservation_series(room_id, rrule)
+	values($1, $2)
+	returning id`
+
+	if err := tx.QueryRow(ctx, query, &roomID, &rule).Scan(&series.ID); err != nil {
+		return domain.ReservationSeries{}, err
+	}
+
+	starts := make([]time.Time, 0, len(timeRanges))
+	ends := make([]time.Time, 0, len(timeRanges))
+	for _, tr := range timeRanges {
+		starts = append(starts, tr.Start)
+		ends = append(ends, tr.End)
+	}
+
+	query = `insert into reservations(room_id, start_time, end_time, series_id, owner, title, description, attendees)
+	select $1, t.start_time, t.end_time, $4, $5, $6, $7, coalesce($8::text[], '{}')
+	from unnest($2::timestamp[], $3::timestamp[]) as t(start_time, end_time)
+	returning id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees`
+
+	rows, err := tx.Query(ctx, query, &roomID, starts, ends, &series.ID,
+		&details.Owner, &details.Title, &details.Description, details.Attendees)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+
+	series.Reservations, err = scanReservations(rows)
+	if err != nil {
+		return domain.ReservationSeries{}, err
+	}
+
+	return series, nil
+}
+
+func (r reservations) GetByID(ctx context.Context, id int64) (domain.Reservation, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations
+	where id = $1`
+
+	reservation, err := scanReservation(tx.QueryRow(ctx, query, &id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Reservation{}, domain.ErrReservationNotFound
+		}
+		return domain.Reservation{}, err
+	}
+
+	return reservation, nil
+}
+
+func (r reservations) ListByRoom(ctx context.Context, q domain.ReservationQuery) ([]domain.Reservation, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations
+	where room_id = $1 and (expires_at is null or expires_at > statement_timestamp() at time zone 'utc')`
+	args := []any{&q.RoomID}
+
+	// окно отдает бронирования, пересекающиеся с [from, to)
+	if !q.From.IsZero() {
+		args = append(args, &q.From)
+		query += fmt.Sprintf(" and end_time > $%d", len(args))
+	}
+	if !q.To.IsZero() {
+		args = append(args, &q.To)
+		query += fmt.Sprintf(" and start_time < $%d", len(args))
+	}
+	if q.After != nil {
+		args = append(args, &q.After.StartTime, &q.After.ID)
+		query += fmt.Sprintf(" and (start_time, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+	query += " order by start_time, id"
+	if q.Limit > 0 {
+		args = append(args, &q.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+
+	return scanReservations(rows)
+}
+
+// FindOverlapping использует индекс idx_reservations_room_start_end.
+// Истекшие held брони уже не занимают слот, даже если их еще не удалили.
+// Время - statement_timestamp, а не now(): now() это начало транзакции,
+// то есть момент до ожидания блокировки комнаты
+func (r reservations) FindOverlapping(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) ([]domain.Reservation, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations
+	where room_id = $1 and start_time < $3 and end_time > $2
+	and (expires_at is null or expires_at > statement_timestamp() at time zone 'utc')
+	order by start_time`
+
+	rows, err := tx.Query(ctx, query, &roomID, &timeRange.Start, &timeRange.End)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReservations(rows)
+}
+
+func scanReservation(row pgx.Row) (domain.Reservation, error) {
+	var reservation domain.Reservation
+	if err := row.Scan(
+		&reservation.ID,
+		&reservation.RoomID,
+		&reservation.TimeRange.Start,
+		&reservation.TimeRange.End,
+		&reservation.Version,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&reservation.Details.Owner,
+		&reservation.Details.Title,
+		&reservation.Details.Description,
+		&reservation.Details.Attendees,
+	); err != nil {
+		return domain.Reservation{}, err
+	}
+	// пустой массив из базы и отсутствие участников - одно и то же
+	if len(reservation.Details.Attendees) == 0 {
+		reservation.Details.Attendees = nil
+	}
+	return reservation, nil
+}
+
+func scanReservations(rows pgx.Rows) ([]domain.Reservation, error) {
 	defer rows.Close()
 
 	var reservations []domain.Reservation
 	for rows.Next() {
-		var reservation domain.Reservation
-		if err := rows.Scan(
-			&reservation.ID,
-			&reservation.RoomID,
-			&reservation.TimeRange.Start,
-			&reservation.TimeRange.End,
-		); err != nil {
+		reservation, err := scanReservation(rows)
+		if err != nil {
 			return nil, err
 		}
 		reservations = append(reservations, reservation)
@@ -67,3 +219,177 @@ func (r reservations) ListByRoom(ctx context.Context, roomID domain.RoomID) ([]d
 
 	return reservations, nil
 }
+
+func (r reservations) Update(ctx context.Context, id int64, timeRange domain.TimeRange, version int64) (int64, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `update reservations set start_time = $2, end_time = $3, version = version + 1
+	where id = $1 and version = $4
+	returning version`
+
+	var newVersion int64
+	if err := tx.QueryRow(ctx, query, &id, &timeRange.Start, &timeRange.End, &version).Scan(&newVersion); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, r.notUpdatedReason(ctx, id)
+		}
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+func (r reservations) Delete(ctx context.Context, id int64, version int64) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `delete from reservations where id = $1 and version = $2`
+
+	tag, err := tx.Exec(ctx, query, &id, &version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.notUpdatedReason(ctx, id)
+	}
+	return nil
+}
+
+func (r reservations) Confirm(ctx context.Context, id int64, version int64) (int64, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `update reservations set status = 'confirmed', expires_at = null, version = version + 1
+	where id = $1 and version = $2 and status = 'held' and expires_at > statement_timestamp() at time zone 'utc'
+	returning version`
+
+	var newVersion int64
+	if err := tx.QueryRow(ctx, query, &id, &version).Scan(&newVersion); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, r.notConfirmedReason(ctx, id, version)
+		}
+		return 0, err
+	}
+	return newVersion, nil
+}
+
+// notConfirmedReason почему Confirm не затронул строк, проверки в порядке важности для клиента
+func (r reservations) notConfirmedReason(ctx context.Context, id int64, version int64) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select version, status, coalesce(expires_at <= statement_timestamp() at time zone 'utc', false) from reservations
+	where id = $1`
+
+	var (
+		current int64
+		status  domain.ReservationStatus
+		expired bool
+	)
+	if err := tx.QueryRow(ctx, query, &id).Scan(&current, &status, &expired); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrReservationNotFound
+		}
+		return err
+	}
+	if current != version {
+		return domain.ErrReservationVersionMismatch
+	}
+	if status != domain.StatusHeld {
+		return domain.ErrReservationNotHeld
+	}
+	if expired {
+		return domain.ErrHoldExpired
+	}
+	return domain.ErrReservationVersionMismatch
+}
+
+// ListExpiredHoldRooms использует индекс idx_reservations_hold_expires
+func (r reservations) ListExpiredHoldRooms(ctx context.Context) ([]domain.RoomID, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select distinct room_id from reservations
+	where status = 'held' and expires_at <= statement_timestamp() at time zone 'utc'
+	order by room_id`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roomIDs []domain.RoomID
+	for rows.Next() {
+		var roomID domain.RoomID
+		if err := rows.Scan(&roomID); err != nil {
+			return nil, err
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roomIDs, nil
+}
+
+func (r reservations) DeleteExpiredHolds(ctx context.Context, roomID domain.RoomID) ([]domain.Reservation, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `delete from reservations
+	where room_id = $1 and status = 'held' and expires_at <= statement_timestamp() at time zone 'utc'
+	returning id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees`
+
+	rows, err := tx.Query(ctx, query, &roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReservations(rows)
+}
+
+// notUpdatedReason условный update/delete не затронул строк:
+// бронирования нет или его версия уже другая
+func (r reservations) notUpdatedReason(ctx context.Context, id int64) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select exists(select 1 from reservations where id = $1)`
+
+	var exists bool
+	if err := tx.QueryRow(ctx, query, &id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrReservationNotFound
+	}
+	return domain.ErrReservationVersionMismatch
+}
+
+// CountActiveByOwner незакончившиеся бронирования, истекшие held брони не считаются
+func (r reservations) CountActiveByOwner(ctx context.Context, owner string, excludeID int64) (int, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select count(*) from reservations
+	where owner = $1 and id <> $2 and end_time > statement_timestamp() at time zone 'utc'
+	and (expires_at is null or expires_at > statement_timestamp() at time zone 'utc')`
+
+	var count int
+	if err := tx.QueryRow(ctx, query, &owner, &excludeID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r reservations) LockOwner(ctx context.Context, owner string) error {
+	// вне транзакции xact lock отпустился бы сразу после запроса
+	tx := extractTx(ctx)
+	if tx == nil {
+		return errLockOutsideTx
+	}
+
+	// префикс отделяет ключи владельцев от ключей комнат того же advisory lock
+	query := `select pg_advisory_xact_lock(hashtext('owner:' || $1))`
+
+	if _, err := tx.Exec(ctx, query, &owner); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%w: %w", internal.ErrLockTimeout, ctxErr)
+		}
+		return err
+	}
+	return nil
+}
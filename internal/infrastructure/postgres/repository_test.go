@@ -8,6 +8,7 @@ import (
 
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
 	"github.com/ynuraddi/test-kami/internal/domain"
 )
 
@@ -29,8 +30,9 @@ func Test_CreateReservation(t *testing.T) {
 	unexpectedError := errors.New("unexpected error")
 
 	type args struct {
-		rid domain.RoomID
-		tr  domain.TimeRange
+		rid     domain.RoomID
+		tr      domain.TimeRange
+		details domain.ReservationDetails
 	}
 
 	defaultArgs := args{
@@ -39,37 +41,104 @@ func Test_CreateReservation(t *testing.T) {
 			Start: from,
 			End:   to,
 		},
+		details: domain.ReservationDetails{
+			Owner:     "alice",
+			Title:     "standup",
+			Attendees: []string{"bob", "carol"},
+		},
+	}
+
+	nonNumericArgs := args{
+		rid: "conf-a",
+		tr:  defaultArgs.tr,
+	}
+
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
+	defaultReservation := domain.Reservation{
+		ID:        1,
+		RoomID:    defaultArgs.rid,
+		TimeRange: defaultArgs.tr,
+		Version:   1,
+		Status:    domain.StatusConfirmed,
+		Details:   defaultArgs.details,
 	}
 
 	testCases := []struct {
 		name        string
 		args        args
 		buildStubs  func()
-		checkResult func(t *testing.T, err error)
+		checkResult func(t *testing.T, r domain.Reservation, err error)
 	}{
 		{
 			name: "OK",
 			args: defaultArgs,
 			buildStubs: func() {
-				mock.ExpectExec(targetQuery).
-					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End).
-					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End, &defaultArgs.details.Owner, &defaultArgs.details.Title, &defaultArgs.details.Description, defaultArgs.details.Attendees).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(
+							defaultReservation.ID,
+							defaultReservation.RoomID,
+							defaultReservation.TimeRange.Start,
+							defaultReservation.TimeRange.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							defaultReservation.Details.Owner,
+							defaultReservation.Details.Title,
+							defaultReservation.Details.Description,
+							defaultReservation.Details.Attendees,
+						))
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultReservation, r)
+			},
+		},
+		{
+			name: "OK non-numeric room id",
+			args: nonNumericArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&nonNumericArgs.rid, &nonNumericArgs.tr.Start, &nonNumericArgs.tr.End, &nonNumericArgs.details.Owner, &nonNumericArgs.details.Title, &nonNumericArgs.details.Description, nonNumericArgs.details.Attendees).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(
+							int64(2),
+							nonNumericArgs.rid,
+							nonNumericArgs.tr.Start,
+							nonNumericArgs.tr.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							"",
+							"",
+							"",
+							[]string{},
+						))
+			},
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
 				assert.NoError(t, err)
+				assert.Equal(t, domain.Reservation{
+					ID:        2,
+					RoomID:    nonNumericArgs.rid,
+					TimeRange: nonNumericArgs.tr,
+					Version:   1,
+					Status:    domain.StatusConfirmed,
+				}, r)
 			},
 		},
 		{
 			name: "NOT OK error unexpected",
 			args: defaultArgs,
 			buildStubs: func() {
-				mock.ExpectExec(targetQuery).
-					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End).
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End, &defaultArgs.details.Owner, &defaultArgs.details.Title, &defaultArgs.details.Description, defaultArgs.details.Attendees).
 					WillReturnError(unexpectedError) // note
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, r)
 			},
 		},
 	}
@@ -77,8 +146,109 @@ func Test_CreateReservation(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.buildStubs()
-			err := repo.Create(context.Background(), tc.args.rid, tc.args.tr)
-			tc.checkResult(t, err)
+			reservation, err := repo.Create(context.Background(), tc.args.rid, tc.args.tr, tc.args.details)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_CreateSeries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	from := time.Now().Truncate(time.Second).UTC()
+
+	rid := domain.RoomID("conf-a")
+	rule := "FREQ=DAILY;COUNT=2"
+	ranges := []domain.TimeRange{
+		{Start: from, End: from.Add(1 * time.Hour)},
+		{Start: from.AddDate(0, 0, 1), End: from.AddDate(0, 0, 1).Add(1 * time.Hour)},
+	}
+	details := domain.ReservationDetails{
+		Owner:     "alice",
+		Title:     "standup",
+		Attendees: []string{"bob", "carol"},
+	}
+	starts := []time.Time{ranges[0].Start, ranges[1].Start}
+	ends := []time.Time{ranges[0].End, ranges[1].End}
+
+	seriesQuery := "insert into reservation_series"
+	reservationsQuery := "insert into reservations\\(room_id, start_time, end_time, series_id, owner, title, description, attendees\\)"
+
+	unexpectedError := errors.New("unexpected error")
+
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, s domain.ReservationSeries, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(seriesQuery).
+					WithArgs(&rid, &rule).
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(7)))
+				mock.ExpectQuery(reservationsQuery).
+					WithArgs(&rid, starts, ends, pgxmock.AnyArg(), &details.Owner, &details.Title, &details.Description, details.Attendees).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(int64(1), rid, ranges[0].Start, ranges[0].End, int64(1), domain.StatusConfirmed, nil, details.Owner, details.Title, details.Description, details.Attendees).
+						AddRow(int64(2), rid, ranges[1].Start, ranges[1].End, int64(1), domain.StatusConfirmed, nil, details.Owner, details.Title, details.Description, details.Attendees))
+			},
+			checkResult: func(t *testing.T, s domain.ReservationSeries, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.ReservationSeries{
+					ID:     7,
+					RoomID: rid,
+					Rule:   rule,
+					Reservations: []domain.Reservation{
+						{ID: 1, RoomID: rid, TimeRange: ranges[0], Version: 1, Status: domain.StatusConfirmed, Details: details},
+						{ID: 2, RoomID: rid, TimeRange: ranges[1], Version: 1, Status: domain.StatusConfirmed, Details: details},
+					},
+				}, s)
+			},
+		},
+		{
+			name: "NOT OK error from series insert",
+			buildStubs: func() {
+				mock.ExpectQuery(seriesQuery).
+					WithArgs(&rid, &rule).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, s domain.ReservationSeries, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, s)
+			},
+		},
+		{
+			name: "NOT OK error from reservations insert",
+			buildStubs: func() {
+				mock.ExpectQuery(seriesQuery).
+					WithArgs(&rid, &rule).
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(7)))
+				mock.ExpectQuery(reservationsQuery).
+					WithArgs(&rid, starts, ends, pgxmock.AnyArg(), &details.Owner, &details.Title, &details.Description, details.Attendees).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, s domain.ReservationSeries, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, s)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			series, err := repo.CreateSeries(context.Background(), rid, rule, ranges, details)
+			tc.checkResult(t, series, err)
 		})
 	}
 }
@@ -96,11 +266,11 @@ func Test_ListByRoom(t *testing.T) {
 	from := time.Now().Truncate(time.Second).UTC()
 	to := from.Add(1 * time.Minute)
 
-	targetQuery := "select id, room_id, start_time, end_time from reservations"
+	targetQuery := "select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations"
 
 	defaultRoomID := domain.RoomID("1")
 
-	reservationsColumns := []string{"id", "room_id", "start_time", "end_time"}
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
 	defaultReservation := domain.Reservation{
 		ID:     1,
 		RoomID: defaultRoomID,
@@ -108,16 +278,30 @@ func Test_ListByRoom(t *testing.T) {
 			Start: from,
 			End:   to,
 		},
+		Version: 1,
+		Status:  domain.StatusConfirmed,
 	}
 
 	unexpectedError := errors.New("unexpected error")
 
 	type args struct {
-		rid domain.RoomID
+		query domain.ReservationQuery
 	}
 
 	defaultArgs := args{
-		rid: defaultRoomID,
+		query: domain.ReservationQuery{RoomID: defaultRoomID},
+	}
+
+	filterArgs := args{
+		query: domain.ReservationQuery{
+			RoomID: defaultRoomID,
+			ReservationFilter: domain.ReservationFilter{
+				From:  from,
+				To:    to,
+				After: &domain.ReservationCursor{StartTime: from, ID: 1},
+				Limit: 10,
+			},
+		},
 	}
 
 	testCases := []struct {
@@ -132,13 +316,20 @@ func Test_ListByRoom(t *testing.T) {
 			buildStubs: func() {
 				mock.ExpectQuery(targetQuery).
 					RowsWillBeClosed().
-					WithArgs(&defaultArgs.rid).
+					WithArgs(&defaultArgs.query.RoomID).
 					WillReturnRows(pgxmock.NewRows(reservationsColumns).
 						AddRow(
 							defaultReservation.ID,
 							defaultReservation.RoomID,
 							defaultReservation.TimeRange.Start,
 							defaultReservation.TimeRange.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							"",
+							"",
+							"",
+							[]string{},
 						))
 			},
 			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
@@ -147,13 +338,48 @@ func Test_ListByRoom(t *testing.T) {
 				assert.Equal(t, defaultReservation, rs[0])
 			},
 		},
+		{
+			name: "OK with filter",
+			args: filterArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery+`\s+where room_id = \$1 and \(expires_at is null or expires_at > statement_timestamp\(\) at time zone 'utc'\) and end_time > \$2 and start_time < \$3 `+
+					`and \(start_time, id\) > \(\$4, \$5\) order by start_time, id limit \$6`).
+					RowsWillBeClosed().
+					WithArgs(
+						&filterArgs.query.RoomID,
+						&filterArgs.query.From,
+						&filterArgs.query.To,
+						&filterArgs.query.After.StartTime,
+						&filterArgs.query.After.ID,
+						&filterArgs.query.Limit,
+					).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(
+							defaultReservation.ID,
+							defaultReservation.RoomID,
+							defaultReservation.TimeRange.Start,
+							defaultReservation.TimeRange.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							"",
+							"",
+							"",
+							[]string{},
+						))
+			},
+			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Reservation{defaultReservation}, rs)
+			},
+		},
 		{
 			name: "NOT OK error unexpected",
 			args: defaultArgs,
 			buildStubs: func() {
 				mock.ExpectQuery(targetQuery).
 					RowsWillBeClosed().
-					WithArgs(&defaultArgs.rid).
+					WithArgs(&defaultArgs.query.RoomID).
 					WillReturnError(unexpectedError) // note
 			},
 			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
@@ -168,13 +394,20 @@ func Test_ListByRoom(t *testing.T) {
 			buildStubs: func() {
 				mock.ExpectQuery(targetQuery).
 					RowsWillBeClosed().
-					WithArgs(&defaultArgs.rid).
+					WithArgs(&defaultArgs.query.RoomID).
 					WillReturnRows(pgxmock.NewRows(reservationsColumns).
 						AddRow(
 							"incorrect value",
 							defaultReservation.RoomID,
 							defaultReservation.TimeRange.Start,
 							defaultReservation.TimeRange.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							"",
+							"",
+							"",
+							[]string{},
 						))
 			},
 			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
@@ -187,7 +420,7 @@ func Test_ListByRoom(t *testing.T) {
 			buildStubs: func() {
 				mock.ExpectQuery(targetQuery).
 					RowsWillBeClosed().
-					WithArgs(&defaultArgs.rid).
+					WithArgs(&defaultArgs.query.RoomID).
 					WillReturnRows(pgxmock.NewRows(reservationsColumns).
 						RowError(0, unexpectedError))
 			},
@@ -201,8 +434,895 @@ func Test_ListByRoom(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.buildStubs()
-			reservations, err := repo.ListByRoom(context.Background(), tc.args.rid)
+			reservations, err := repo.ListByRoom(context.Background(), tc.args.query)
+			tc.checkResult(t, reservations, err)
+		})
+	}
+}
+
+func Test_FindOverlapping(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	targetQuery := `select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations\s+` +
+		`where room_id = \$1 and start_time < \$3 and end_time > \$2`
+
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
+	defaultReservation := domain.Reservation{
+		ID:     1,
+		RoomID: "1",
+		TimeRange: domain.TimeRange{
+			Start: from.Add(30 * time.Minute),
+			End:   to.Add(30 * time.Minute),
+		},
+		Version: 1,
+		Status:  domain.StatusConfirmed,
+	}
+
+	unexpectedError := errors.New("unexpected error")
+
+	type args struct {
+		rid domain.RoomID
+		tr  domain.TimeRange
+	}
+
+	defaultArgs := args{
+		rid: defaultReservation.RoomID,
+		tr:  domain.TimeRange{Start: from, End: to},
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, rs []domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					RowsWillBeClosed().
+					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(
+							defaultReservation.ID,
+							defaultReservation.RoomID,
+							defaultReservation.TimeRange.Start,
+							defaultReservation.TimeRange.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							"",
+							"",
+							"",
+							[]string{},
+						))
+			},
+			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Reservation{defaultReservation}, rs)
+			},
+		},
+		{
+			name: "OK no overlaps",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					RowsWillBeClosed().
+					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns)) // note
+			},
+			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, rs)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.rid, &defaultArgs.tr.Start, &defaultArgs.tr.End).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, rs []domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, rs)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservations, err := repo.FindOverlapping(context.Background(), tc.args.rid, tc.args.tr)
 			tc.checkResult(t, reservations, err)
 		})
 	}
 }
+
+func Test_GetReservationByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Minute)
+
+	targetQuery := "select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations"
+
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
+	defaultReservation := domain.Reservation{
+		ID:     1,
+		RoomID: "1",
+		TimeRange: domain.TimeRange{
+			Start: from,
+			End:   to,
+		},
+		Version: 1,
+		Status:  domain.StatusConfirmed,
+	}
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultID := defaultReservation.ID
+
+	testCases := []struct {
+		name        string
+		id          int64
+		buildStubs  func()
+		checkResult func(t *testing.T, r domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultID).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(
+							defaultReservation.ID,
+							defaultReservation.RoomID,
+							defaultReservation.TimeRange.Start,
+							defaultReservation.TimeRange.End,
+							int64(1),
+							domain.StatusConfirmed,
+							nil,
+							"",
+							"",
+							"",
+							[]string{},
+						))
+			},
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultReservation, r)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultID).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns)) // note
+			},
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+				assert.Empty(t, r)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, r)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			reservation, err := repo.GetByID(context.Background(), tc.id)
+			tc.checkResult(t, reservation, err)
+		})
+	}
+}
+
+func Test_DeleteReservation(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	targetQuery := "delete from reservations"
+	existsQuery := `select exists\(select 1 from reservations where id = \$1\)`
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultID := int64(1)
+	defaultVersion := int64(2)
+
+	testCases := []struct {
+		name        string
+		id          int64
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultID, &defaultVersion).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultID, &defaultVersion).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0)) // note
+				mock.ExpectQuery(existsQuery).
+					WithArgs(&defaultID).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+			},
+		},
+		{
+			name: "NOT OK version mismatch",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultID, &defaultVersion).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0)) // note
+				mock.ExpectQuery(existsQuery).
+					WithArgs(&defaultID).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			id:   defaultID,
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultID, &defaultVersion).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			err := repo.Delete(context.Background(), tc.id, defaultVersion)
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func Test_UpdateReservation(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Minute)
+
+	targetQuery := `update reservations set start_time = \$2, end_time = \$3, version = version \+ 1\s+where id = \$1 and version = \$4`
+	existsQuery := `select exists\(select 1 from reservations where id = \$1\)`
+
+	unexpectedError := errors.New("unexpected error")
+
+	type args struct {
+		id      int64
+		tr      domain.TimeRange
+		version int64
+	}
+
+	defaultArgs := args{
+		id: 1,
+		tr: domain.TimeRange{
+			Start: from,
+			End:   to,
+		},
+		version: 2,
+	}
+
+	testCases := []struct {
+		name        string
+		args        args
+		buildStubs  func()
+		checkResult func(t *testing.T, version int64, err error)
+	}{
+		{
+			name: "OK",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.id, &defaultArgs.tr.Start, &defaultArgs.tr.End, &defaultArgs.version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(int64(3)))
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), version)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.id, &defaultArgs.tr.Start, &defaultArgs.tr.End, &defaultArgs.version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"})) // note
+				mock.ExpectQuery(existsQuery).
+					WithArgs(&defaultArgs.id).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+				assert.Zero(t, version)
+			},
+		},
+		{
+			name: "NOT OK version mismatch",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.id, &defaultArgs.tr.Start, &defaultArgs.tr.End, &defaultArgs.version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"})) // note
+				mock.ExpectQuery(existsQuery).
+					WithArgs(&defaultArgs.id).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+				assert.Zero(t, version)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			args: defaultArgs,
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultArgs.id, &defaultArgs.tr.Start, &defaultArgs.tr.End, &defaultArgs.version).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			version, err := repo.Update(context.Background(), tc.args.id, tc.args.tr, tc.args.version)
+			tc.checkResult(t, version, err)
+		})
+	}
+}
+
+func Test_CreateHold(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+	expiresAt := from.Add(-time.Hour)
+
+	targetQuery := `insert into reservations\(room_id, start_time, end_time, owner, title, description, attendees, status, expires_at\)`
+
+	unexpectedError := errors.New("unexpected error")
+
+	rid := domain.RoomID("conf-a")
+	tr := domain.TimeRange{Start: from, End: to}
+	details := domain.ReservationDetails{Owner: "alice", Title: "interview", Attendees: []string{"bob"}}
+	ttlSeconds := float64(600)
+
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, r domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&rid, &tr.Start, &tr.End, &details.Owner, &details.Title, &details.Description, details.Attendees, &ttlSeconds).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(int64(1), rid, from, to, int64(1), domain.StatusHeld, &expiresAt, details.Owner, details.Title, details.Description, details.Attendees))
+			},
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.Reservation{
+					ID:        1,
+					RoomID:    rid,
+					TimeRange: tr,
+					Version:   1,
+					Status:    domain.StatusHeld,
+					ExpiresAt: &expiresAt,
+					Details:   details,
+				}, r)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&rid, &tr.Start, &tr.End, &details.Owner, &details.Title, &details.Description, details.Attendees, &ttlSeconds).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, r)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			r, err := repo.CreateHold(context.Background(), rid, tr, details, 10*time.Minute)
+			tc.checkResult(t, r, err)
+		})
+	}
+}
+
+func Test_ConfirmReservation(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	targetQuery := `update reservations set status = 'confirmed', expires_at = null, version = version \+ 1\s+` +
+		`where id = \$1 and version = \$2 and status = 'held'`
+	reasonQuery := `select version, status, coalesce\(expires_at`
+
+	reasonColumns := []string{"version", "status", "expired"}
+
+	unexpectedError := errors.New("unexpected error")
+
+	id, version := int64(1), int64(2)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, version int64, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id, &version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(int64(3)))
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), version)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id, &version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(reasonQuery).
+					WithArgs(&id).
+					WillReturnRows(pgxmock.NewRows(reasonColumns)) // note
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+				assert.Zero(t, version)
+			},
+		},
+		{
+			name: "NOT OK version mismatch",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id, &version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(reasonQuery).
+					WithArgs(&id).
+					WillReturnRows(pgxmock.NewRows(reasonColumns).AddRow(int64(5), domain.StatusHeld, false)) // note
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+				assert.Zero(t, version)
+			},
+		},
+		{
+			name: "NOT OK already confirmed",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id, &version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(reasonQuery).
+					WithArgs(&id).
+					WillReturnRows(pgxmock.NewRows(reasonColumns).AddRow(version, domain.StatusConfirmed, false)) // note
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.ErrorIs(t, err, domain.ErrReservationNotHeld)
+				assert.Zero(t, version)
+			},
+		},
+		{
+			name: "NOT OK hold expired",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id, &version).
+					WillReturnRows(pgxmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(reasonQuery).
+					WithArgs(&id).
+					WillReturnRows(pgxmock.NewRows(reasonColumns).AddRow(version, domain.StatusHeld, true)) // note
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.ErrorIs(t, err, domain.ErrHoldExpired)
+				assert.Zero(t, version)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id, &version).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, version int64, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Zero(t, version)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			version, err := repo.Confirm(context.Background(), id, version)
+			tc.checkResult(t, version, err)
+		})
+	}
+}
+
+func Test_ListExpiredHoldRooms(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	targetQuery := `select distinct room_id from reservations\s+where status = 'held' and expires_at <=`
+
+	unexpectedError := errors.New("unexpected error")
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, roomIDs []domain.RoomID, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					RowsWillBeClosed().
+					WillReturnRows(pgxmock.NewRows([]string{"room_id"}).AddRow(domain.RoomID("conf-a")).AddRow(domain.RoomID("conf-b")))
+			},
+			checkResult: func(t *testing.T, roomIDs []domain.RoomID, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.RoomID{"conf-a", "conf-b"}, roomIDs)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, roomIDs []domain.RoomID, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, roomIDs)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			roomIDs, err := repo.ListExpiredHoldRooms(context.Background())
+			tc.checkResult(t, roomIDs, err)
+		})
+	}
+}
+
+func Test_DeleteExpiredHolds(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	targetQuery := `delete from reservations\s+where room_id = \$1 and status = 'held' and expires_at <=(.+)returning id, room_id`
+
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
+
+	from := time.Now().Truncate(time.Second).UTC()
+	expiresAt := from.Add(-1 * time.Minute)
+	roomID := domain.RoomID("conf-a")
+
+	expired := domain.Reservation{
+		ID:        3,
+		RoomID:    roomID,
+		TimeRange: domain.TimeRange{Start: from, End: from.Add(1 * time.Hour)},
+		Version:   1,
+		Status:    domain.StatusHeld,
+		ExpiresAt: &expiresAt,
+		Details:   domain.ReservationDetails{Owner: "alice"},
+	}
+
+	unexpectedError := errors.New("unexpected error")
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, holds []domain.Reservation, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					RowsWillBeClosed().
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows(reservationsColumns).
+						AddRow(expired.ID, expired.RoomID, expired.TimeRange.Start, expired.TimeRange.End,
+							expired.Version, expired.Status, expired.ExpiresAt, "alice", "", "", []string{}))
+			},
+			checkResult: func(t *testing.T, holds []domain.Reservation, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Reservation{expired}, holds)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, holds []domain.Reservation, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, holds)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			holds, err := repo.DeleteExpiredHolds(context.Background(), roomID)
+			tc.checkResult(t, holds, err)
+		})
+	}
+}
+
+func Test_CountActiveByOwner(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	targetQuery := `select count\(\*\) from reservations\s+where owner = \$1 and id <> \$2`
+
+	unexpectedError := errors.New("unexpected error")
+
+	owner := "alice"
+	excludeID := int64(3)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, count int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&owner, &excludeID).
+					WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+			},
+			checkResult: func(t *testing.T, count int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, count)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&owner, &excludeID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, count int, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Zero(t, count)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			count, err := repo.CountActiveByOwner(context.Background(), owner, excludeID)
+			tc.checkResult(t, count, err)
+		})
+	}
+}
+
+func Test_LockOwner(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewReservations(mock)
+
+	targetQuery := `select pg_advisory_xact_lock\(hashtext\('owner:' \|\| \$1\)\)`
+
+	unexpectedError := errors.New("unexpected error")
+
+	owner := "alice"
+
+	testCases := []struct {
+		name        string
+		inTx        bool
+		timeout     time.Duration
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			inTx: true,
+			buildStubs: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(targetQuery).
+					WithArgs(&owner).
+					WillReturnResult(pgxmock.NewResult("SELECT", 1))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:       "NOT OK without transaction",
+			inTx:       false, // note
+			buildStubs: func() {},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, errLockOutsideTx)
+			},
+		},
+		{
+			name:    "NOT OK context expired while waiting",
+			inTx:    true,
+			timeout: 10 * time.Millisecond, // note
+			buildStubs: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(targetQuery).
+					WithArgs(&owner).
+					WillReturnResult(pgxmock.NewResult("SELECT", 1)).
+					WillDelayFor(time.Second)
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, internal.ErrLockTimeout)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			inTx: true,
+			buildStubs: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(targetQuery).
+					WithArgs(&owner).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			if tc.inTx {
+				tx, err := mock.Begin(ctx)
+				assert.NoError(t, err)
+				ctx = injectTx(ctx, tx)
+			}
+
+			tc.checkResult(t, repo.LockOwner(ctx, owner))
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+type rooms struct {
+	conn DBTX
+}
+
+func NewRooms(conn DBTX) *rooms {
+	return &rooms{
+		conn: conn,
+	}
+}
+
+func (r rooms) Create(ctx context.Context, room domain.Room) (domain.Room, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `insert into rooms(id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds)
+	values($1, $2, $3, $4, $5, $6, $7)
+	returning id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds`
+
+	before, after := bufferSeconds(room.Buffer)
+	created, err := scanRoom(tx.QueryRow(ctx, query, &room.ID, &room.Name, &room.Capacity, &room.Location, &room.Active, &before, &after))
+	if err != nil {
+		if isPgError(err, uniqueViolationCode) {
+			return domain.Room{}, domain.ErrRoomAlreadyExists
+		}
+		return domain.Room{}, err
+	}
+
+	return created, nil
+}
+
+func (r rooms) GetByID(ctx context.Context, id domain.RoomID) (domain.Room, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds from rooms
+	where id = $1`
+
+	room, err := scanRoom(tx.QueryRow(ctx, query, &id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Room{}, domain.ErrRoomNotFound
+		}
+		return domain.Room{}, err
+	}
+
+	return room, nil
+}
+
+func (r rooms) List(ctx context.Context) ([]domain.Room, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds from rooms
+	order by id`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRooms(rows)
+}
+
+// ListAvailable активные комнаты без бронирований, пересекающихся с timeRange
+// с учетом буфера комнаты, и без blackout на timeRange, одним запросом вместо обхода комнат по одной.
+// Часы работы здесь не проверяются: их проверяет вызывающий через RoomSchedule.Covers
+func (r rooms) ListAvailable(ctx context.Context, timeRange domain.TimeRange, minCapacity int) ([]domain.Room, error) {
+	tx := solveTx(r.conn, ctx)
+
+	// то же условие, что RoomBuffer.Widen: бронирование расширяется на оба буфера
+	query := `select r.id, r.name, r.capacity, r.location, r.active, r.buffer_before_seconds, r.buffer_after_seconds from rooms r
+	where r.active and r.capacity >= $3
+	and not exists (
+		select 1 from reservations b
+		where b.room_id = r.id
+		and b.start_time < $2::timestamp + make_interval(secs => r.buffer_before_seconds + r.buffer_after_seconds)
+		and b.end_time > $1::timestamp - make_interval(secs => r.buffer_before_seconds + r.buffer_after_seconds)
+		and (b.expires_at is null or b.expires_at > statement_timestamp() at time zone 'utc')
+	)
+	and not exists (
+		select 1 from room_blackouts o
+		where o.room_id = r.id
+		and o.start_time < $2 and o.end_time > $1
+	)
+	order by r.id`
+
+	rows, err := tx.Query(ctx, query, &timeRange.Start, &timeRange.End, &minCapacity)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRooms(rows)
+}
+
+func (r rooms) Update(ctx context.Context, room domain.Room) (domain.Room, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `update rooms set name = $2, capacity = $3, location = $4, active = $5,
+	buffer_before_seconds = $6, buffer_after_seconds = $7
+	where id = $1
+	returning id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds`
+
+	before, after := bufferSeconds(room.Buffer)
+	updated, err := scanRoom(tx.QueryRow(ctx, query, &room.ID, &room.Name, &room.Capacity, &room.Location, &room.Active, &before, &after))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Room{}, domain.ErrRoomNotFound
+		}
+		return domain.Room{}, err
+	}
+
+	return updated, nil
+}
+
+func (r rooms) Delete(ctx context.Context, id domain.RoomID) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `delete from rooms where id = $1`
+
+	tag, err := tx.Exec(ctx, query, &id)
+	if err != nil {
+		if isPgError(err, foreignKeyViolationCode) {
+			return domain.ErrRoomInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRoomNotFound
+	}
+	return nil
+}
+
+func scanRoom(row pgx.Row) (domain.Room, error) {
+	var (
+		room          domain.Room
+		before, after int64
+	)
+	if err := row.Scan(
+		&room.ID,
+		&room.Name,
+		&room.Capacity,
+		&room.Location,
+		&room.Active,
+		&before,
+		&after,
+	); err != nil {
+		return domain.Room{}, err
+	}
+	room.Buffer = domain.RoomBuffer{
+		Before: time.Duration(before) * time.Second,
+		After:  time.Duration(after) * time.Second,
+	}
+	return room, nil
+}
+
+// bufferSeconds буфер хранится в секундах
+func bufferSeconds(b domain.RoomBuffer) (before, after int64) {
+	return int64(b.Before / time.Second), int64(b.After / time.Second)
+}
+
+func scanRooms(rows pgx.Rows) ([]domain.Room, error) {
+	defer rows.Close()
+
+	var rooms []domain.Room
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+var roomsColumns = []string{"id", "name", "capacity", "location", "active", "buffer_before_seconds", "buffer_after_seconds"}
+
+func roomRow(room domain.Room) *pgxmock.Rows {
+	before, after := bufferSeconds(room.Buffer)
+	return pgxmock.NewRows(roomsColumns).
+		AddRow(room.ID, room.Name, room.Capacity, room.Location, room.Active, before, after)
+}
+
+func Test_CreateRoom(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewRooms(mock)
+
+	targetQuery := "insert into rooms"
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Location: "2nd floor",
+		Active:   true,
+		Buffer:   domain.RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute},
+	}
+	before, after := int64(300), int64(900)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, room domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID, &defaultRoom.Name, &defaultRoom.Capacity, &defaultRoom.Location, &defaultRoom.Active, &before, &after).
+					WillReturnRows(roomRow(defaultRoom))
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRoom, room)
+			},
+		},
+		{
+			name: "NOT OK already exists",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID, &defaultRoom.Name, &defaultRoom.Capacity, &defaultRoom.Location, &defaultRoom.Active, &before, &after).
+					WillReturnError(&pgconn.PgError{Code: uniqueViolationCode}) // note
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomAlreadyExists)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID, &defaultRoom.Name, &defaultRoom.Capacity, &defaultRoom.Location, &defaultRoom.Active, &before, &after).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			room, err := repo.Create(context.Background(), defaultRoom)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_GetRoomByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewRooms(mock)
+
+	targetQuery := "select id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds from rooms"
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, room domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID).
+					WillReturnRows(roomRow(defaultRoom))
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRoom, room)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID).
+					WillReturnRows(pgxmock.NewRows(roomsColumns)) // note
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			room, err := repo.GetByID(context.Background(), defaultRoom.ID)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_ListRooms(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewRooms(mock)
+
+	targetQuery := "select id, name, capacity, location, active, buffer_before_seconds, buffer_after_seconds from rooms"
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, rooms []domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					RowsWillBeClosed().
+					WillReturnRows(roomRow(defaultRoom))
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Room{defaultRoom}, rooms)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, rooms)
+			},
+		},
+		{
+			name: "NOT OK error rows",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					RowsWillBeClosed().
+					WillReturnRows(pgxmock.NewRows(roomsColumns).
+						RowError(0, unexpectedError))
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			rooms, err := repo.List(context.Background())
+			tc.checkResult(t, rooms, err)
+		})
+	}
+}
+
+func Test_ListAvailableRooms(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewRooms(mock)
+
+	targetQuery := "select r.id, r.name, r.capacity, r.location, r.active, r.buffer_before_seconds, r.buffer_after_seconds from rooms r\\s+" +
+		"where r.active and r.capacity >= \\$3\\s+and not exists.+make_interval\\(secs => r.buffer_before_seconds \\+ r.buffer_after_seconds\\).+" +
+		"and not exists \\(\\s+select 1 from room_blackouts o\\s+where o.room_id = r.id\\s+and o.start_time < \\$2 and o.end_time > \\$1"
+
+	unexpectedError := errors.New("unexpected error")
+
+	from := time.Now().Truncate(time.Second).UTC()
+	tr := domain.TimeRange{Start: from, End: from.Add(1 * time.Hour)}
+	minCapacity := 5
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, rooms []domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&tr.Start, &tr.End, &minCapacity).
+					RowsWillBeClosed().
+					WillReturnRows(roomRow(defaultRoom))
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Room{defaultRoom}, rooms)
+			},
+		},
+		{
+			name: "OK no rooms",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&tr.Start, &tr.End, &minCapacity).
+					RowsWillBeClosed().
+					WillReturnRows(pgxmock.NewRows(roomsColumns)) // note
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, rooms)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&tr.Start, &tr.End, &minCapacity).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, rooms []domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, rooms)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			rooms, err := repo.ListAvailable(context.Background(), tr, minCapacity)
+			tc.checkResult(t, rooms, err)
+		})
+	}
+}
+
+func Test_UpdateRoom(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewRooms(mock)
+
+	targetQuery := "update rooms"
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   false,
+		Buffer:   domain.RoomBuffer{After: 10 * time.Minute},
+	}
+	before, after := int64(0), int64(600)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, room domain.Room, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID, &defaultRoom.Name, &defaultRoom.Capacity, &defaultRoom.Location, &defaultRoom.Active, &before, &after).
+					WillReturnRows(roomRow(defaultRoom))
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultRoom, room)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID, &defaultRoom.Name, &defaultRoom.Capacity, &defaultRoom.Location, &defaultRoom.Active, &before, &after).
+					WillReturnRows(pgxmock.NewRows(roomsColumns)) // note
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, room)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultRoom.ID, &defaultRoom.Name, &defaultRoom.Capacity, &defaultRoom.Location, &defaultRoom.Active, &before, &after).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, room domain.Room, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, room)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			room, err := repo.Update(context.Background(), defaultRoom)
+			tc.checkResult(t, room, err)
+		})
+	}
+}
+
+func Test_DeleteRoom(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewRooms(mock)
+
+	targetQuery := "delete from rooms"
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultRoomID := domain.RoomID("conf-a")
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultRoomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultRoomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0)) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+			},
+		},
+		{
+			name: "NOT OK room has reservations",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultRoomID).
+					WillReturnError(&pgconn.PgError{Code: foreignKeyViolationCode}) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomInUse)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&defaultRoomID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			err := repo.Delete(context.Background(), defaultRoomID)
+			tc.checkResult(t, err)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type schedules struct {
+	conn DBTX
+}
+
+func NewSchedules(conn DBTX) *schedules {
+	return &schedules{
+		conn: conn,
+	}
+}
+
+func (r schedules) GetSchedule(ctx context.Context, roomID domain.RoomID) (domain.RoomSchedule, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select time_zone from rooms
+	where id = $1`
+
+	var timeZone string
+	if err := tx.QueryRow(ctx, query, &roomID).Scan(&timeZone); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RoomSchedule{}, domain.ErrRoomNotFound
+		}
+		return domain.RoomSchedule{}, err
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return domain.RoomSchedule{}, err
+	}
+
+	query = `select weekday, open_seconds, close_seconds from room_opening_hours
+	where room_id = $1
+	order by weekday, open_seconds`
+
+	rows, err := tx.Query(ctx, query, &roomID)
+	if err != nil {
+		return domain.RoomSchedule{}, err
+	}
+	defer rows.Close()
+
+	schedule := domain.RoomSchedule{
+		RoomID:   roomID,
+		Location: loc,
+	}
+	for rows.Next() {
+		var weekday, open, close int64
+		if err := rows.Scan(&weekday, &open, &close); err != nil {
+			return domain.RoomSchedule{}, err
+		}
+		schedule.Hours = append(schedule.Hours, domain.OpeningHours{
+			Weekday: time.Weekday(weekday),
+			Open:    time.Duration(open) * time.Second,
+			Close:   time.Duration(close) * time.Second,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return domain.RoomSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+// ListSchedules часовые пояса и часы работы всех комнат одним запросом, у комнаты без часов работы
+// left join дает одну строку с null вместо часов
+func (r schedules) ListSchedules(ctx context.Context, roomIDs []domain.RoomID) (map[domain.RoomID]domain.RoomSchedule, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select r.id, r.time_zone, h.weekday, h.open_seconds, h.close_seconds from rooms r
+	left join room_opening_hours h on h.room_id = r.id
+	where r.id = any($1::varchar[])
+	order by r.id, h.weekday, h.open_seconds`
+
+	ids := make([]string, 0, len(roomIDs))
+	for _, id := range roomIDs {
+		ids = append(ids, string(id))
+	}
+
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := make(map[domain.RoomID]domain.RoomSchedule, len(roomIDs))
+	for rows.Next() {
+		var (
+			roomID               domain.RoomID
+			timeZone             string
+			weekday, open, close *int64
+		)
+		if err := rows.Scan(&roomID, &timeZone, &weekday, &open, &close); err != nil {
+			return nil, err
+		}
+
+		schedule, ok := schedules[roomID]
+		if !ok {
+			loc, err := time.LoadLocation(timeZone)
+			if err != nil {
+				return nil, err
+			}
+			schedule = domain.RoomSchedule{
+				RoomID:   roomID,
+				Location: loc,
+			}
+		}
+		if weekday != nil {
+			schedule.Hours = append(schedule.Hours, domain.OpeningHours{
+				Weekday: time.Weekday(*weekday),
+				Open:    time.Duration(*open) * time.Second,
+				Close:   time.Duration(*close) * time.Second,
+			})
+		}
+		schedules[roomID] = schedule
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// SetSchedule выполняет несколько запросов, вызывается внутри транзакции
+func (r schedules) SetSchedule(ctx context.Context, schedule domain.RoomSchedule) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `update rooms set time_zone = $2
+	where id = $1`
+
+	timeZone := schedule.TimeZone()
+	tag, err := tx.Exec(ctx, query, &schedule.RoomID, &timeZone)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRoomNotFound
+	}
+
+	query = `delete from room_opening_hours where room_id = $1`
+
+	if _, err := tx.Exec(ctx, query, &schedule.RoomID); err != nil {
+		return err
+	}
+
+	if len(schedule.Hours) == 0 {
+		return nil
+	}
+
+	weekdays := make([]int32, 0, len(schedule.Hours))
+	opens := make([]int32, 0, len(schedule.Hours))
+	closes := make([]int32, 0, len(schedule.Hours))
+	for _, h := range schedule.Hours {
+		weekdays = append(weekdays, int32(h.Weekday))
+		opens = append(opens, int32(h.Open/time.Second))
+		closes = append(closes, int32(h.Close/time.Second))
+	}
+
+	query = `insert into room_opening_hours(room_id, weekday, open_seconds, close_seconds)
+	select $1, t.weekday, t.open_seconds, t.close_seconds
+	from unnest($2::smallint[], $3::int[], $4::int[]) as t(weekday, open_seconds, close_seconds)`
+
+	_, err = tx.Exec(ctx, query, &schedule.RoomID, weekdays, opens, closes)
+	return err
+}
+
+func (r schedules) FindBlackouts(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) ([]domain.Blackout, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, room_id, start_time, end_time, reason from room_blackouts
+	where room_id = $1 and start_time < $3 and end_time > $2
+	order by start_time, id`
+
+	rows, err := tx.Query(ctx, query, &roomID, &timeRange.Start, &timeRange.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blackouts []domain.Blackout
+	for rows.Next() {
+		blackout, err := scanBlackout(rows)
+		if err != nil {
+			return nil, err
+		}
+		blackouts = append(blackouts, blackout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blackouts, nil
+}
+
+func (r schedules) CreateBlackout(ctx context.Context, blackout domain.Blackout) (domain.Blackout, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `insert into room_blackouts(room_id, start_time, end_time, reason)
+	values($1, $2, $3, $4)
+	returning id, room_id, start_time, end_time, reason`
+
+	created, err := scanBlackout(tx.QueryRow(ctx, query,
+		&blackout.RoomID, &blackout.TimeRange.Start, &blackout.TimeRange.End, &blackout.Reason))
+	if err != nil {
+		if isPgError(err, foreignKeyViolationCode) {
+			return domain.Blackout{}, domain.ErrRoomNotFound
+		}
+		return domain.Blackout{}, err
+	}
+
+	return created, nil
+}
+
+func (r schedules) DeleteBlackout(ctx context.Context, roomID domain.RoomID, id int64) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `delete from room_blackouts where id = $1 and room_id = $2`
+
+	tag, err := tx.Exec(ctx, query, &id, &roomID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrBlackoutNotFound
+	}
+	return nil
+}
+
+func scanBlackout(row pgx.Row) (domain.Blackout, error) {
+	var blackout domain.Blackout
+	if err := row.Scan(
+		&blackout.ID,
+		&blackout.RoomID,
+		&blackout.TimeRange.Start,
+		&blackout.TimeRange.End,
+		&blackout.Reason,
+	); err != nil {
+		return domain.Blackout{}, err
+	}
+	return blackout, nil
+}
@@ -0,0 +1,519 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+var blackoutsColumns = []string{"id", "room_id", "start_time", "end_time", "reason"}
+
+func blackoutRow(b domain.Blackout) *pgxmock.Rows {
+	return pgxmock.NewRows(blackoutsColumns).
+		AddRow(b.ID, b.RoomID, b.TimeRange.Start, b.TimeRange.End, b.Reason)
+}
+
+func Test_GetSchedule(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewSchedules(mock)
+
+	timeZoneQuery := "select time_zone from rooms"
+	hoursQuery := "select weekday, open_seconds, close_seconds from room_opening_hours"
+
+	unexpectedError := errors.New("unexpected error")
+
+	roomID := domain.RoomID("conf-a")
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, schedule domain.RoomSchedule, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(timeZoneQuery).
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows([]string{"time_zone"}).AddRow("Europe/Berlin"))
+				mock.ExpectQuery(hoursQuery).
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows([]string{"weekday", "open_seconds", "close_seconds"}).
+						AddRow(int64(1), int64(9*3600), int64(18*3600)))
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.RoomSchedule{
+					RoomID:   roomID,
+					Location: berlin,
+					Hours:    []domain.OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}},
+				}, schedule)
+			},
+		},
+		{
+			name: "OK no hours",
+			buildStubs: func() {
+				mock.ExpectQuery(timeZoneQuery).
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows([]string{"time_zone"}).AddRow("UTC"))
+				mock.ExpectQuery(hoursQuery).
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows([]string{"weekday", "open_seconds", "close_seconds"})) // note
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "UTC", schedule.TimeZone())
+				assert.Empty(t, schedule.Hours)
+			},
+		},
+		{
+			name: "NOT OK room not found",
+			buildStubs: func() {
+				mock.ExpectQuery(timeZoneQuery).
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows([]string{"time_zone"})) // note
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, schedule)
+			},
+		},
+		{
+			name: "NOT OK error unexpected from hours",
+			buildStubs: func() {
+				mock.ExpectQuery(timeZoneQuery).
+					WithArgs(&roomID).
+					WillReturnRows(pgxmock.NewRows([]string{"time_zone"}).AddRow("UTC"))
+				mock.ExpectQuery(hoursQuery).
+					WithArgs(&roomID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, schedule domain.RoomSchedule, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, schedule)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			schedule, err := repo.GetSchedule(context.Background(), roomID)
+			tc.checkResult(t, schedule, err)
+		})
+	}
+}
+
+func Test_ListSchedules(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewSchedules(mock)
+
+	targetQuery := "select r.id, r.time_zone, h.weekday, h.open_seconds, h.close_seconds from rooms r"
+
+	unexpectedError := errors.New("unexpected error")
+
+	roomIDs := []domain.RoomID{"conf-a", "conf-b"}
+	ids := []string{"conf-a", "conf-b"}
+
+	columns := []string{"id", "time_zone", "weekday", "open_seconds", "close_seconds"}
+	// часы работы nullable из-за left join
+	monday := int64(time.Monday)
+	morning := []int64{9 * 3600, 13 * 3600}
+	afternoon := []int64{14 * 3600, 18 * 3600}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, schedules map[domain.RoomID]domain.RoomSchedule, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(ids).
+					WillReturnRows(pgxmock.NewRows(columns).
+						AddRow(domain.RoomID("conf-a"), "Europe/Berlin", &monday, &morning[0], &morning[1]).
+						AddRow(domain.RoomID("conf-a"), "Europe/Berlin", &monday, &afternoon[0], &afternoon[1]).
+						AddRow(domain.RoomID("conf-b"), "UTC", nil, nil, nil))
+			},
+			checkResult: func(t *testing.T, schedules map[domain.RoomID]domain.RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, map[domain.RoomID]domain.RoomSchedule{
+					"conf-a": {
+						RoomID:   "conf-a",
+						Location: berlin,
+						Hours: []domain.OpeningHours{
+							{Weekday: time.Monday, Open: 9 * time.Hour, Close: 13 * time.Hour},
+							{Weekday: time.Monday, Open: 14 * time.Hour, Close: 18 * time.Hour},
+						},
+					},
+					"conf-b": {RoomID: "conf-b", Location: time.UTC},
+				}, schedules)
+			},
+		},
+		{
+			name: "OK no rooms found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(ids).
+					WillReturnRows(pgxmock.NewRows(columns)) // note
+			},
+			checkResult: func(t *testing.T, schedules map[domain.RoomID]domain.RoomSchedule, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, schedules)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(ids).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, schedules map[domain.RoomID]domain.RoomSchedule, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Nil(t, schedules)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			schedules, err := repo.ListSchedules(context.Background(), roomIDs)
+			tc.checkResult(t, schedules, err)
+		})
+	}
+}
+
+func Test_SetSchedule(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewSchedules(mock)
+
+	updateQuery := "update rooms set time_zone"
+	deleteQuery := "delete from room_opening_hours"
+	insertQuery := "insert into room_opening_hours"
+
+	unexpectedError := errors.New("unexpected error")
+
+	defaultSchedule, err := domain.NewRoomSchedule("conf-a", "Europe/Berlin", []domain.OpeningHours{
+		{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour},
+		{Weekday: time.Tuesday, Open: 10 * time.Hour, Close: 12 * time.Hour},
+	})
+	assert.NoError(t, err)
+	timeZone := "Europe/Berlin"
+
+	testCases := []struct {
+		name        string
+		schedule    domain.RoomSchedule
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name:     "OK",
+			schedule: defaultSchedule,
+			buildStubs: func() {
+				mock.ExpectExec(updateQuery).
+					WithArgs(&defaultSchedule.RoomID, &timeZone).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(&defaultSchedule.RoomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 3))
+				mock.ExpectExec(insertQuery).
+					WithArgs(&defaultSchedule.RoomID, []int32{1, 2}, []int32{9 * 3600, 10 * 3600}, []int32{18 * 3600, 12 * 3600}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:     "OK no hours skips insert",
+			schedule: domain.RoomSchedule{RoomID: defaultSchedule.RoomID, Location: defaultSchedule.Location}, // note
+			buildStubs: func() {
+				mock.ExpectExec(updateQuery).
+					WithArgs(&defaultSchedule.RoomID, &timeZone).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(&defaultSchedule.RoomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 2))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:     "NOT OK room not found",
+			schedule: defaultSchedule,
+			buildStubs: func() {
+				mock.ExpectExec(updateQuery).
+					WithArgs(&defaultSchedule.RoomID, &timeZone).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0)) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+			},
+		},
+		{
+			name:     "NOT OK error unexpected from insert",
+			schedule: defaultSchedule,
+			buildStubs: func() {
+				mock.ExpectExec(updateQuery).
+					WithArgs(&defaultSchedule.RoomID, &timeZone).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(&defaultSchedule.RoomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+				mock.ExpectExec(insertQuery).
+					WithArgs(&defaultSchedule.RoomID, []int32{1, 2}, []int32{9 * 3600, 10 * 3600}, []int32{18 * 3600, 12 * 3600}).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			tc.checkResult(t, repo.SetSchedule(context.Background(), tc.schedule))
+		})
+	}
+}
+
+func Test_FindBlackouts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewSchedules(mock)
+
+	targetQuery := "select id, room_id, start_time, end_time, reason from room_blackouts"
+
+	unexpectedError := errors.New("unexpected error")
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	roomID := domain.RoomID("conf-a")
+	window := domain.TimeRange{Start: now, End: now.Add(24 * time.Hour)}
+
+	defaultBlackout := domain.Blackout{
+		ID:        1,
+		RoomID:    roomID,
+		TimeRange: domain.TimeRange{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+		Reason:    "maintenance",
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, blackouts []domain.Blackout, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &window.Start, &window.End).
+					WillReturnRows(blackoutRow(defaultBlackout))
+			},
+			checkResult: func(t *testing.T, blackouts []domain.Blackout, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.Blackout{defaultBlackout}, blackouts)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &window.Start, &window.End).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, blackouts []domain.Blackout, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, blackouts)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			blackouts, err := repo.FindBlackouts(context.Background(), roomID, window)
+			tc.checkResult(t, blackouts, err)
+		})
+	}
+}
+
+func Test_CreateBlackout(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewSchedules(mock)
+
+	targetQuery := "insert into room_blackouts"
+
+	unexpectedError := errors.New("unexpected error")
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	defaultBlackout := domain.Blackout{
+		RoomID:    "conf-a",
+		TimeRange: domain.TimeRange{Start: now, End: now.Add(time.Hour)},
+		Reason:    "maintenance",
+	}
+	created := defaultBlackout
+	created.ID = 1
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, blackout domain.Blackout, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultBlackout.RoomID, &defaultBlackout.TimeRange.Start, &defaultBlackout.TimeRange.End, &defaultBlackout.Reason).
+					WillReturnRows(blackoutRow(created))
+			},
+			checkResult: func(t *testing.T, blackout domain.Blackout, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, created, blackout)
+			},
+		},
+		{
+			name: "NOT OK room not found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultBlackout.RoomID, &defaultBlackout.TimeRange.Start, &defaultBlackout.TimeRange.End, &defaultBlackout.Reason).
+					WillReturnError(&pgconn.PgError{Code: foreignKeyViolationCode}) // note
+			},
+			checkResult: func(t *testing.T, blackout domain.Blackout, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, blackout)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&defaultBlackout.RoomID, &defaultBlackout.TimeRange.Start, &defaultBlackout.TimeRange.End, &defaultBlackout.Reason).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, blackout domain.Blackout, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, blackout)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			blackout, err := repo.CreateBlackout(context.Background(), defaultBlackout)
+			tc.checkResult(t, blackout, err)
+		})
+	}
+}
+
+func Test_DeleteBlackout(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewSchedules(mock)
+
+	targetQuery := "delete from room_blackouts"
+
+	unexpectedError := errors.New("unexpected error")
+
+	roomID := domain.RoomID("conf-a")
+	id := int64(1)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&id, &roomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&id, &roomID).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0)) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrBlackoutNotFound)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&id, &roomID).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			tc.checkResult(t, repo.DeleteBlackout(context.Background(), roomID, id))
+		})
+	}
+}
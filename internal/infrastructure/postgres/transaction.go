@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -12,16 +13,43 @@ type TxBeginner interface {
 }
 
 type transaction struct {
-	conn TxBeginner
+	conn  TxBeginner
+	retry RetryPolicy
 }
 
-func NewTxManager(conn TxBeginner) *transaction {
+func NewTxManager(conn TxBeginner, retry RetryPolicy) *transaction {
 	return &transaction{
-		conn: conn,
+		conn:  conn,
+		retry: retry,
 	}
 }
 
+// Execute выполняет f в транзакции, при конфликте сериализации или дедлоке
+// транзакция повторяется целиком, поэтому f должна быть готова к повторному вызову.
+//
+// Если в ctx уже есть транзакция, f выполняется в savepoint внутри нее:
+// при ошибке откатывается только savepoint, options игнорируются (уровень изоляции
+// задает внешняя транзакция), а повтор выполняет внешний Execute
 func (t *transaction) Execute(ctx context.Context, f func(txCtx context.Context) error, options pgx.TxOptions) (err error) {
+	if parent := extractTx(ctx); parent != nil {
+		return t.savepoint(ctx, parent, f)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = t.execute(ctx, f, options)
+		if err == nil || attempt >= t.retry.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(t.retry.backoff(attempt)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (t *transaction) execute(ctx context.Context, f func(txCtx context.Context) error, options pgx.TxOptions) (err error) {
 	// WARNING: нужно использовать пул подключений
 	tx, err := t.conn.BeginTx(ctx, options)
 	if err != nil {
@@ -39,12 +67,47 @@ func (t *transaction) Execute(ctx context.Context, f func(txCtx context.Context)
 	return tx.Commit(ctx)
 }
 
-type txKey struct{}
+// savepoint pgx.Tx.Begin у уже открытой транзакции создает savepoint,
+// Commit его отпускает, а Rollback откатывает только до него
+func (t *transaction) savepoint(ctx context.Context, parent pgx.Tx, f func(txCtx context.Context) error) (err error) {
+	depth := TxDepth(ctx) + 1
+
+	tx, err := parent.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("savepoint (depth %d): %w", depth, err)
+	}
+
+	if err = f(injectTx(ctx, tx)); err != nil {
+		rollbackErr := tx.Rollback(ctx)
+		if rollbackErr != nil {
+			return fmt.Errorf("%w | rollback savepoint (depth %d) err: %w", err, depth, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("release savepoint (depth %d): %w", depth, err)
+	}
+	return nil
+}
+
+type (
+	txKey      struct{}
+	txDepthKey struct{}
+)
 
 func injectTx(ctx context.Context, tx pgx.Tx) context.Context {
+	ctx = context.WithValue(ctx, txDepthKey{}, TxDepth(ctx)+1)
 	return context.WithValue(ctx, txKey{}, tx)
 }
 
+// TxDepth глубина вложенности транзакций в ctx для отладки:
+// 0 - вне транзакции, 1 - внешняя транзакция, 2 и больше - savepoint
+func TxDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(txDepthKey{}).(int)
+	return depth
+}
+
 func extractTx(ctx context.Context) (tx pgx.Tx) {
 	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
 		return tx
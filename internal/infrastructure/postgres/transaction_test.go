@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/ynuraddi/test-kami/internal/domain"
@@ -18,7 +21,7 @@ func Test_Transaction(t *testing.T) {
 	defer mock.Close()
 	defer assert.NoError(t, mock.ExpectationsWereMet())
 
-	transaction := NewTxManager(mock)
+	transaction := NewTxManager(mock, DefaultRetryPolicy)
 
 	// если он не будет использовать внедреннуб транзакцию то он запникует
 	// из-за nil
@@ -27,7 +30,7 @@ func Test_Transaction(t *testing.T) {
 	unexpectedError := errors.New("unexpected error")
 	someErr := errors.New("some error")
 
-	reservationsColumns := []string{"id", "room_id", "start_time", "end_time"}
+	reservationsColumns := []string{"id", "room_id", "start_time", "end_time", "version", "status", "expires_at", "owner", "title", "description", "attendees"}
 
 	type args struct {
 		do     func(txCtx context.Context) error
@@ -69,13 +72,13 @@ func Test_Transaction(t *testing.T) {
 			args: args{
 				option: defaultOptions,
 				do: func(txCtx context.Context) error {
-					_, err := repo.ListByRoom(txCtx, defaultRoomID)
+					_, err := repo.ListByRoom(txCtx, domain.ReservationQuery{RoomID: defaultRoomID})
 					return err
 				},
 			},
 			buildStubs: func() {
 				mock.ExpectBeginTx(defaultOptions)
-				mock.ExpectQuery("select id, room_id, start_time, end_time from reservations").
+				mock.ExpectQuery("select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations").
 					WithArgs(&defaultRoomID).
 					WillReturnRows(pgxmock.NewRows(reservationsColumns)) // note len zero
 				mock.ExpectCommit()
@@ -89,13 +92,13 @@ func Test_Transaction(t *testing.T) {
 			args: args{
 				option: defaultOptions,
 				do: func(txCtx context.Context) error {
-					_, err := repo.ListByRoom(txCtx, "1")
+					_, err := repo.ListByRoom(txCtx, domain.ReservationQuery{RoomID: "1"})
 					return err
 				},
 			},
 			buildStubs: func() {
 				mock.ExpectBeginTx(defaultOptions)
-				mock.ExpectQuery("select id, room_id, start_time, end_time from reservations").
+				mock.ExpectQuery("select id, room_id, start_time, end_time, version, status, expires_at, owner, title, description, attendees from reservations").
 					WithArgs(&defaultRoomID).
 					WillReturnError(unexpectedError) // note len zero
 				mock.ExpectRollback()
@@ -116,6 +119,17 @@ func Test_Transaction(t *testing.T) {
 				assert.ErrorIs(t, err, unexpectedError)
 			},
 		},
+		{
+			name: "NOT OK begin tx deadline exceeded",
+			args: defaultArgs,
+			buildStubs: func() {
+				// пул не отдал соединение до дедлайна, повторять нечего
+				mock.ExpectBeginTx(defaultOptions).WillReturnError(context.DeadlineExceeded) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
 		{
 			name: "NOT OK error rollback",
 			args: args{
@@ -142,3 +156,302 @@ func Test_Transaction(t *testing.T) {
 		})
 	}
 }
+
+func Test_TransactionRetry(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	transaction := NewTxManager(mock, RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	})
+
+	options := pgx.TxOptions{IsoLevel: pgx.ReadCommitted}
+
+	serializationErr := &pgconn.PgError{Code: serializationFailureCode}
+	deadlockErr := &pgconn.PgError{Code: deadlockDetectedCode}
+	uniqueErr := &pgconn.PgError{Code: "23505"}
+
+	testCases := []struct {
+		name        string
+		failures    int // сколько первых вызовов f вернут err
+		err         error
+		buildStubs  func()
+		checkResult func(t *testing.T, calls int, err error)
+	}{
+		{
+			name:     "OK retry after serialization failure",
+			failures: 1,
+			err:      serializationErr,
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectRollback()
+				mock.ExpectBeginTx(options)
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, calls int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		{
+			name:     "OK retry after deadlock",
+			failures: 1,
+			err:      deadlockErr,
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectRollback()
+				mock.ExpectBeginTx(options)
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, calls int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		{
+			name: "OK retry after serialization failure on commit",
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectCommit().WillReturnError(serializationErr) // note
+				mock.ExpectBeginTx(options)
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, calls int, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, calls)
+			},
+		},
+		{
+			name:     "NOT OK attempts exhausted",
+			failures: 10,
+			err:      serializationErr,
+			buildStubs: func() {
+				for i := 0; i < 3; i++ {
+					mock.ExpectBeginTx(options)
+					mock.ExpectRollback()
+				}
+			},
+			checkResult: func(t *testing.T, calls int, err error) {
+				assert.ErrorIs(t, err, serializationErr)
+				assert.Equal(t, 3, calls)
+			},
+		},
+		{
+			name:     "NOT OK domain error is not retried",
+			failures: 10,
+			err:      domain.ReservationConflictError{},
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectRollback()
+			},
+			checkResult: func(t *testing.T, calls int, err error) {
+				assert.ErrorIs(t, err, domain.ReservationConflictError{})
+				assert.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:     "NOT OK other postgres error is not retried",
+			failures: 10,
+			err:      uniqueErr,
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectRollback()
+			},
+			checkResult: func(t *testing.T, calls int, err error) {
+				assert.ErrorIs(t, err, uniqueErr)
+				assert.Equal(t, 1, calls)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			calls := 0
+			err := transaction.Execute(context.Background(), func(txCtx context.Context) error {
+				calls++
+				if calls <= tc.failures {
+					return fmt.Errorf("wrapped: %w", tc.err)
+				}
+				return nil
+			}, options)
+			tc.checkResult(t, calls, err)
+		})
+	}
+}
+
+func Test_RetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 30 * time.Millisecond}
+
+	for attempt, max := range []time.Duration{10, 20, 30, 30} {
+		max *= time.Millisecond
+		for i := 0; i < 100; i++ {
+			delay := policy.backoff(attempt + 1)
+			assert.GreaterOrEqual(t, delay, max/2)
+			assert.LessOrEqual(t, delay, max)
+		}
+	}
+}
+
+func Test_NestedTransaction(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	transaction := NewTxManager(mock, DefaultRetryPolicy)
+
+	options := pgx.TxOptions{IsoLevel: pgx.ReadCommitted}
+
+	unexpectedError := errors.New("unexpected error")
+	serializationErr := &pgconn.PgError{Code: serializationFailureCode}
+
+	testCases := []struct {
+		name        string
+		do          func(txCtx context.Context) error
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK savepoint released, outer committed",
+			do: func(txCtx context.Context) error {
+				assert.Equal(t, 1, TxDepth(txCtx))
+				return transaction.Execute(txCtx, func(txCtx context.Context) error {
+					assert.Equal(t, 2, TxDepth(txCtx))
+					return nil
+				}, options)
+			},
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin()  // savepoint
+				mock.ExpectCommit() // release savepoint
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "OK failed savepoint rolled back, outer continues",
+			do: func(txCtx context.Context) error {
+				err := transaction.Execute(txCtx, func(txCtx context.Context) error {
+					return domain.ReservationConflictError{}
+				}, options)
+				assert.ErrorIs(t, err, domain.ReservationConflictError{})
+				return nil
+			},
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin()
+				mock.ExpectRollback() // rollback to savepoint
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "OK depth of deeply nested savepoints",
+			do: func(txCtx context.Context) error {
+				return transaction.Execute(txCtx, func(txCtx context.Context) error {
+					return transaction.Execute(txCtx, func(txCtx context.Context) error {
+						assert.Equal(t, 3, TxDepth(txCtx))
+						return nil
+					}, options)
+				}, options)
+			},
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin()
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+				mock.ExpectCommit()
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK serialization failure retried by outer transaction only",
+			do: func() func(txCtx context.Context) error {
+				calls := 0
+				return func(txCtx context.Context) error {
+					return transaction.Execute(txCtx, func(txCtx context.Context) error {
+						calls++
+						if calls == 1 {
+							return serializationErr
+						}
+						return nil
+					}, options)
+				}
+			}(),
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin()
+				mock.ExpectRollback() // savepoint
+				mock.ExpectRollback() // outer
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+				mock.ExpectCommit()
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK error begin savepoint",
+			do: func(txCtx context.Context) error {
+				return transaction.Execute(txCtx, func(txCtx context.Context) error {
+					t.Fatal("should not be called without savepoint")
+					return nil
+				}, options)
+			},
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin().WillReturnError(unexpectedError) // note
+				mock.ExpectRollback()
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.ErrorContains(t, err, "depth 2")
+			},
+		},
+		{
+			name: "NOT OK error rollback savepoint",
+			do: func(txCtx context.Context) error {
+				return transaction.Execute(txCtx, func(txCtx context.Context) error {
+					return domain.ReservationConflictError{}
+				}, options)
+			},
+			buildStubs: func() {
+				mock.ExpectBeginTx(options)
+				mock.ExpectBegin()
+				mock.ExpectRollback().WillReturnError(unexpectedError) // note
+				mock.ExpectRollback()
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ReservationConflictError{})
+				assert.ErrorIs(t, err, unexpectedError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			assert.Equal(t, 0, TxDepth(context.Background()))
+			err := transaction.Execute(context.Background(), tc.do, options)
+			tc.checkResult(t, err)
+		})
+	}
+}
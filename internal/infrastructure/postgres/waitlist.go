@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type waitlist struct {
+	conn DBTX
+}
+
+func NewWaitlist(conn DBTX) *waitlist {
+	return &waitlist{
+		conn: conn,
+	}
+}
+
+func (r waitlist) Create(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange, details domain.ReservationDetails) (domain.WaitlistEntry, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `insert into reservation_waitlist(room_id, start_time, end_time, owner, title, description, attendees)
+	values($1, $2, $3, $4, $5, $6, coalesce($7::text[], '{}'))
+	returning id, room_id, start_time, end_time, status, owner, title, description, attendees, coalesce(reservation_id, 0), created_at`
+
+	entry, err := scanWaitlistEntry(tx.QueryRow(ctx, query, &roomID, &timeRange.Start, &timeRange.End,
+		&details.Owner, &details.Title, &details.Description, details.Attendees))
+	if err != nil {
+		if isPgError(err, foreignKeyViolationCode) {
+			return domain.WaitlistEntry{}, domain.ErrRoomNotFound
+		}
+		return domain.WaitlistEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r waitlist) GetByID(ctx context.Context, id int64) (domain.WaitlistEntry, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, room_id, start_time, end_time, status, owner, title, description, attendees, coalesce(reservation_id, 0), created_at from reservation_waitlist
+	where id = $1`
+
+	entry, err := scanWaitlistEntry(tx.QueryRow(ctx, query, &id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WaitlistEntry{}, domain.ErrWaitlistEntryNotFound
+		}
+		return domain.WaitlistEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r waitlist) FindWaiting(ctx context.Context, roomID domain.RoomID, timeRange domain.TimeRange) ([]domain.WaitlistEntry, error) {
+	tx := solveTx(r.conn, ctx)
+
+	query := `select id, room_id, start_time, end_time, status, owner, title, description, attendees, coalesce(reservation_id, 0), created_at from reservation_waitlist
+	where room_id = $1 and status = 'waiting' and start_time < $3 and end_time > $2
+	order by created_at, id`
+
+	rows, err := tx.Query(ctx, query, &roomID, &timeRange.Start, &timeRange.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.WaitlistEntry
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r waitlist) Promote(ctx context.Context, id int64, reservationID int64) error {
+	tx := solveTx(r.conn, ctx)
+
+	query := `update reservation_waitlist set status = 'promoted', reservation_id = $2
+	where id = $1 and status = 'waiting'`
+
+	tag, err := tx.Exec(ctx, query, &id, &reservationID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWaitlistEntryNotFound
+	}
+	return nil
+}
+
+func scanWaitlistEntry(row pgx.Row) (domain.WaitlistEntry, error) {
+	var entry domain.WaitlistEntry
+	if err := row.Scan(
+		&entry.ID,
+		&entry.RoomID,
+		&entry.TimeRange.Start,
+		&entry.TimeRange.End,
+		&entry.Status,
+		&entry.Details.Owner,
+		&entry.Details.Title,
+		&entry.Details.Description,
+		&entry.Details.Attendees,
+		&entry.ReservationID,
+		&entry.CreatedAt,
+	); err != nil {
+		return domain.WaitlistEntry{}, err
+	}
+	if len(entry.Details.Attendees) == 0 {
+		entry.Details.Attendees = nil
+	}
+	return entry, nil
+}
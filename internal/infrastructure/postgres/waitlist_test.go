@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+var waitlistColumns = []string{"id", "room_id", "start_time", "end_time", "status", "owner", "title", "description", "attendees", "reservation_id", "created_at"}
+
+func waitlistRow(e domain.WaitlistEntry) *pgxmock.Rows {
+	return pgxmock.NewRows(waitlistColumns).
+		AddRow(e.ID, e.RoomID, e.TimeRange.Start, e.TimeRange.End, e.Status, e.Details.Owner, e.Details.Title, e.Details.Description, e.Details.Attendees, e.ReservationID, e.CreatedAt)
+}
+
+func Test_CreateWaitlistEntry(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewWaitlist(mock)
+
+	targetQuery := "insert into reservation_waitlist"
+
+	unexpectedError := errors.New("unexpected error")
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	roomID := domain.RoomID("conf-a")
+	tr := domain.TimeRange{Start: now, End: now.Add(time.Hour)}
+	details := domain.ReservationDetails{Owner: "alice", Title: "retro", Attendees: []string{"bob"}}
+
+	created := domain.WaitlistEntry{
+		ID:        1,
+		RoomID:    roomID,
+		TimeRange: tr,
+		Status:    domain.WaitlistWaiting,
+		Details:   details,
+		CreatedAt: now,
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, entry domain.WaitlistEntry, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &tr.Start, &tr.End, &details.Owner, &details.Title, &details.Description, details.Attendees).
+					WillReturnRows(waitlistRow(created))
+			},
+			checkResult: func(t *testing.T, entry domain.WaitlistEntry, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, created, entry)
+			},
+		},
+		{
+			name: "NOT OK room not found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &tr.Start, &tr.End, &details.Owner, &details.Title, &details.Description, details.Attendees).
+					WillReturnError(&pgconn.PgError{Code: foreignKeyViolationCode}) // note
+			},
+			checkResult: func(t *testing.T, entry domain.WaitlistEntry, err error) {
+				assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+				assert.Empty(t, entry)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &tr.Start, &tr.End, &details.Owner, &details.Title, &details.Description, details.Attendees).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, entry domain.WaitlistEntry, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, entry)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			entry, err := repo.Create(context.Background(), roomID, tr, details)
+			tc.checkResult(t, entry, err)
+		})
+	}
+}
+
+func Test_GetWaitlistEntry(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewWaitlist(mock)
+
+	targetQuery := "select id, room_id, start_time, end_time, status, owner, title, description, attendees, coalesce\\(reservation_id, 0\\), created_at from reservation_waitlist"
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	id := int64(1)
+	promoted := domain.WaitlistEntry{
+		ID:            id,
+		RoomID:        "conf-a",
+		TimeRange:     domain.TimeRange{Start: now, End: now.Add(time.Hour)},
+		Status:        domain.WaitlistPromoted,
+		ReservationID: 7,
+		CreatedAt:     now,
+	}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, entry domain.WaitlistEntry, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id).
+					WillReturnRows(waitlistRow(promoted))
+			},
+			checkResult: func(t *testing.T, entry domain.WaitlistEntry, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, promoted, entry)
+			},
+		},
+		{
+			name: "NOT OK not found",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&id).
+					WillReturnRows(pgxmock.NewRows(waitlistColumns)) // note
+			},
+			checkResult: func(t *testing.T, entry domain.WaitlistEntry, err error) {
+				assert.ErrorIs(t, err, domain.ErrWaitlistEntryNotFound)
+				assert.Empty(t, entry)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			entry, err := repo.GetByID(context.Background(), id)
+			tc.checkResult(t, entry, err)
+		})
+	}
+}
+
+func Test_FindWaiting(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewWaitlist(mock)
+
+	targetQuery := "from reservation_waitlist\\s+where room_id = \\$1 and status = 'waiting' and start_time < \\$3 and end_time > \\$2\\s+order by created_at, id"
+
+	unexpectedError := errors.New("unexpected error")
+
+	now := time.Now().Truncate(time.Second).UTC()
+
+	roomID := domain.RoomID("conf-a")
+	window := domain.TimeRange{Start: now, End: now.Add(time.Hour)}
+
+	first := domain.WaitlistEntry{ID: 1, RoomID: roomID, TimeRange: window, Status: domain.WaitlistWaiting, CreatedAt: now}
+	second := domain.WaitlistEntry{ID: 2, RoomID: roomID, TimeRange: window, Status: domain.WaitlistWaiting, CreatedAt: now.Add(time.Minute)}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, entries []domain.WaitlistEntry, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &window.Start, &window.End).
+					WillReturnRows(waitlistRow(first).
+						AddRow(second.ID, second.RoomID, second.TimeRange.Start, second.TimeRange.End, second.Status, second.Details.Owner, second.Details.Title, second.Details.Description, second.Details.Attendees, second.ReservationID, second.CreatedAt))
+			},
+			checkResult: func(t *testing.T, entries []domain.WaitlistEntry, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.WaitlistEntry{first, second}, entries)
+			},
+		},
+		{
+			name: "NOT OK error unexpected",
+			buildStubs: func() {
+				mock.ExpectQuery(targetQuery).
+					WithArgs(&roomID, &window.Start, &window.End).
+					WillReturnError(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, entries []domain.WaitlistEntry, err error) {
+				assert.ErrorIs(t, err, unexpectedError)
+				assert.Empty(t, entries)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			entries, err := repo.FindWaiting(context.Background(), roomID, window)
+			tc.checkResult(t, entries, err)
+		})
+	}
+}
+
+func Test_PromoteWaitlistEntry(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+
+	// closing after check all expectations were met
+	defer mock.Close()
+	defer assert.NoError(t, mock.ExpectationsWereMet())
+
+	repo := NewWaitlist(mock)
+
+	targetQuery := "update reservation_waitlist set status = 'promoted', reservation_id = \\$2\\s+where id = \\$1 and status = 'waiting'"
+
+	id := int64(1)
+	reservationID := int64(7)
+
+	testCases := []struct {
+		name        string
+		buildStubs  func()
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&id, &reservationID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "NOT OK already promoted",
+			buildStubs: func() {
+				mock.ExpectExec(targetQuery).
+					WithArgs(&id, &reservationID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0)) // note
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, domain.ErrWaitlistEntryNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+			err := repo.Promote(context.Background(), id, reservationID)
+			tc.checkResult(t, err)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package transport
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,17 +36,374 @@ func (t *ReservationTime) UnmarshalJSON(data []byte) error {
 }
 
 type reservation struct {
-	ID        int64           `json:"id"`
-	RoomID    string          `json:"room_id"`
-	StartTime ReservationTime `json:"start_time"`
-	EndTime   ReservationTime `json:"end_time"`
+	ID        int64            `json:"id"`
+	RoomID    string           `json:"room_id"`
+	StartTime ReservationTime  `json:"start_time"`
+	EndTime   ReservationTime  `json:"end_time"`
+	Version   int64            `json:"version"`
+	Status    string           `json:"status"`
+	ExpiresAt *ReservationTime `json:"expires_at,omitempty"`
+
+	Owner       string   `json:"owner,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Attendees   []string `json:"attendees,omitempty"`
 }
 
 func newResevation(r domain.Reservation) reservation {
-	return reservation{
+	out := reservation{
 		ID:        r.ID,
 		RoomID:    string(r.RoomID),
 		StartTime: ReservationTime{r.TimeRange.Start},
 		EndTime:   ReservationTime{r.TimeRange.End},
+		Version:   r.Version,
+		Status:    string(r.Status),
+
+		Owner:       r.Details.Owner,
+		Title:       r.Details.Title,
+		Description: r.Details.Description,
+		Attendees:   r.Details.Attendees,
+	}
+	if r.ExpiresAt != nil {
+		out.ExpiresAt = &ReservationTime{*r.ExpiresAt}
+	}
+	return out
+}
+
+type waitlistEntry struct {
+	ID        int64           `json:"id"`
+	RoomID    string          `json:"room_id"`
+	StartTime ReservationTime `json:"start_time"`
+	EndTime   ReservationTime `json:"end_time"`
+	Status    string          `json:"status"`
+
+	Owner       string   `json:"owner,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Attendees   []string `json:"attendees,omitempty"`
+
+	// ReservationID бронирование, в которое превратился запрос, только у promoted
+	ReservationID int64           `json:"reservation_id,omitempty"`
+	CreatedAt     ReservationTime `json:"created_at"`
+}
+
+func newWaitlistEntry(e domain.WaitlistEntry) waitlistEntry {
+	return waitlistEntry{
+		ID:            e.ID,
+		RoomID:        string(e.RoomID),
+		StartTime:     ReservationTime{e.TimeRange.Start},
+		EndTime:       ReservationTime{e.TimeRange.End},
+		Status:        string(e.Status),
+		Owner:         e.Details.Owner,
+		Title:         e.Details.Title,
+		Description:   e.Details.Description,
+		Attendees:     e.Details.Attendees,
+		ReservationID: e.ReservationID,
+		CreatedAt:     ReservationTime{e.CreatedAt},
+	}
+}
+
+type timeRange struct {
+	StartTime ReservationTime `json:"start_time"`
+	EndTime   ReservationTime `json:"end_time"`
+}
+
+func newTimeRange(tr domain.TimeRange) timeRange {
+	return timeRange{
+		StartTime: ReservationTime{tr.Start},
+		EndTime:   ReservationTime{tr.End},
+	}
+}
+
+type reservationSeries struct {
+	ID           int64         `json:"id"`
+	RoomID       string        `json:"room_id"`
+	RRule        string        `json:"rrule"`
+	Reservations []reservation `json:"reservations"`
+}
+
+func newReservationSeries(s domain.ReservationSeries) reservationSeries {
+	out := reservationSeries{
+		ID:           s.ID,
+		RoomID:       string(s.RoomID),
+		RRule:        s.Rule,
+		Reservations: make([]reservation, 0, len(s.Reservations)),
+	}
+	for _, r := range s.Reservations {
+		out.Reservations = append(out.Reservations, newResevation(r))
+	}
+	return out
+}
+
+type occurrenceConflict struct {
+	StartTime         ReservationTime `json:"start_time"`
+	EndTime           ReservationTime `json:"end_time"`
+	ConflictStartTime ReservationTime `json:"conflict_start_time"`
+	ConflictEndTime   ReservationTime `json:"conflict_end_time"`
+	Reason            string          `json:"reason"`
+}
+
+type policyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type policyViolations struct {
+	Err        string            `json:"error"`
+	Violations []policyViolation `json:"violations"`
+}
+
+func newPolicyViolations(e domain.PolicyViolationError) policyViolations {
+	out := policyViolations{
+		Err:        e.Error(),
+		Violations: make([]policyViolation, 0, len(e.Violations)),
+	}
+	for _, v := range e.Violations {
+		out.Violations = append(out.Violations, policyViolation{
+			Rule:    v.Rule(),
+			Message: v.Error(),
+		})
+	}
+	return out
+}
+
+type seriesConflict struct {
+	Err       string               `json:"error"`
+	Conflicts []occurrenceConflict `json:"conflicts"`
+}
+
+func newSeriesConflict(e domain.SeriesConflictError) seriesConflict {
+	out := seriesConflict{
+		Err:       e.Error(),
+		Conflicts: make([]occurrenceConflict, 0, len(e.Conflicts)),
+	}
+	for _, c := range e.Conflicts {
+		out.Conflicts = append(out.Conflicts, occurrenceConflict{
+			StartTime:         ReservationTime{c.Reservation.Start},
+			EndTime:           ReservationTime{c.Reservation.End},
+			ConflictStartTime: ReservationTime{c.ConflictReservation.Start},
+			ConflictEndTime:   ReservationTime{c.ConflictReservation.End},
+			Reason:            c.Reason.String(),
+		})
+	}
+	return out
+}
+
+type roomConflict struct {
+	RoomID            string          `json:"room_id"`
+	StartTime         ReservationTime `json:"start_time"`
+	EndTime           ReservationTime `json:"end_time"`
+	ConflictStartTime ReservationTime `json:"conflict_start_time"`
+	ConflictEndTime   ReservationTime `json:"conflict_end_time"`
+	Reason            string          `json:"reason"`
+}
+
+type batchConflict struct {
+	Err       string         `json:"error"`
+	Conflicts []roomConflict `json:"conflicts"`
+}
+
+func newBatchConflict(e domain.BatchConflictError) batchConflict {
+	out := batchConflict{
+		Err:       e.Error(),
+		Conflicts: make([]roomConflict, 0, len(e.Conflicts)),
+	}
+	for _, c := range e.Conflicts {
+		out.Conflicts = append(out.Conflicts, roomConflict{
+			RoomID:            string(c.RoomID),
+			StartTime:         ReservationTime{c.Conflict.Reservation.Start},
+			EndTime:           ReservationTime{c.Conflict.Reservation.End},
+			ConflictStartTime: ReservationTime{c.Conflict.ConflictReservation.Start},
+			ConflictEndTime:   ReservationTime{c.Conflict.ConflictReservation.End},
+			Reason:            c.Conflict.Reason.String(),
+		})
+	}
+	return out
+}
+
+type room struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	Capacity            int    `json:"capacity"`
+	Location            string `json:"location"`
+	Active              bool   `json:"active"`
+	BufferBeforeMinutes int    `json:"buffer_before_minutes"`
+	BufferAfterMinutes  int    `json:"buffer_after_minutes"`
+}
+
+func newRoom(r domain.Room) room {
+	return room{
+		ID:                  string(r.ID),
+		Name:                r.Name,
+		Capacity:            r.Capacity,
+		Location:            r.Location,
+		Active:              r.Active,
+		BufferBeforeMinutes: int(r.Buffer.Before / time.Minute),
+		BufferAfterMinutes:  int(r.Buffer.After / time.Minute),
+	}
+}
+
+// буфер комнаты в API задается в минутах
+func minutes(m int) time.Duration {
+	return time.Duration(m) * time.Minute
+}
+
+var (
+	errIfMatchRequired = errors.New("If-Match header with reservation ETag is required")
+	errInvalidIfMatch  = errors.New("If-Match should contain a single strong ETag")
+)
+
+// ETag бронирования - его версия, сильный валидатор
+func formatETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseETag принимает только один сильный ETag: слабые по RFC 9110
+// не участвуют в If-Match, а "*" не защищает от потерянного обновления
+func parseETag(etag string) (int64, error) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// курсор непрозрачен для клиента: base64 от "unix_seconds:id"
+func encodeCursor(c domain.ReservationCursor) string {
+	raw := fmt.Sprintf("%d:%d", c.StartTime.Unix(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*domain.ReservationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	startStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &domain.ReservationCursor{
+		StartTime: time.Unix(start, 0).UTC(),
+		ID:        id,
+	}, nil
+}
+
+type openingHours struct {
+	Weekday string `json:"weekday"`
+	Open    string `json:"open"`
+	Close   string `json:"close"`
+}
+
+type roomSchedule struct {
+	RoomID   string         `json:"room_id"`
+	TimeZone string         `json:"time_zone"`
+	Hours    []openingHours `json:"hours"`
+}
+
+func newRoomSchedule(s domain.RoomSchedule) roomSchedule {
+	out := roomSchedule{
+		RoomID:   string(s.RoomID),
+		TimeZone: s.TimeZone(),
+		Hours:    make([]openingHours, 0, len(s.Hours)),
+	}
+	for _, h := range s.Hours {
+		out.Hours = append(out.Hours, openingHours{
+			Weekday: strings.ToLower(h.Weekday.String()),
+			Open:    formatClock(h.Open),
+			Close:   formatClock(h.Close),
+		})
+	}
+	return out
+}
+
+var errInvalidOpeningHours = errors.New("opening hours should have weekday name and open, close as HH:MM")
+
+func parseOpeningHours(hours []openingHours) ([]domain.OpeningHours, error) {
+	out := make([]domain.OpeningHours, 0, len(hours))
+	for _, h := range hours {
+		weekday, ok := parseWeekday(h.Weekday)
+		if !ok {
+			return nil, errInvalidOpeningHours
+		}
+		open, ok := parseClock(h.Open)
+		if !ok {
+			return nil, errInvalidOpeningHours
+		}
+		close, ok := parseClock(h.Close)
+		if !ok {
+			return nil, errInvalidOpeningHours
+		}
+		out = append(out, domain.OpeningHours{
+			Weekday: weekday,
+			Open:    open,
+			Close:   close,
+		})
+	}
+	return out, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// время дня в формате HH:MM, "24:00" допустимо как конец дня
+func parseClock(s string) (time.Duration, bool) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok || len(hh) != 2 || len(mm) != 2 {
+		return 0, false
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil || h < 0 || h > 24 {
+		return 0, false
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, false
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, true
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+type blackout struct {
+	ID        int64           `json:"id"`
+	RoomID    string          `json:"room_id"`
+	StartTime ReservationTime `json:"start_time"`
+	EndTime   ReservationTime `json:"end_time"`
+	Reason    string          `json:"reason"`
+}
+
+func newBlackout(b domain.Blackout) blackout {
+	return blackout{
+		ID:        b.ID,
+		RoomID:    string(b.RoomID),
+		StartTime: ReservationTime{b.TimeRange.Start},
+		EndTime:   ReservationTime{b.TimeRange.End},
+		Reason:    b.Reason,
 	}
 }
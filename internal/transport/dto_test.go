@@ -1,11 +1,13 @@
 package transport
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
 )
 
 type testReservationTime struct {
@@ -43,3 +45,90 @@ func Test_ReservationTime(t *testing.T) {
 	assert.Error(t, err)
 
 }
+
+func Test_Cursor(t *testing.T) {
+	now := time.Now().Truncate(time.Second).UTC()
+
+	cursor := domain.ReservationCursor{
+		StartTime: now,
+		ID:        42,
+	}
+
+	decoded, err := decodeCursor(encodeCursor(cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, &cursor, decoded)
+
+	for _, invalid := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("no-separator")),
+		base64.RawURLEncoding.EncodeToString([]byte("abc:1")),
+		base64.RawURLEncoding.EncodeToString([]byte("1:abc")),
+	} {
+		decoded, err := decodeCursor(invalid)
+		assert.ErrorIs(t, err, errInvalidCursor)
+		assert.Nil(t, decoded)
+	}
+}
+
+func Test_ETag(t *testing.T) {
+	etag := formatETag(42)
+	assert.Equal(t, `"42"`, etag)
+
+	version, err := parseETag(etag)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), version)
+
+	version, err = parseETag(` "7" `)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), version)
+
+	for _, invalid := range []string{
+		"42",
+		`W/"42"`,
+		"*",
+		`"abc"`,
+		`"0"`,
+		`"-1"`,
+		`"1", "2"`,
+		`"`,
+	} {
+		version, err := parseETag(invalid)
+		assert.ErrorIs(t, err, errInvalidIfMatch, invalid)
+		assert.Zero(t, version)
+	}
+}
+
+func Test_OpeningHours(t *testing.T) {
+	hours, err := parseOpeningHours([]openingHours{
+		{Weekday: "monday", Open: "09:00", Close: "18:30"},
+		{Weekday: "Sunday", Open: "00:00", Close: "24:00"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.OpeningHours{
+		{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18*time.Hour + 30*time.Minute},
+		{Weekday: time.Sunday, Open: 0, Close: 24 * time.Hour},
+	}, hours)
+
+	schedule := newRoomSchedule(domain.RoomSchedule{RoomID: "conf-a", Hours: hours})
+	assert.Equal(t, roomSchedule{
+		RoomID:   "conf-a",
+		TimeZone: domain.DefaultTimeZone,
+		Hours: []openingHours{
+			{Weekday: "monday", Open: "09:00", Close: "18:30"},
+			{Weekday: "sunday", Open: "00:00", Close: "24:00"},
+		},
+	}, schedule)
+
+	for _, invalid := range []openingHours{
+		{Weekday: "mon", Open: "09:00", Close: "18:00"},
+		{Weekday: "monday", Open: "9:00", Close: "18:00"},
+		{Weekday: "monday", Open: "09:00", Close: "24:30"},
+		{Weekday: "monday", Open: "09:60", Close: "18:00"},
+		{Weekday: "monday", Open: "09-00", Close: "18:00"},
+		{Weekday: "monday", Open: "09:00", Close: ""},
+	} {
+		hours, err := parseOpeningHours([]openingHours{invalid})
+		assert.ErrorIs(t, err, errInvalidOpeningHours, invalid)
+		assert.Nil(t, hours)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,8 +15,18 @@ import (
 )
 
 type ReservationService interface {
-	ListByRoom(ctx context.Context, roomID string) ([]domain.Reservation, error)
-	ReserveRoom(ctx context.Context, roomID string, from time.Time, to time.Time) (err error)
+	ListByRoom(ctx context.Context, roomID string, filter domain.ReservationFilter) ([]domain.Reservation, error)
+	ReserveRoom(ctx context.Context, roomID string, from time.Time, to time.Time, details domain.ReservationDetails) (domain.Reservation, error)
+	HoldRoom(ctx context.Context, roomID string, from time.Time, to time.Time, details domain.ReservationDetails, ttl time.Duration) (domain.Reservation, error)
+	WaitlistRoom(ctx context.Context, roomID string, from time.Time, to time.Time, details domain.ReservationDetails) (domain.Reservation, domain.WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, id int64) (domain.WaitlistEntry, error)
+	ReserveRecurring(ctx context.Context, roomID string, from time.Time, to time.Time, details domain.ReservationDetails, rule string) (domain.ReservationSeries, error)
+	ReserveBatch(ctx context.Context, requests []domain.BookingRequest) ([]domain.Reservation, error)
+	GetReservation(ctx context.Context, id int64) (domain.Reservation, error)
+	RescheduleReservation(ctx context.Context, id int64, from time.Time, to time.Time, version int64) (domain.Reservation, error)
+	ConfirmReservation(ctx context.Context, id int64, version int64) (domain.Reservation, error)
+	CancelReservation(ctx context.Context, id int64, version int64) error
+	RoomAvailability(ctx context.Context, roomID string, from time.Time, to time.Time, duration time.Duration) ([]domain.TimeRange, error)
 }
 
 type reservationController struct {
@@ -31,8 +43,27 @@ type createReservationRequest struct {
 	RoomID    string          `json:"room_id"`
 	StartTime ReservationTime `json:"start_time"`
 	EndTime   ReservationTime `json:"end_time"`
+	// Owner кто бронирует, по нему считается квота бронирований
+	Owner       string   `json:"owner,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Attendees   []string `json:"attendees,omitempty"`
+
+	// RRule подмножество RFC 5545, start_time/end_time задают первое вхождение
+	RRule string `json:"rrule,omitempty"`
+
+	// HoldMinutes создает временную бронь, которую нужно подтвердить через /confirm
+	HoldMinutes int `json:"hold_minutes,omitempty"`
+
+	// Waitlist при конфликте ставит запрос в лист ожидания вместо 409
+	Waitlist bool `json:"waitlist,omitempty"`
 }
 
+var (
+	errHoldSeries       = errors.New("hold_minutes can't be used with rrule")
+	errWaitlistCombined = errors.New("waitlist can't be used with rrule or hold_minutes")
+)
+
 func (h reservationController) CreateReservation(w http.ResponseWriter, r *http.Request) {
 	var req createReservationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -43,28 +74,211 @@ func (h reservationController) CreateReservation(w http.ResponseWriter, r *http.
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	err := h.service.ReserveRoom(ctx, req.RoomID, req.StartTime.Time, req.EndTime.Time)
+	details := domain.ReservationDetails{
+		Owner:       req.Owner,
+		Title:       req.Title,
+		Description: req.Description,
+		Attendees:   req.Attendees,
+	}
+	if req.Waitlist && (req.RRule != "" || req.HoldMinutes != 0) {
+		writeError(w, http.StatusBadRequest, errWaitlistCombined)
+		return
+	}
+	if req.RRule != "" {
+		if req.HoldMinutes != 0 {
+			writeError(w, http.StatusBadRequest, errHoldSeries)
+			return
+		}
+		h.createSeries(ctx, w, req, details)
+		return
+	}
+
+	var (
+		reservation domain.Reservation
+		entry       domain.WaitlistEntry
+		err         error
+	)
+	switch {
+	case req.HoldMinutes != 0:
+		reservation, err = h.service.HoldRoom(ctx, req.RoomID, req.StartTime.Time, req.EndTime.Time, details, minutes(req.HoldMinutes))
+	case req.Waitlist:
+		reservation, entry, err = h.service.WaitlistRoom(ctx, req.RoomID, req.StartTime.Time, req.EndTime.Time, details)
+	default:
+		reservation, err = h.service.ReserveRoom(ctx, req.RoomID, req.StartTime.Time, req.EndTime.Time, details)
+	}
+
+	var violation domain.PolicyViolationError
 	if errors.Is(err, internal.ErrValidationFailed) {
 		writeError(w, http.StatusBadRequest, err)
 		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomInactive) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.Is(err, &domain.CapacityExceededError{}) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.Is(err, &domain.OutsideBookableHoursError{}) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.As(err, &violation) {
+		writePolicyViolation(w, violation)
+		return
 	} else if errors.Is(err, &domain.ReservationConflictError{}) {
 		writeError(w, http.StatusConflict, err)
 		return
+	} else if errors.Is(err, internal.ErrLockTimeout) {
+		writeLockTimeout(w, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// слот занят, запрос ждет в очереди и станет бронированием после отмены
+	if entry.ID > 0 {
+		w.Header().Set("Location", fmt.Sprintf("/api/v1/waitlist/%d", entry.ID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		write(w, http.StatusAccepted, newWaitlistEntry(entry))
+		return
+	}
+
+	writeCreated(w, fmt.Sprintf("/api/v1/reservations/id/%d", reservation.ID), newResevation(reservation))
+}
+
+func (h reservationController) createSeries(ctx context.Context, w http.ResponseWriter, req createReservationRequest, details domain.ReservationDetails) {
+	series, err := h.service.ReserveRecurring(ctx, req.RoomID, req.StartTime.Time, req.EndTime.Time, details, req.RRule)
+
+	var (
+		conflict  domain.SeriesConflictError
+		violation domain.PolicyViolationError
+	)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomInactive) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.Is(err, &domain.CapacityExceededError{}) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.Is(err, &domain.OutsideBookableHoursError{}) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.As(err, &violation) {
+		writePolicyViolation(w, violation)
+		return
+	} else if errors.As(err, &conflict) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		write(w, http.StatusConflict, newSeriesConflict(conflict))
+		return
+	} else if errors.Is(err, internal.ErrLockTimeout) {
+		writeLockTimeout(w, err)
+		return
 	} else if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	write(w, http.StatusCreated, nil)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	write(w, http.StatusCreated, newReservationSeries(series))
+}
+
+type batchReservationRequest struct {
+	RoomID    string          `json:"room_id"`
+	StartTime ReservationTime `json:"start_time"`
+	EndTime   ReservationTime `json:"end_time"`
+}
+
+type createReservationBatchRequest struct {
+	Reservations []batchReservationRequest `json:"reservations"`
+}
+
+// CreateReservationBatch бронирует все комнаты из запроса или ни одной
+func (h reservationController) CreateReservationBatch(w http.ResponseWriter, r *http.Request) {
+	var req createReservationBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	requests := make([]domain.BookingRequest, 0, len(req.Reservations))
+	for _, r := range req.Reservations {
+		requests = append(requests, domain.BookingRequest{
+			RoomID: r.RoomID,
+			From:   r.StartTime.Time,
+			To:     r.EndTime.Time,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	reservations, err := h.service.ReserveBatch(ctx, requests)
+
+	var (
+		conflict  domain.BatchConflictError
+		violation domain.PolicyViolationError
+	)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomInactive) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.Is(err, &domain.OutsideBookableHoursError{}) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.As(err, &violation) {
+		writePolicyViolation(w, violation)
+		return
+	} else if errors.As(err, &conflict) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		write(w, http.StatusConflict, newBatchConflict(conflict))
+		return
+	} else if errors.Is(err, internal.ErrLockTimeout) {
+		writeLockTimeout(w, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]reservation, 0, len(reservations))
+	for _, r := range reservations {
+		out = append(out, newResevation(r))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	write(w, http.StatusCreated, out)
 }
 
 func (h reservationController) ListByRoom(w http.ResponseWriter, r *http.Request) {
 	roomID := chi.URLParam(r, "room_id")
 
+	filter, err := parseReservationFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	reservations, err := h.service.ListByRoom(ctx, roomID)
+	reservations, err := h.service.ListByRoom(ctx, roomID, filter)
 	if errors.Is(err, internal.ErrValidationFailed) {
 		writeError(w, http.StatusBadRequest, err)
 		return
@@ -78,6 +292,290 @@ func (h reservationController) ListByRoom(w http.ResponseWriter, r *http.Request
 		out = append(out, newResevation(r))
 	}
 
+	// полная страница - возможно есть следующая
+	limit := filter.Limit
+	if limit == 0 {
+		limit = domain.DefaultReservationsLimit
+	}
+	if len(reservations) > 0 && len(reservations) == limit {
+		last := reservations[len(reservations)-1]
+		w.Header().Set(nextCursorHeader, encodeCursor(domain.ReservationCursor{
+			StartTime: last.TimeRange.Start,
+			ID:        last.ID,
+		}))
+	}
+
+	write(w, http.StatusOK, out)
+}
+
+const nextCursorHeader = "X-Next-Cursor"
+
+func parseReservationFilter(r *http.Request) (filter domain.ReservationFilter, err error) {
+	params := r.URL.Query()
+
+	if from := params.Get("from"); from != "" {
+		if filter.From, err = time.Parse(ReservationTimeLayout, from); err != nil {
+			return domain.ReservationFilter{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if to := params.Get("to"); to != "" {
+		if filter.To, err = time.Parse(ReservationTimeLayout, to); err != nil {
+			return domain.ReservationFilter{}, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if cursor := params.Get("cursor"); cursor != "" {
+		if filter.After, err = decodeCursor(cursor); err != nil {
+			return domain.ReservationFilter{}, err
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return domain.ReservationFilter{}, fmt.Errorf("invalid limit: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+func (h reservationController) GetReservation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	reservation, err := h.service.GetReservation(ctx, id)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(reservation.Version))
+	write(w, http.StatusOK, newResevation(reservation))
+}
+
+// GetWaitlistEntry состояние запроса из листа ожидания, после продвижения в нем reservation_id
+func (h reservationController) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	entry, err := h.service.GetWaitlistEntry(ctx, id)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrWaitlistEntryNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusOK, newWaitlistEntry(entry))
+}
+
+type rescheduleReservationRequest struct {
+	StartTime ReservationTime `json:"start_time"`
+	EndTime   ReservationTime `json:"end_time"`
+}
+
+func (h reservationController) RescheduleReservation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	var req rescheduleReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	reservation, err := h.service.RescheduleReservation(ctx, id, req.StartTime.Time, req.EndTime.Time, version)
+
+	var violation domain.PolicyViolationError
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationVersionMismatch) {
+		writeError(w, http.StatusPreconditionFailed, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomInactive) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.Is(err, &domain.OutsideBookableHoursError{}) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if errors.As(err, &violation) {
+		writePolicyViolation(w, violation)
+		return
+	} else if errors.Is(err, &domain.ReservationConflictError{}) {
+		writeError(w, http.StatusConflict, err)
+		return
+	} else if errors.Is(err, internal.ErrLockTimeout) {
+		writeLockTimeout(w, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(reservation.Version))
+	write(w, http.StatusOK, newResevation(reservation))
+}
+
+func (h reservationController) ConfirmReservation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	reservation, err := h.service.ConfirmReservation(ctx, id, version)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationVersionMismatch) {
+		writeError(w, http.StatusPreconditionFailed, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationNotHeld) {
+		writeError(w, http.StatusConflict, err)
+		return
+	} else if errors.Is(err, domain.ErrHoldExpired) {
+		writeError(w, http.StatusGone, err)
+		return
+	} else if errors.Is(err, internal.ErrLockTimeout) {
+		writeLockTimeout(w, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(reservation.Version))
+	write(w, http.StatusOK, newResevation(reservation))
+}
+
+func (h reservationController) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.service.CancelReservation(ctx, id, version)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrReservationVersionMismatch) {
+		writeError(w, http.StatusPreconditionFailed, err)
+		return
+	} else if errors.Is(err, internal.ErrLockTimeout) {
+		writeLockTimeout(w, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusNoContent, nil)
+}
+
+func (h reservationController) RoomAvailability(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+	params := r.URL.Query()
+
+	from, err := time.Parse(ReservationTimeLayout, params.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid from: %w", err))
+		return
+	}
+	to, err := time.Parse(ReservationTimeLayout, params.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid to: %w", err))
+		return
+	}
+	// без duration отдаются все свободные интервалы
+	var duration time.Duration
+	if d := params.Get("duration"); d != "" {
+		if duration, err = time.ParseDuration(d); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration: %w", err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	free, err := h.service.RoomAvailability(ctx, roomID, from, to, duration)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomInactive) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]timeRange, 0, len(free))
+	for _, tr := range free {
+		out = append(out, newTimeRange(tr))
+	}
+
 	write(w, http.StatusOK, out)
 }
 
@@ -101,6 +599,43 @@ func write(w http.ResponseWriter, status int, msg any) {
 	}
 }
 
+func writeCreated(w http.ResponseWriter, location string, msg any) {
+	w.Header().Set("Location", location)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	write(w, http.StatusCreated, msg)
+}
+
+// requireIfMatch версия из If-Match, без заголовка изменение отклоняется с 428
+func requireIfMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		writeError(w, http.StatusPreconditionRequired, errIfMatchRequired)
+		return 0, false
+	}
+	version, err := parseETag(ifMatch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return 0, false
+	}
+	return version, true
+}
+
+// retryAfterSeconds подсказка клиенту, когда повторить запрос после 503
+const retryAfterSeconds = "1"
+
+// writePolicyViolation 422 со всеми нарушенными правилами бронирования
+func writePolicyViolation(w http.ResponseWriter, e domain.PolicyViolationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	write(w, http.StatusUnprocessableEntity, newPolicyViolations(e))
+}
+
+func writeLockTimeout(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", retryAfterSeconds)
+	writeError(w, http.StatusServiceUnavailable, err)
+}
+
 type jsonError struct {
 	Err string `json:"error"`
 }
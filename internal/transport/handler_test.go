@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -22,7 +23,7 @@ func Test_CreateReservation(t *testing.T) {
 	defer ctrl.Finish()
 
 	service := mock_transport.NewMockReservationService(ctrl)
-	router := NewRouter(service)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
 
 	from := time.Now().Truncate(time.Second).UTC()
 	to := from.Add(1 * time.Minute)
@@ -35,6 +36,33 @@ func Test_CreateReservation(t *testing.T) {
 		EndTime:   ReservationTime{to},
 	}
 
+	defaultReservation := domain.Reservation{
+		ID:        1,
+		RoomID:    domain.RoomID(defaultInput.RoomID),
+		TimeRange: domain.TimeRange{Start: from, End: to},
+	}
+
+	expiresAt := from.Add(15 * time.Minute)
+	heldReservation := defaultReservation
+	heldReservation.Status = domain.StatusHeld
+	heldReservation.ExpiresAt = &expiresAt
+
+	ownedReservation := defaultReservation
+	ownedReservation.Details = domain.ReservationDetails{
+		Owner:       "alice",
+		Title:       "standup",
+		Description: "daily sync",
+		Attendees:   []string{"bob", "carol"},
+	}
+
+	waiting := domain.WaitlistEntry{
+		ID:        3,
+		RoomID:    defaultReservation.RoomID,
+		TimeRange: defaultReservation.TimeRange,
+		Status:    domain.WaitlistWaiting,
+		CreatedAt: from,
+	}
+
 	testCases := []struct {
 		name  string
 		input *createReservationRequest
@@ -51,17 +79,136 @@ func Test_CreateReservation(t *testing.T) {
 					gomock.Eq(defaultInput.RoomID),
 					gomock.Eq(defaultInput.StartTime.Time),
 					gomock.Eq(defaultInput.EndTime.Time),
-				).Times(1).Return(nil)
+					gomock.Eq(domain.ReservationDetails{}),
+				).Times(1).Return(defaultReservation, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "/api/v1/reservations/id/1", r.Header().Get("Location"))
+				assert.Equal(t, "application/json", r.Header().Get("Content-Type"))
+
+				var out reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newResevation(defaultReservation), out)
+			},
+		},
+		{
+			name: "OK hold",
+			input: &createReservationRequest{
+				RoomID:      defaultInput.RoomID,
+				StartTime:   defaultInput.StartTime,
+				EndTime:     defaultInput.EndTime,
+				HoldMinutes: 15, // note
+			},
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().HoldRoom(
+					gomock.Any(),
+					gomock.Eq(defaultInput.RoomID),
+					gomock.Eq(defaultInput.StartTime.Time),
+					gomock.Eq(defaultInput.EndTime.Time),
+					gomock.Eq(domain.ReservationDetails{}),
+					gomock.Eq(15*time.Minute),
+				).Times(1).Return(heldReservation, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "/api/v1/reservations/id/1", r.Header().Get("Location"))
+
+				var out reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, "held", out.Status)
+				if assert.NotNil(t, out.ExpiresAt) {
+					assert.Equal(t, expiresAt, out.ExpiresAt.Time)
+				}
+			},
+		},
+		{
+			name: "NOT OK hold with rrule",
+			input: &createReservationRequest{
+				RoomID:      defaultInput.RoomID,
+				StartTime:   defaultInput.StartTime,
+				EndTime:     defaultInput.EndTime,
+				RRule:       "FREQ=DAILY;COUNT=2", // note
+				HoldMinutes: 15,
+			},
+			buildStubs: func() {
+				service.EXPECT().HoldRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name: "OK waitlist slot is free",
+			input: &createReservationRequest{
+				RoomID:    defaultInput.RoomID,
+				StartTime: defaultInput.StartTime,
+				EndTime:   defaultInput.EndTime,
+				Waitlist:  true, // note
+			},
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().WaitlistRoom(
+					gomock.Any(),
+					gomock.Eq(defaultInput.RoomID),
+					gomock.Eq(defaultInput.StartTime.Time),
+					gomock.Eq(defaultInput.EndTime.Time),
+					gomock.Eq(domain.ReservationDetails{}),
+				).Times(1).Return(defaultReservation, domain.WaitlistEntry{}, nil)
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "/api/v1/reservations/id/1", r.Header().Get("Location"))
+			},
+		},
+		{
+			name: "OK waitlisted on conflict",
+			input: &createReservationRequest{
+				RoomID:    defaultInput.RoomID,
+				StartTime: defaultInput.StartTime,
+				EndTime:   defaultInput.EndTime,
+				Waitlist:  true,
+			},
+			buildStubs: func() {
+				service.EXPECT().WaitlistRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, waiting, nil) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusAccepted, r.Code)
+				assert.Equal(t, "/api/v1/waitlist/3", r.Header().Get("Location"))
+
+				var out waitlistEntry
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newWaitlistEntry(waiting), out)
+			},
+		},
+		{
+			name: "NOT OK waitlist with rrule",
+			input: &createReservationRequest{
+				RoomID:    defaultInput.RoomID,
+				StartTime: defaultInput.StartTime,
+				EndTime:   defaultInput.EndTime,
+				RRule:     "FREQ=DAILY;COUNT=2", // note
+				Waitlist:  true,
+			},
+			buildStubs: func() {
+				service.EXPECT().WaitlistRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
 			},
 		},
 		{
 			name:  "NOT OK nil body",
 			input: nil, // note
 			buildStubs: func() {
-				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, r.Code)
@@ -76,12 +223,109 @@ func Test_CreateReservation(t *testing.T) {
 					gomock.Eq(defaultInput.RoomID),
 					gomock.Eq(defaultInput.StartTime.Time),
 					gomock.Eq(defaultInput.EndTime.Time),
-				).Times(1).Return(internal.ErrValidationFailed) // note
+					gomock.Eq(domain.ReservationDetails{}),
+				).Times(1).Return(domain.Reservation{}, internal.ErrValidationFailed) // note
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, r.Code)
 			},
 		},
+		{
+			name:  "NOT OK error from ReserveRoom room not found",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRoom room inactive",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ErrRoomInactive) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRoom capacity exceeded",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.CapacityExceededError{RoomID: "1", Capacity: 2, Attendees: 3}) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRoom outside bookable hours",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.OutsideBookableHoursError{}) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name: "OK with details",
+			input: &createReservationRequest{
+				RoomID:      defaultInput.RoomID,
+				StartTime:   defaultInput.StartTime,
+				EndTime:     defaultInput.EndTime,
+				Owner:       "alice", // note
+				Title:       "standup",
+				Description: "daily sync",
+				Attendees:   []string{"bob", "carol"},
+			},
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(
+					gomock.Any(),
+					gomock.Eq(defaultInput.RoomID),
+					gomock.Eq(defaultInput.StartTime.Time),
+					gomock.Eq(defaultInput.EndTime.Time),
+					gomock.Eq(ownedReservation.Details),
+				).Times(1).Return(ownedReservation, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+
+				var out reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newResevation(ownedReservation), out)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRoom policy violation",
+			input: &defaultInput,
+			buildStubs: func() {
+				violation := domain.PolicyViolationError{Violations: []domain.PolicyViolation{
+					domain.MinDurationViolation{Min: 5 * time.Minute, Duration: time.Minute},
+					domain.OwnerQuotaViolation{Owner: "alice", Limit: 2, Active: 2},
+				}}
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, violation) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+				assert.Equal(t, "application/json", r.Header().Get("Content-Type"))
+
+				var out policyViolations
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Len(t, out.Violations, 2)
+				assert.Equal(t, "min_duration", out.Violations[0].Rule)
+				assert.Equal(t, "owner_quota", out.Violations[1].Rule)
+			},
+		},
 		{
 			name:  "NOT OK error from ReserveRoom reservation conflict",
 			input: &defaultInput,
@@ -91,12 +335,25 @@ func Test_CreateReservation(t *testing.T) {
 					gomock.Eq(defaultInput.RoomID),
 					gomock.Eq(defaultInput.StartTime.Time),
 					gomock.Eq(defaultInput.EndTime.Time),
-				).Times(1).Return(&domain.ReservationConflictError{}) // note
+					gomock.Eq(domain.ReservationDetails{}),
+				).Times(1).Return(domain.Reservation{}, &domain.ReservationConflictError{}) // note
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusConflict, r.Code)
 			},
 		},
+		{
+			name:  "NOT OK error from ReserveRoom lock timeout",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, internal.ErrLockTimeout) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+				assert.Equal(t, "1", r.Header().Get("Retry-After"))
+			},
+		},
 		{
 			name:  "NOT OK error from ReserveRoom unexpected",
 			input: &defaultInput,
@@ -106,7 +363,8 @@ func Test_CreateReservation(t *testing.T) {
 					gomock.Eq(defaultInput.RoomID),
 					gomock.Eq(defaultInput.StartTime.Time),
 					gomock.Eq(defaultInput.EndTime.Time),
-				).Times(1).Return(unexpectedError) // note
+					gomock.Eq(domain.ReservationDetails{}),
+				).Times(1).Return(domain.Reservation{}, unexpectedError) // note
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, r.Code)
@@ -135,91 +393,162 @@ func Test_CreateReservation(t *testing.T) {
 	}
 }
 
-func Test_ListByRoom(t *testing.T) {
+func Test_CreateReservationSeries(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	service := mock_transport.NewMockReservationService(ctrl)
-	router := NewRouter(service)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
 
 	from := time.Now().Truncate(time.Second).UTC()
-	to := from.Add(1 * time.Minute)
+	to := from.Add(1 * time.Hour)
 
-	defaultRoomID := "1"
+	unexpectedError := errors.New("unexpecte error")
 
-	var defaultReservations []domain.Reservation
-	defaultReservations = append(defaultReservations,
-		domain.Reservation{
-			ID:        1,
-			RoomID:    domain.RoomID(defaultRoomID),
-			TimeRange: domain.TimeRange{Start: from, End: to},
-		},
-		domain.Reservation{
-			ID:        2,
-			RoomID:    domain.RoomID(defaultRoomID),
-			TimeRange: domain.TimeRange{Start: to, End: to.Add(time.Minute)},
-		})
+	defaultInput := createReservationRequest{
+		RoomID:    "1",
+		StartTime: ReservationTime{from},
+		EndTime:   ReservationTime{to},
+		RRule:     "FREQ=WEEKLY;COUNT=2",
+		Owner:     "alice",
+		Title:     "standup",
+		Attendees: []string{"bob", "carol"},
+	}
 
-	unexpectedError := errors.New("unexpecte error")
+	second := domain.TimeRange{Start: from.AddDate(0, 0, 7), End: to.AddDate(0, 0, 7)}
+
+	defaultSeries := domain.ReservationSeries{
+		ID:     1,
+		RoomID: domain.RoomID(defaultInput.RoomID),
+		Rule:   defaultInput.RRule,
+		Reservations: []domain.Reservation{
+			{ID: 1, RoomID: domain.RoomID(defaultInput.RoomID), TimeRange: domain.TimeRange{Start: from, End: to}},
+			{ID: 2, RoomID: domain.RoomID(defaultInput.RoomID), TimeRange: second},
+		},
+	}
 
 	testCases := []struct {
-		name        string
-		roomIDParam string
+		name  string
+		input *createReservationRequest
 
 		buildStubs  func()
 		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
 	}{
 		{
-			name:        "OK",
-			roomIDParam: defaultRoomID,
+			name:  "OK",
+			input: &defaultInput,
 			buildStubs: func() {
-				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID)).Times(1).Return(defaultReservations, nil)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().ReserveRecurring(
+					gomock.Any(),
+					gomock.Eq(defaultInput.RoomID),
+					gomock.Eq(defaultInput.StartTime.Time),
+					gomock.Eq(defaultInput.EndTime.Time),
+					gomock.Eq(domain.ReservationDetails{
+						Owner:     defaultInput.Owner,
+						Title:     defaultInput.Title,
+						Attendees: defaultInput.Attendees,
+					}),
+					gomock.Eq(defaultInput.RRule),
+				).Times(1).Return(defaultSeries, nil)
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "application/json", r.Header().Get("Content-Type"))
 
-				var reservations []reservation
-				err := json.NewDecoder(r.Body).Decode(&reservations)
+				var out reservationSeries
+				err := json.NewDecoder(r.Body).Decode(&out)
 				assert.NoError(t, err)
-
-				for i := range reservations {
-					assert.Equal(t, defaultReservations[i].ID, reservations[i].ID)
-					assert.Equal(t, string(defaultReservations[i].RoomID), reservations[i].RoomID)
-					assert.Equal(t, defaultReservations[i].TimeRange.Start, reservations[i].StartTime.Time)
-					assert.Equal(t, defaultReservations[i].TimeRange.End, reservations[i].EndTime.Time)
-				}
+				assert.Equal(t, newReservationSeries(defaultSeries), out)
 			},
 		},
 		{
-			name:        "OK no data",
-			roomIDParam: defaultRoomID,
+			name:  "NOT OK error from ReserveRecurring validation failed",
+			input: &defaultInput,
 			buildStubs: func() {
-				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID)).Times(1).Return(nil, nil) // note
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.ReservationSeries{}, internal.ErrValidationFailed) // note
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRecurring capacity exceeded",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.ReservationSeries{}, domain.CapacityExceededError{RoomID: "1", Capacity: 1, Attendees: 2}) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRecurring room not found",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.ReservationSeries{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveRecurring series conflict",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.ReservationSeries{}, domain.SeriesConflictError{
+					Conflicts: []domain.ReservationConflictError{
+						{Reservation: second, ConflictReservation: second}, // note
+						{Reservation: second, ConflictReservation: second, Reason: domain.ConflictBuffer},
+					},
+				})
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
 
-				var reservations []reservation
-				err := json.NewDecoder(r.Body).Decode(&reservations)
+				var out seriesConflict
+				err := json.NewDecoder(r.Body).Decode(&out)
 				assert.NoError(t, err)
-				assert.Empty(t, reservations)
+				assert.Equal(t, []occurrenceConflict{
+					{
+						StartTime:         ReservationTime{second.Start},
+						EndTime:           ReservationTime{second.End},
+						ConflictStartTime: ReservationTime{second.Start},
+						ConflictEndTime:   ReservationTime{second.End},
+						Reason:            "booking",
+					},
+					{
+						StartTime:         ReservationTime{second.Start},
+						EndTime:           ReservationTime{second.End},
+						ConflictStartTime: ReservationTime{second.Start},
+						ConflictEndTime:   ReservationTime{second.End},
+						Reason:            "buffer",
+					},
+				}, out.Conflicts)
 			},
 		},
 		{
-			name:        "NOT OK error from ListByRoom validation failed",
-			roomIDParam: defaultRoomID,
+			name:  "NOT OK error from ReserveRecurring lock timeout",
+			input: &defaultInput,
 			buildStubs: func() {
-				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID)).Times(1).Return(nil, internal.ErrValidationFailed) // note
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.ReservationSeries{}, internal.ErrLockTimeout) // note
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusBadRequest, r.Code)
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+				assert.Equal(t, "1", r.Header().Get("Retry-After"))
 			},
 		},
 		{
-			name:        "NOT OK error from ListByRoom unexpected",
-			roomIDParam: defaultRoomID,
+			name:  "NOT OK error from ReserveRecurring unexpected",
+			input: &defaultInput,
 			buildStubs: func() {
-				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID)).Times(1).Return(nil, unexpectedError) // note
+				service.EXPECT().ReserveRecurring(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.ReservationSeries{}, unexpectedError) // note
 			},
 			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, r.Code)
@@ -227,12 +556,1194 @@ func Test_ListByRoom(t *testing.T) {
 		},
 	}
 
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			b, err := json.Marshal(tc.input)
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/reservations", bytes.NewBuffer(b))
+			r.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_CreateReservationBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(2 * time.Hour)
+	tr := domain.TimeRange{Start: from, End: to}
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultInput := createReservationBatchRequest{
+		Reservations: []batchReservationRequest{
+			{RoomID: "hall", StartTime: ReservationTime{from}, EndTime: ReservationTime{to}},
+			{RoomID: "overflow", StartTime: ReservationTime{from}, EndTime: ReservationTime{to}},
+		},
+	}
+
+	defaultRequests := []domain.BookingRequest{
+		{RoomID: "hall", From: from, To: to},
+		{RoomID: "overflow", From: from, To: to},
+	}
+
+	defaultReservations := []domain.Reservation{
+		{ID: 1, RoomID: "hall", TimeRange: tr},
+		{ID: 2, RoomID: "overflow", TimeRange: tr},
+	}
+
+	testCases := []struct {
+		name  string
+		input *createReservationBatchRequest
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Eq(defaultRequests)).
+					Times(1).Return(defaultReservations, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "application/json", r.Header().Get("Content-Type"))
+
+				var out []reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, []reservation{newResevation(defaultReservations[0]), newResevation(defaultReservations[1])}, out)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch validation failed",
+			input: &createReservationBatchRequest{}, // note
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, internal.ErrValidationFailed)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch room not found",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch room inactive",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, domain.ErrRoomInactive) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch outside bookable hours",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, fmt.Errorf("room overflow: %w", domain.OutsideBookableHoursError{})) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch batch conflict",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, domain.BatchConflictError{
+					Conflicts: []domain.RoomConflict{
+						{RoomID: "overflow", Conflict: domain.ReservationConflictError{Reservation: tr, ConflictReservation: tr}}, // note
+					},
+				})
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+
+				var out batchConflict
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, []roomConflict{{
+					RoomID:            "overflow",
+					StartTime:         ReservationTime{from},
+					EndTime:           ReservationTime{to},
+					ConflictStartTime: ReservationTime{from},
+					ConflictEndTime:   ReservationTime{to},
+					Reason:            "booking",
+				}}, out.Conflicts)
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch lock timeout",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, internal.ErrLockTimeout) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+				assert.Equal(t, "1", r.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:  "NOT OK error from ReserveBatch unexpected",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			b, err := json.Marshal(tc.input)
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/reservations/batch", bytes.NewBuffer(b))
+			r.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+
+	t.Run("NOT OK invalid body", func(t *testing.T) {
+		service.EXPECT().ReserveBatch(gomock.Any(), gomock.Any()).Times(0)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/reservations/batch", bytes.NewBufferString("{"))
+
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func Test_ListByRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Minute)
+
+	defaultRoomID := "1"
+
+	var defaultReservations []domain.Reservation
+	defaultReservations = append(defaultReservations,
+		domain.Reservation{
+			ID:        1,
+			RoomID:    domain.RoomID(defaultRoomID),
+			TimeRange: domain.TimeRange{Start: from, End: to},
+			Details: domain.ReservationDetails{
+				Owner:       "alice",
+				Title:       "standup",
+				Description: "daily sync",
+				Attendees:   []string{"bob", "carol"},
+			},
+		},
+		domain.Reservation{
+			ID:        2,
+			RoomID:    domain.RoomID(defaultRoomID),
+			TimeRange: domain.TimeRange{Start: to, End: to.Add(time.Minute)},
+		})
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultFilter := domain.ReservationFilter{}
+
+	testCases := []struct {
+		name        string
+		roomIDParam string
+		queryParams string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			roomIDParam: defaultRoomID,
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID), gomock.Eq(defaultFilter)).Times(1).Return(defaultReservations, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var reservations []reservation
+				err := json.NewDecoder(r.Body).Decode(&reservations)
+				assert.NoError(t, err)
+
+				for i := range reservations {
+					assert.Equal(t, defaultReservations[i].ID, reservations[i].ID)
+					assert.Equal(t, string(defaultReservations[i].RoomID), reservations[i].RoomID)
+					assert.Equal(t, defaultReservations[i].TimeRange.Start, reservations[i].StartTime.Time)
+					assert.Equal(t, defaultReservations[i].TimeRange.End, reservations[i].EndTime.Time)
+					assert.Equal(t, defaultReservations[i].Details.Owner, reservations[i].Owner)
+					assert.Equal(t, defaultReservations[i].Details.Title, reservations[i].Title)
+					assert.Equal(t, defaultReservations[i].Details.Description, reservations[i].Description)
+					assert.Equal(t, defaultReservations[i].Details.Attendees, reservations[i].Attendees)
+				}
+			},
+		},
+		{
+			name:        "OK no data",
+			roomIDParam: defaultRoomID,
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID), gomock.Eq(defaultFilter)).Times(1).Return(nil, nil) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var reservations []reservation
+				err := json.NewDecoder(r.Body).Decode(&reservations)
+				assert.NoError(t, err)
+				assert.Empty(t, reservations)
+			},
+		},
+		{
+			name:        "OK with filter and next cursor",
+			roomIDParam: defaultRoomID,
+			queryParams: fmt.Sprintf("?from=%s&to=%s&limit=2&cursor=%s",
+				url.QueryEscape(from.Format(ReservationTimeLayout)),
+				url.QueryEscape(to.Format(ReservationTimeLayout)),
+				encodeCursor(domain.ReservationCursor{StartTime: from, ID: 1}),
+			),
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID), gomock.Eq(domain.ReservationFilter{
+					From:  from,
+					To:    to,
+					After: &domain.ReservationCursor{StartTime: from, ID: 1},
+					Limit: 2,
+				})).Times(1).Return(defaultReservations, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				last := defaultReservations[len(defaultReservations)-1]
+				assert.Equal(t, encodeCursor(domain.ReservationCursor{
+					StartTime: last.TimeRange.Start,
+					ID:        last.ID,
+				}), r.Header().Get(nextCursorHeader))
+			},
+		},
+		{
+			name:        "OK last page without next cursor",
+			roomIDParam: defaultRoomID,
+			queryParams: "?limit=3",
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID), gomock.Eq(domain.ReservationFilter{Limit: 3})).
+					Times(1).Return(defaultReservations, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Empty(t, r.Header().Get(nextCursorHeader))
+			},
+		},
+		{
+			name:        "NOT OK invalid from",
+			roomIDParam: defaultRoomID,
+			queryParams: "?from=yesterday", // note
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK invalid limit",
+			roomIDParam: defaultRoomID,
+			queryParams: "?limit=ten", // note
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK invalid cursor",
+			roomIDParam: defaultRoomID,
+			queryParams: "?cursor=abc", // note
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from ListByRoom validation failed",
+			roomIDParam: defaultRoomID,
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID), gomock.Eq(defaultFilter)).Times(1).Return(nil, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from ListByRoom unexpected",
+			roomIDParam: defaultRoomID,
+			buildStubs: func() {
+				service.EXPECT().ListByRoom(gomock.Any(), gomock.Eq(defaultRoomID), gomock.Eq(defaultFilter)).Times(1).Return(nil, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/reservations/%s%s", tc.roomIDParam, tc.queryParams), nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_GetReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Minute)
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultReservation := domain.Reservation{
+		ID:        1,
+		RoomID:    "conf-a",
+		TimeRange: domain.TimeRange{Start: from, End: to},
+		Version:   3,
+	}
+
+	testCases := []struct {
+		name    string
+		idParam string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			idParam: "1",
+			buildStubs: func() {
+				service.EXPECT().GetReservation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).Return(defaultReservation, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"3"`, r.Header().Get("ETag"))
+
+				var out reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newResevation(defaultReservation), out)
+			},
+		},
+		{
+			name:    "NOT OK invalid id",
+			idParam: "abc", // note
+			buildStubs: func() {
+				service.EXPECT().GetReservation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from GetReservation validation failed",
+			idParam: "0",
+			buildStubs: func() {
+				service.EXPECT().GetReservation(gomock.Any(), gomock.Eq(int64(0))).
+					Times(1).Return(domain.Reservation{}, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from GetReservation not found",
+			idParam: "1",
+			buildStubs: func() {
+				service.EXPECT().GetReservation(gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ErrReservationNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+				assert.Empty(t, r.Header().Get("ETag"))
+			},
+		},
+		{
+			name:    "NOT OK error from GetReservation unexpected",
+			idParam: "1",
+			buildStubs: func() {
+				service.EXPECT().GetReservation(gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/reservations/id/"+tc.idParam, nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_RescheduleReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Minute)
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultInput := rescheduleReservationRequest{
+		StartTime: ReservationTime{from},
+		EndTime:   ReservationTime{to},
+	}
+
+	defaultReservation := domain.Reservation{
+		ID:        1,
+		RoomID:    "1",
+		TimeRange: domain.TimeRange{Start: from, End: to},
+		Version:   2,
+	}
+
+	testCases := []struct {
+		name    string
+		idParam string
+		ifMatch string
+		input   *rescheduleReservationRequest
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(
+					gomock.Any(),
+					gomock.Eq(int64(1)),
+					gomock.Eq(defaultInput.StartTime.Time),
+					gomock.Eq(defaultInput.EndTime.Time),
+					gomock.Eq(int64(1)),
+				).Times(1).Return(defaultReservation, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+
+				var out reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newResevation(defaultReservation), out)
+			},
+		},
+		{
+			name:    "NOT OK invalid id",
+			idParam: "abc", // note
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK missing If-Match",
+			idParam: "1",
+			ifMatch: "", // note
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK invalid If-Match",
+			idParam: "1",
+			ifMatch: `W/"1"`, // note
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK nil body",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   nil, // note
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation validation failed",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation not found",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ErrReservationNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation version mismatch",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ErrReservationVersionMismatch) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation room inactive",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ErrRoomInactive) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation reservation conflict",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, &domain.ReservationConflictError{}) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation lock timeout",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, internal.ErrLockTimeout) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+				assert.Equal(t, "1", r.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:    "NOT OK error from RescheduleReservation unexpected",
+			idParam: "1",
+			ifMatch: `"1"`,
+			input:   &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			body := &bytes.Buffer{}
+			if tc.input != nil {
+				b, err := json.Marshal(tc.input)
+				assert.NoError(t, err)
+				body = bytes.NewBuffer(b)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/v1/reservations/%s", tc.idParam), body)
+			r.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_RoomAvailability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Hour).UTC()
+	to := from.Add(4 * time.Hour)
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultQuery := fmt.Sprintf("?from=%s&to=%s&duration=30m",
+		url.QueryEscape(from.Format(ReservationTimeLayout)),
+		url.QueryEscape(to.Format(ReservationTimeLayout)),
+	)
+
+	defaultFree := []domain.TimeRange{
+		{Start: from, End: from.Add(1 * time.Hour)},
+		{Start: from.Add(2 * time.Hour), End: to},
+	}
+
+	testCases := []struct {
+		name        string
+		queryParams string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			queryParams: defaultQuery,
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(
+					gomock.Any(),
+					gomock.Eq("1"),
+					gomock.Eq(from),
+					gomock.Eq(to),
+					gomock.Eq(30*time.Minute),
+				).Times(1).Return(defaultFree, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out []timeRange
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, []timeRange{newTimeRange(defaultFree[0]), newTimeRange(defaultFree[1])}, out)
+			},
+		},
+		{
+			name: "OK without duration",
+			queryParams: fmt.Sprintf("?from=%s&to=%s",
+				url.QueryEscape(from.Format(ReservationTimeLayout)),
+				url.QueryEscape(to.Format(ReservationTimeLayout)),
+			),
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(time.Duration(0))).
+					Times(1).Return(nil, nil) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, "[]", r.Body.String())
+			},
+		},
+		{
+			name:        "NOT OK missing window",
+			queryParams: "", // note
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name: "NOT OK invalid duration",
+			queryParams: fmt.Sprintf("?from=%s&to=%s&duration=hour", // note
+				url.QueryEscape(from.Format(ReservationTimeLayout)),
+				url.QueryEscape(to.Format(ReservationTimeLayout)),
+			),
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from RoomAvailability validation failed",
+			queryParams: defaultQuery,
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from RoomAvailability room not found",
+			queryParams: defaultQuery,
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from RoomAvailability unexpected",
+			queryParams: defaultQuery,
+			buildStubs: func() {
+				service.EXPECT().RoomAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/rooms/1/availability%s", tc.queryParams), nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_CancelReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	testCases := []struct {
+		name    string
+		idParam string
+		ifMatch string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Eq(int64(1)), gomock.Eq(int64(1))).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, r.Code)
+				assert.Empty(t, r.Body)
+			},
+		},
+		{
+			name:    "NOT OK invalid id",
+			idParam: "abc", // note
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK missing If-Match",
+			idParam: "1",
+			ifMatch: "", // note
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK invalid If-Match",
+			idParam: "1",
+			ifMatch: "*", // note
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from CancelReservation validation failed",
+			idParam: "0",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Eq(int64(0)), gomock.Eq(int64(1))).Times(1).Return(internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from CancelReservation not found",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Eq(int64(1)), gomock.Eq(int64(1))).Times(1).Return(domain.ErrReservationNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from CancelReservation version mismatch",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Eq(int64(1)), gomock.Eq(int64(1))).Times(1).Return(domain.ErrReservationVersionMismatch) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from CancelReservation lock timeout",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Eq(int64(1)), gomock.Eq(int64(1))).Times(1).Return(internal.ErrLockTimeout) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+				assert.Equal(t, "1", r.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:    "NOT OK error from CancelReservation unexpected",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().CancelReservation(gomock.Any(), gomock.Eq(int64(1)), gomock.Eq(int64(1))).Times(1).Return(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/reservations/%s", tc.idParam), nil)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_ConfirmReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+
+	confirmed := domain.Reservation{
+		ID:        1,
+		RoomID:    "1",
+		TimeRange: domain.TimeRange{Start: from, End: from.Add(time.Hour)},
+		Version:   2,
+		Status:    domain.StatusConfirmed,
+	}
+
+	testCases := []struct {
+		name    string
+		idParam string
+		ifMatch string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Eq(int64(1)), gomock.Eq(int64(1))).Times(1).Return(confirmed, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, `"2"`, r.Header().Get("ETag"))
+
+				var out reservation
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newResevation(confirmed), out)
+				assert.Nil(t, out.ExpiresAt)
+			},
+		},
+		{
+			name:    "NOT OK missing If-Match",
+			idParam: "1",
+			ifMatch: "", // note
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK invalid id",
+			idParam: "abc", // note
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from ConfirmReservation not found",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(domain.Reservation{}, domain.ErrReservationNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from ConfirmReservation version mismatch",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(domain.Reservation{}, domain.ErrReservationVersionMismatch) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from ConfirmReservation not held",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(domain.Reservation{}, domain.ErrReservationNotHeld) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from ConfirmReservation hold expired",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(domain.Reservation{}, domain.ErrHoldExpired) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusGone, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from ConfirmReservation lock timeout",
+			idParam: "1",
+			ifMatch: `"1"`,
+			buildStubs: func() {
+				service.EXPECT().ConfirmReservation(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(domain.Reservation{}, internal.ErrLockTimeout) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/reservations/%s/confirm", tc.idParam), nil)
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_GetWaitlistEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+
+	promoted := domain.WaitlistEntry{
+		ID:            3,
+		RoomID:        "1",
+		TimeRange:     domain.TimeRange{Start: from, End: from.Add(time.Hour)},
+		Status:        domain.WaitlistPromoted,
+		ReservationID: 7,
+		CreatedAt:     from,
+	}
+
+	testCases := []struct {
+		name    string
+		idParam string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			idParam: "3",
+			buildStubs: func() {
+				service.EXPECT().GetWaitlistEntry(gomock.Any(), gomock.Eq(int64(3))).Times(1).Return(promoted, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out waitlistEntry
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newWaitlistEntry(promoted), out)
+				assert.Equal(t, int64(7), out.ReservationID)
+			},
+		},
+		{
+			name:    "NOT OK invalid id",
+			idParam: "abc", // note
+			buildStubs: func() {
+				service.EXPECT().GetWaitlistEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from GetWaitlistEntry not found",
+			idParam: "3",
+			buildStubs: func() {
+				service.EXPECT().GetWaitlistEntry(gomock.Any(), gomock.Any()).Times(1).Return(domain.WaitlistEntry{}, domain.ErrWaitlistEntryNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+	}
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.buildStubs()
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/reservations/%s", tc.roomIDParam), nil)
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/waitlist/%s", tc.idParam), nil)
 
 			router.ServeHTTP(w, r)
 			tc.checkResult(t, w)
@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key string, requestHash string) (*domain.IdempotentResponse, error)
+	Complete(ctx context.Context, key string, response domain.IdempotentResponse) error
+	Release(ctx context.Context, key string) error
+}
+
+// idempotent повтор запроса с тем же Idempotency-Key и телом получает сохраненный ответ
+// вместо повторного выполнения, тот же ключ с другим телом - 422.
+// Запросы без заголовка проходят как обычно
+func idempotent(service IdempotencyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			defer cancel()
+
+			replay, err := service.Begin(ctx, key, requestHash(r, body))
+			if errors.Is(err, internal.ErrValidationFailed) {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			} else if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+				writeError(w, http.StatusUnprocessableEntity, err)
+				return
+			} else if errors.Is(err, domain.ErrIdempotencyKeyInProgress) {
+				writeError(w, http.StatusConflict, err)
+				return
+			} else if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			if replay != nil {
+				writeReplay(w, *replay)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				// при панике обработчика ключ тоже освобождается, иначе повторы получали бы 409,
+				// саму панику дальше обрабатывает Recoverer
+				p := recover()
+
+				// ответ уже ушел клиенту, сохраняем его даже если клиент отключился
+				saveCtx, saveCancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+				defer saveCancel()
+
+				// 5xx не запоминаем, чтобы клиент мог повторить запрос с тем же ключом
+				var err error
+				if p != nil || rec.status >= http.StatusInternalServerError {
+					err = service.Release(saveCtx, key)
+				} else {
+					err = service.Complete(saveCtx, key, domain.IdempotentResponse{
+						StatusCode: rec.status,
+						Location:   rec.Header().Get("Location"),
+						Body:       rec.body.Bytes(),
+					})
+				}
+				if err != nil {
+					log.Println("save idempotent response:", err.Error())
+				}
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// requestHash ключ привязан к конкретному запросу: метод, путь и тело
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeReplay(w http.ResponseWriter, response domain.IdempotentResponse) {
+	if response.Location != "" {
+		w.Header().Set("Location", response.Location)
+	}
+	if len(response.Body) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(response.StatusCode)
+	_, _ = w.Write(response.Body)
+}
+
+// responseRecorder пишет ответ клиенту и запоминает его для повторов
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
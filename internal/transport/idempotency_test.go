@@ -0,0 +1,234 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_transport "github.com/ynuraddi/test-kami/internal/transport/mock"
+)
+
+func Test_IdempotentCreateReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	idempotency := mock_transport.NewMockIdempotencyService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), idempotency)
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	unexpectedError := errors.New("unexpecte error")
+
+	input := createReservationRequest{
+		RoomID:    "1",
+		StartTime: ReservationTime{from},
+		EndTime:   ReservationTime{to},
+	}
+	body, err := json.Marshal(input)
+	assert.NoError(t, err)
+
+	key := "4f1c2a7e-9b7d-4e5a-8f3b-2d6c1e0a9b7d"
+	hash := requestHash(httptest.NewRequest(http.MethodPost, "/api/v1/reservations", nil), body)
+
+	defaultReservation := domain.Reservation{
+		ID:        1,
+		RoomID:    domain.RoomID(input.RoomID),
+		TimeRange: domain.TimeRange{Start: from, End: to},
+	}
+	defaultBody, err := json.Marshal(newResevation(defaultReservation))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		key  string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK without key",
+			key:  "", // note
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(defaultReservation, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+			},
+		},
+		{
+			name: "OK first request stores response",
+			key:  key,
+			buildStubs: func() {
+				c1 := idempotency.EXPECT().Begin(gomock.Any(), gomock.Eq(key), gomock.Eq(hash)).Times(1).Return(nil, nil)
+				c2 := service.EXPECT().ReserveRoom(gomock.Any(), gomock.Eq(input.RoomID), gomock.Eq(from), gomock.Eq(to), gomock.Eq(domain.ReservationDetails{})).
+					Times(1).Return(defaultReservation, nil)
+				c3 := idempotency.EXPECT().Complete(gomock.Any(), gomock.Eq(key), gomock.Eq(domain.IdempotentResponse{
+					StatusCode: http.StatusCreated,
+					Location:   "/api/v1/reservations/id/1",
+					Body:       defaultBody,
+				})).Times(1).Return(nil)
+
+				c2.After(c1)
+				c3.After(c2)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, defaultBody, r.Body.Bytes())
+				assert.Empty(t, r.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "OK replay returns original response",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Eq(key), gomock.Eq(hash)).Times(1).Return(&domain.IdempotentResponse{
+					StatusCode: http.StatusCreated,
+					Location:   "/api/v1/reservations/id/1",
+					Body:       defaultBody,
+				}, nil)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				idempotency.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, defaultBody, r.Body.Bytes())
+				assert.Equal(t, "/api/v1/reservations/id/1", r.Header().Get("Location"))
+				assert.Equal(t, "application/json", r.Header().Get("Content-Type"))
+				assert.Equal(t, "true", r.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "OK conflict is stored too",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, domain.ReservationConflictError{}) // note
+				idempotency.EXPECT().Complete(gomock.Any(), gomock.Eq(key), gomock.Any()).DoAndReturn(
+					func(_ any, _ string, response domain.IdempotentResponse) error {
+						assert.Equal(t, http.StatusConflict, response.StatusCode)
+						return nil
+					},
+				).Times(1)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+			},
+		},
+		{
+			name: "OK server error releases key",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Reservation{}, internal.ErrLockTimeout) // note
+				idempotency.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				idempotency.EXPECT().Release(gomock.Any(), gomock.Eq(key)).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusServiceUnavailable, r.Code)
+			},
+		},
+		{
+			name: "OK panic in handler releases key",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Do(func(context.Context, string, time.Time, time.Time, domain.ReservationDetails) { panic("boom") }) // note
+				idempotency.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				idempotency.EXPECT().Release(gomock.Any(), gomock.Eq(key)).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+		{
+			name: "NOT OK key reused with another body",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, domain.ErrIdempotencyKeyReused) // note
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, r.Code)
+			},
+		},
+		{
+			name: "NOT OK first request in progress",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, domain.ErrIdempotencyKeyInProgress) // note
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+			},
+		},
+		{
+			name: "NOT OK invalid key",
+			key:  "some key",
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, internal.ErrValidationFailed) // note
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name: "NOT OK error from Begin unexpected",
+			key:  key,
+			buildStubs: func() {
+				idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, unexpectedError) // note
+				service.EXPECT().ReserveRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/reservations", bytes.NewBuffer(body))
+			r.Header.Set("Content-Type", "application/json")
+			if tc.key != "" {
+				r.Header.Set(idempotencyKeyHeader, tc.key)
+			}
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_RequestHash(t *testing.T) {
+	post := func(path string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, path, nil)
+	}
+
+	body := []byte(`{"room_id":"1"}`)
+
+	assert.Equal(t, requestHash(post("/api/v1/reservations"), body), requestHash(post("/api/v1/reservations"), body))
+	assert.Len(t, requestHash(post("/api/v1/reservations"), body), 64)
+
+	assert.NotEqual(t, requestHash(post("/api/v1/reservations"), body), requestHash(post("/api/v1/reservations"), []byte(`{"room_id":"2"}`)))
+	assert.NotEqual(t, requestHash(post("/api/v1/reservations"), body), requestHash(post("/api/v1/reservations/batch"), body))
+}
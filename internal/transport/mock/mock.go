@@ -36,31 +36,182 @@ func (m *MockReservationService) EXPECT() *MockReservationServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelReservation mocks base method.
+func (m *MockReservationService) CancelReservation(ctx context.Context, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelReservation", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelReservation indicates an expected call of CancelReservation.
+func (mr *MockReservationServiceMockRecorder) CancelReservation(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*MockReservationService)(nil).CancelReservation), ctx, id, version)
+}
+
+// ConfirmReservation mocks base method.
+func (m *MockReservationService) ConfirmReservation(ctx context.Context, id, version int64) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReservation", ctx, id, version)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmReservation indicates an expected call of ConfirmReservation.
+func (mr *MockReservationServiceMockRecorder) ConfirmReservation(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReservation", reflect.TypeOf((*MockReservationService)(nil).ConfirmReservation), ctx, id, version)
+}
+
+// GetReservation mocks base method.
+func (m *MockReservationService) GetReservation(ctx context.Context, id int64) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", ctx, id)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockReservationServiceMockRecorder) GetReservation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockReservationService)(nil).GetReservation), ctx, id)
+}
+
+// GetWaitlistEntry mocks base method.
+func (m *MockReservationService) GetWaitlistEntry(ctx context.Context, id int64) (domain.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlistEntry", ctx, id)
+	ret0, _ := ret[0].(domain.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlistEntry indicates an expected call of GetWaitlistEntry.
+func (mr *MockReservationServiceMockRecorder) GetWaitlistEntry(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlistEntry", reflect.TypeOf((*MockReservationService)(nil).GetWaitlistEntry), ctx, id)
+}
+
+// HoldRoom mocks base method.
+func (m *MockReservationService) HoldRoom(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails, ttl time.Duration) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldRoom", ctx, roomID, from, to, details, ttl)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldRoom indicates an expected call of HoldRoom.
+func (mr *MockReservationServiceMockRecorder) HoldRoom(ctx, roomID, from, to, details, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldRoom", reflect.TypeOf((*MockReservationService)(nil).HoldRoom), ctx, roomID, from, to, details, ttl)
+}
+
 // ListByRoom mocks base method.
-func (m *MockReservationService) ListByRoom(ctx context.Context, roomID string) ([]domain.Reservation, error) {
+func (m *MockReservationService) ListByRoom(ctx context.Context, roomID string, filter domain.ReservationFilter) ([]domain.Reservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByRoom", ctx, roomID)
+	ret := m.ctrl.Call(m, "ListByRoom", ctx, roomID, filter)
 	ret0, _ := ret[0].([]domain.Reservation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByRoom indicates an expected call of ListByRoom.
-func (mr *MockReservationServiceMockRecorder) ListByRoom(ctx, roomID interface{}) *gomock.Call {
+func (mr *MockReservationServiceMockRecorder) ListByRoom(ctx, roomID, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRoom", reflect.TypeOf((*MockReservationService)(nil).ListByRoom), ctx, roomID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRoom", reflect.TypeOf((*MockReservationService)(nil).ListByRoom), ctx, roomID, filter)
+}
+
+// RescheduleReservation mocks base method.
+func (m *MockReservationService) RescheduleReservation(ctx context.Context, id int64, from, to time.Time, version int64) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleReservation", ctx, id, from, to, version)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RescheduleReservation indicates an expected call of RescheduleReservation.
+func (mr *MockReservationServiceMockRecorder) RescheduleReservation(ctx, id, from, to, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleReservation", reflect.TypeOf((*MockReservationService)(nil).RescheduleReservation), ctx, id, from, to, version)
+}
+
+// ReserveBatch mocks base method.
+func (m *MockReservationService) ReserveBatch(ctx context.Context, requests []domain.BookingRequest) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveBatch", ctx, requests)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveBatch indicates an expected call of ReserveBatch.
+func (mr *MockReservationServiceMockRecorder) ReserveBatch(ctx, requests interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveBatch", reflect.TypeOf((*MockReservationService)(nil).ReserveBatch), ctx, requests)
+}
+
+// ReserveRecurring mocks base method.
+func (m *MockReservationService) ReserveRecurring(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails, rule string) (domain.ReservationSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveRecurring", ctx, roomID, from, to, details, rule)
+	ret0, _ := ret[0].(domain.ReservationSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveRecurring indicates an expected call of ReserveRecurring.
+func (mr *MockReservationServiceMockRecorder) ReserveRecurring(ctx, roomID, from, to, details, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveRecurring", reflect.TypeOf((*MockReservationService)(nil).ReserveRecurring), ctx, roomID, from, to, details, rule)
 }
 
 // ReserveRoom mocks base method.
-func (m *MockReservationService) ReserveRoom(ctx context.Context, roomID string, from, to time.Time) error {
+func (m *MockReservationService) ReserveRoom(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails) (domain.Reservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReserveRoom", ctx, roomID, from, to)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ReserveRoom", ctx, roomID, from, to, details)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ReserveRoom indicates an expected call of ReserveRoom.
-func (mr *MockReservationServiceMockRecorder) ReserveRoom(ctx, roomID, from, to interface{}) *gomock.Call {
+func (mr *MockReservationServiceMockRecorder) ReserveRoom(ctx, roomID, from, to, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveRoom", reflect.TypeOf((*MockReservationService)(nil).ReserveRoom), ctx, roomID, from, to, details)
+}
+
+// RoomAvailability mocks base method.
+func (m *MockReservationService) RoomAvailability(ctx context.Context, roomID string, from, to time.Time, duration time.Duration) ([]domain.TimeRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoomAvailability", ctx, roomID, from, to, duration)
+	ret0, _ := ret[0].([]domain.TimeRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RoomAvailability indicates an expected call of RoomAvailability.
+func (mr *MockReservationServiceMockRecorder) RoomAvailability(ctx, roomID, from, to, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoomAvailability", reflect.TypeOf((*MockReservationService)(nil).RoomAvailability), ctx, roomID, from, to, duration)
+}
+
+// WaitlistRoom mocks base method.
+func (m *MockReservationService) WaitlistRoom(ctx context.Context, roomID string, from, to time.Time, details domain.ReservationDetails) (domain.Reservation, domain.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitlistRoom", ctx, roomID, from, to, details)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(domain.WaitlistEntry)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// WaitlistRoom indicates an expected call of WaitlistRoom.
+func (mr *MockReservationServiceMockRecorder) WaitlistRoom(ctx, roomID, from, to, details interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveRoom", reflect.TypeOf((*MockReservationService)(nil).ReserveRoom), ctx, roomID, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitlistRoom", reflect.TypeOf((*MockReservationService)(nil).WaitlistRoom), ctx, roomID, from, to, details)
 }
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/transport/room.go
+
+// Package mock_transport is a generated GoMock package.
+package mock_transport
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/ynuraddi/test-kami/internal/domain"
+)
+
+// MockRoomService is a mock of RoomService interface.
+type MockRoomService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomServiceMockRecorder
+}
+
+// MockRoomServiceMockRecorder is the mock recorder for MockRoomService.
+type MockRoomServiceMockRecorder struct {
+	mock *MockRoomService
+}
+
+// NewMockRoomService creates a new mock instance.
+func NewMockRoomService(ctrl *gomock.Controller) *MockRoomService {
+	mock := &MockRoomService{ctrl: ctrl}
+	mock.recorder = &MockRoomServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomService) EXPECT() *MockRoomServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateRoom mocks base method.
+func (m *MockRoomService) CreateRoom(ctx context.Context, roomID, name string, capacity int, location string, bufferBefore, bufferAfter time.Duration) (domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRoom", ctx, roomID, name, capacity, location, bufferBefore, bufferAfter)
+	ret0, _ := ret[0].(domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRoom indicates an expected call of CreateRoom.
+func (mr *MockRoomServiceMockRecorder) CreateRoom(ctx, roomID, name, capacity, location, bufferBefore, bufferAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoom", reflect.TypeOf((*MockRoomService)(nil).CreateRoom), ctx, roomID, name, capacity, location, bufferBefore, bufferAfter)
+}
+
+// DeleteRoom mocks base method.
+func (m *MockRoomService) DeleteRoom(ctx context.Context, roomID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRoom", ctx, roomID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRoom indicates an expected call of DeleteRoom.
+func (mr *MockRoomServiceMockRecorder) DeleteRoom(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoom", reflect.TypeOf((*MockRoomService)(nil).DeleteRoom), ctx, roomID)
+}
+
+// FindAvailableRooms mocks base method.
+func (m *MockRoomService) FindAvailableRooms(ctx context.Context, from, to time.Time, minCapacity int) ([]domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAvailableRooms", ctx, from, to, minCapacity)
+	ret0, _ := ret[0].([]domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAvailableRooms indicates an expected call of FindAvailableRooms.
+func (mr *MockRoomServiceMockRecorder) FindAvailableRooms(ctx, from, to, minCapacity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAvailableRooms", reflect.TypeOf((*MockRoomService)(nil).FindAvailableRooms), ctx, from, to, minCapacity)
+}
+
+// GetRoom mocks base method.
+func (m *MockRoomService) GetRoom(ctx context.Context, roomID string) (domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoom", ctx, roomID)
+	ret0, _ := ret[0].(domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoom indicates an expected call of GetRoom.
+func (mr *MockRoomServiceMockRecorder) GetRoom(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoom", reflect.TypeOf((*MockRoomService)(nil).GetRoom), ctx, roomID)
+}
+
+// ListRooms mocks base method.
+func (m *MockRoomService) ListRooms(ctx context.Context) ([]domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRooms", ctx)
+	ret0, _ := ret[0].([]domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRooms indicates an expected call of ListRooms.
+func (mr *MockRoomServiceMockRecorder) ListRooms(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRooms", reflect.TypeOf((*MockRoomService)(nil).ListRooms), ctx)
+}
+
+// UpdateRoom mocks base method.
+func (m *MockRoomService) UpdateRoom(ctx context.Context, roomID, name string, capacity int, location string, active bool, bufferBefore, bufferAfter time.Duration) (domain.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoom", ctx, roomID, name, capacity, location, active, bufferBefore, bufferAfter)
+	ret0, _ := ret[0].(domain.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRoom indicates an expected call of UpdateRoom.
+func (mr *MockRoomServiceMockRecorder) UpdateRoom(ctx, roomID, name, capacity, location, active, bufferBefore, bufferAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoom", reflect.TypeOf((*MockRoomService)(nil).UpdateRoom), ctx, roomID, name, capacity, location, active, bufferBefore, bufferAfter)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/transport/schedule.go
+
+// Package mock_transport is a generated GoMock package.
+package mock_transport
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/ynuraddi/test-kami/internal/domain"
+)
+
+// MockScheduleService is a mock of ScheduleService interface.
+type MockScheduleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleServiceMockRecorder
+}
+
+// MockScheduleServiceMockRecorder is the mock recorder for MockScheduleService.
+type MockScheduleServiceMockRecorder struct {
+	mock *MockScheduleService
+}
+
+// NewMockScheduleService creates a new mock instance.
+func NewMockScheduleService(ctrl *gomock.Controller) *MockScheduleService {
+	mock := &MockScheduleService{ctrl: ctrl}
+	mock.recorder = &MockScheduleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleService) EXPECT() *MockScheduleServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateBlackout mocks base method.
+func (m *MockScheduleService) CreateBlackout(ctx context.Context, roomID string, from, to time.Time, reason string) (domain.Blackout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlackout", ctx, roomID, from, to, reason)
+	ret0, _ := ret[0].(domain.Blackout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlackout indicates an expected call of CreateBlackout.
+func (mr *MockScheduleServiceMockRecorder) CreateBlackout(ctx, roomID, from, to, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlackout", reflect.TypeOf((*MockScheduleService)(nil).CreateBlackout), ctx, roomID, from, to, reason)
+}
+
+// DeleteBlackout mocks base method.
+func (m *MockScheduleService) DeleteBlackout(ctx context.Context, roomID string, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlackout", ctx, roomID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlackout indicates an expected call of DeleteBlackout.
+func (mr *MockScheduleServiceMockRecorder) DeleteBlackout(ctx, roomID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlackout", reflect.TypeOf((*MockScheduleService)(nil).DeleteBlackout), ctx, roomID, id)
+}
+
+// GetSchedule mocks base method.
+func (m *MockScheduleService) GetSchedule(ctx context.Context, roomID string) (domain.RoomSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", ctx, roomID)
+	ret0, _ := ret[0].(domain.RoomSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockScheduleServiceMockRecorder) GetSchedule(ctx, roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockScheduleService)(nil).GetSchedule), ctx, roomID)
+}
+
+// ListBlackouts mocks base method.
+func (m *MockScheduleService) ListBlackouts(ctx context.Context, roomID string, from, to time.Time) ([]domain.Blackout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlackouts", ctx, roomID, from, to)
+	ret0, _ := ret[0].([]domain.Blackout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlackouts indicates an expected call of ListBlackouts.
+func (mr *MockScheduleServiceMockRecorder) ListBlackouts(ctx, roomID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlackouts", reflect.TypeOf((*MockScheduleService)(nil).ListBlackouts), ctx, roomID, from, to)
+}
+
+// SetSchedule mocks base method.
+func (m *MockScheduleService) SetSchedule(ctx context.Context, roomID, timeZone string, hours []domain.OpeningHours) (domain.RoomSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSchedule", ctx, roomID, timeZone, hours)
+	ret0, _ := ret[0].(domain.RoomSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSchedule indicates an expected call of SetSchedule.
+func (mr *MockScheduleServiceMockRecorder) SetSchedule(ctx, roomID, timeZone, hours interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSchedule", reflect.TypeOf((*MockScheduleService)(nil).SetSchedule), ctx, roomID, timeZone, hours)
+}
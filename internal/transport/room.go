@@ -0,0 +1,210 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type RoomService interface {
+	CreateRoom(ctx context.Context, roomID, name string, capacity int, location string, bufferBefore, bufferAfter time.Duration) (domain.Room, error)
+	GetRoom(ctx context.Context, roomID string) (domain.Room, error)
+	ListRooms(ctx context.Context) ([]domain.Room, error)
+	FindAvailableRooms(ctx context.Context, from, to time.Time, minCapacity int) ([]domain.Room, error)
+	UpdateRoom(ctx context.Context, roomID, name string, capacity int, location string, active bool, bufferBefore, bufferAfter time.Duration) (domain.Room, error)
+	DeleteRoom(ctx context.Context, roomID string) error
+}
+
+type roomController struct {
+	service RoomService
+}
+
+func NewRoomController(service RoomService) *roomController {
+	return &roomController{
+		service: service,
+	}
+}
+
+type createRoomRequest struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	Capacity            int    `json:"capacity"`
+	Location            string `json:"location"`
+	BufferBeforeMinutes int    `json:"buffer_before_minutes"`
+	BufferAfterMinutes  int    `json:"buffer_after_minutes"`
+}
+
+func (h roomController) CreateRoom(w http.ResponseWriter, r *http.Request) {
+	var req createRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	room, err := h.service.CreateRoom(ctx, req.ID, req.Name, req.Capacity, req.Location,
+		minutes(req.BufferBeforeMinutes), minutes(req.BufferAfterMinutes))
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomAlreadyExists) {
+		writeError(w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeCreated(w, fmt.Sprintf("/api/v1/rooms/%s", room.ID), newRoom(room))
+}
+
+func (h roomController) GetRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	room, err := h.service.GetRoom(ctx, roomID)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusOK, newRoom(room))
+}
+
+func (h roomController) ListRooms(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	rooms, err := h.service.ListRooms(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]room, 0, len(rooms))
+	for _, r := range rooms {
+		out = append(out, newRoom(r))
+	}
+
+	write(w, http.StatusOK, out)
+}
+
+func (h roomController) AvailableRooms(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	from, err := time.Parse(ReservationTimeLayout, params.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid from: %w", err))
+		return
+	}
+	to, err := time.Parse(ReservationTimeLayout, params.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid to: %w", err))
+		return
+	}
+	var minCapacity int
+	if c := params.Get("min_capacity"); c != "" {
+		if minCapacity, err = strconv.Atoi(c); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid min_capacity: %w", err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	rooms, err := h.service.FindAvailableRooms(ctx, from, to, minCapacity)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]room, 0, len(rooms))
+	for _, r := range rooms {
+		out = append(out, newRoom(r))
+	}
+
+	write(w, http.StatusOK, out)
+}
+
+type updateRoomRequest struct {
+	Name                string `json:"name"`
+	Capacity            int    `json:"capacity"`
+	Location            string `json:"location"`
+	Active              bool   `json:"active"`
+	BufferBeforeMinutes int    `json:"buffer_before_minutes"`
+	BufferAfterMinutes  int    `json:"buffer_after_minutes"`
+}
+
+func (h roomController) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	var req updateRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	room, err := h.service.UpdateRoom(ctx, roomID, req.Name, req.Capacity, req.Location, req.Active,
+		minutes(req.BufferBeforeMinutes), minutes(req.BufferAfterMinutes))
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusOK, newRoom(room))
+}
+
+func (h roomController) DeleteRoom(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	err := h.service.DeleteRoom(ctx, roomID)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomInUse) {
+		writeError(w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusNoContent, nil)
+}
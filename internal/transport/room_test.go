@@ -0,0 +1,586 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_transport "github.com/ynuraddi/test-kami/internal/transport/mock"
+)
+
+func Test_CreateRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockRoomService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), service, mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultInput := createRoomRequest{
+		ID:                  "conf-a",
+		Name:                "Conference A",
+		Capacity:            10,
+		Location:            "2nd floor",
+		BufferBeforeMinutes: 5,
+		BufferAfterMinutes:  15,
+	}
+
+	defaultRoom := domain.Room{
+		ID:       domain.RoomID(defaultInput.ID),
+		Name:     defaultInput.Name,
+		Capacity: defaultInput.Capacity,
+		Location: defaultInput.Location,
+		Active:   true,
+		Buffer:   domain.RoomBuffer{Before: 5 * time.Minute, After: 15 * time.Minute},
+	}
+
+	testCases := []struct {
+		name  string
+		input *createRoomRequest
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateRoom(
+					gomock.Any(),
+					gomock.Eq(defaultInput.ID),
+					gomock.Eq(defaultInput.Name),
+					gomock.Eq(defaultInput.Capacity),
+					gomock.Eq(defaultInput.Location),
+					gomock.Eq(5*time.Minute),
+					gomock.Eq(15*time.Minute),
+				).Times(1).Return(defaultRoom, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "/api/v1/rooms/conf-a", r.Header().Get("Location"))
+
+				var out room
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newRoom(defaultRoom), out)
+				assert.Equal(t, 5, out.BufferBeforeMinutes)
+				assert.Equal(t, 15, out.BufferAfterMinutes)
+			},
+		},
+		{
+			name:  "NOT OK nil body",
+			input: nil, // note
+			buildStubs: func() {
+				service.EXPECT().CreateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from CreateRoom validation failed",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Room{}, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from CreateRoom already exists",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Room{}, domain.ErrRoomAlreadyExists) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from CreateRoom unexpected",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Room{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			body := &bytes.Buffer{}
+			if tc.input != nil {
+				b, err := json.Marshal(tc.input)
+				assert.NoError(t, err)
+				body = bytes.NewBuffer(b)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/rooms", body)
+			r.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_GetRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockRoomService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), service, mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultRoom := domain.Room{
+		ID:       "conf-a",
+		Name:     "Conference A",
+		Capacity: 10,
+		Active:   true,
+	}
+
+	testCases := []struct {
+		name string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				service.EXPECT().GetRoom(gomock.Any(), gomock.Eq(string(defaultRoom.ID))).Times(1).Return(defaultRoom, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out room
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newRoom(defaultRoom), out)
+			},
+		},
+		{
+			name: "NOT OK error from GetRoom not found",
+			buildStubs: func() {
+				service.EXPECT().GetRoom(gomock.Any(), gomock.Any()).Times(1).Return(domain.Room{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name: "NOT OK error from GetRoom unexpected",
+			buildStubs: func() {
+				service.EXPECT().GetRoom(gomock.Any(), gomock.Any()).Times(1).Return(domain.Room{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/rooms/%s", defaultRoom.ID), nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_ListRooms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockRoomService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), service, mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultRooms := []domain.Room{
+		{ID: "conf-a", Name: "Conference A", Capacity: 10, Active: true},
+		{ID: "conf-b", Name: "Conference B", Capacity: 4, Active: false},
+	}
+
+	testCases := []struct {
+		name string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				service.EXPECT().ListRooms(gomock.Any()).Times(1).Return(defaultRooms, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out []room
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, []room{newRoom(defaultRooms[0]), newRoom(defaultRooms[1])}, out)
+			},
+		},
+		{
+			name: "OK no data",
+			buildStubs: func() {
+				service.EXPECT().ListRooms(gomock.Any()).Times(1).Return(nil, nil) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, "[]", r.Body.String())
+			},
+		},
+		{
+			name: "NOT OK error from ListRooms unexpected",
+			buildStubs: func() {
+				service.EXPECT().ListRooms(gomock.Any()).Times(1).Return(nil, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/rooms", nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_AvailableRooms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockRoomService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), service, mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	window := fmt.Sprintf("?from=%s&to=%s",
+		url.QueryEscape(from.Format(ReservationTimeLayout)),
+		url.QueryEscape(to.Format(ReservationTimeLayout)),
+	)
+
+	defaultRooms := []domain.Room{
+		{ID: "conf-a", Name: "Conference A", Capacity: 10, Active: true},
+	}
+
+	testCases := []struct {
+		name        string
+		queryParams string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			queryParams: window + "&min_capacity=8",
+			buildStubs: func() {
+				service.EXPECT().FindAvailableRooms(gomock.Any(), gomock.Eq(from), gomock.Eq(to), gomock.Eq(8)).
+					Times(1).Return(defaultRooms, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out []room
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, []room{newRoom(defaultRooms[0])}, out)
+			},
+		},
+		{
+			name:        "OK without min capacity",
+			queryParams: window,
+			buildStubs: func() {
+				service.EXPECT().FindAvailableRooms(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(0)).
+					Times(1).Return(nil, nil) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+				assert.Equal(t, "[]", r.Body.String())
+			},
+		},
+		{
+			name:        "NOT OK missing window",
+			queryParams: "", // note
+			buildStubs: func() {
+				service.EXPECT().FindAvailableRooms(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK invalid min capacity",
+			queryParams: window + "&min_capacity=many", // note
+			buildStubs: func() {
+				service.EXPECT().FindAvailableRooms(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from FindAvailableRooms validation failed",
+			queryParams: window,
+			buildStubs: func() {
+				service.EXPECT().FindAvailableRooms(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:        "NOT OK error from FindAvailableRooms unexpected",
+			queryParams: window,
+			buildStubs: func() {
+				service.EXPECT().FindAvailableRooms(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(nil, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/availability"+tc.queryParams, nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_UpdateRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockRoomService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), service, mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultRoomID := "conf-a"
+
+	defaultInput := updateRoomRequest{
+		Name:               "Conference A",
+		Capacity:           12,
+		Active:             false,
+		BufferAfterMinutes: 10,
+	}
+
+	defaultRoom := domain.Room{
+		ID:       domain.RoomID(defaultRoomID),
+		Name:     defaultInput.Name,
+		Capacity: defaultInput.Capacity,
+		Active:   defaultInput.Active,
+		Buffer:   domain.RoomBuffer{After: 10 * time.Minute},
+	}
+
+	testCases := []struct {
+		name  string
+		input *updateRoomRequest
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().UpdateRoom(
+					gomock.Any(),
+					gomock.Eq(defaultRoomID),
+					gomock.Eq(defaultInput.Name),
+					gomock.Eq(defaultInput.Capacity),
+					gomock.Eq(defaultInput.Location),
+					gomock.Eq(defaultInput.Active),
+					gomock.Eq(time.Duration(0)),
+					gomock.Eq(10*time.Minute),
+				).Times(1).Return(defaultRoom, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out room
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newRoom(defaultRoom), out)
+			},
+		},
+		{
+			name:  "NOT OK nil body",
+			input: nil, // note
+			buildStubs: func() {
+				service.EXPECT().UpdateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from UpdateRoom validation failed",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().UpdateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Room{}, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from UpdateRoom not found",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().UpdateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Room{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from UpdateRoom unexpected",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().UpdateRoom(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Room{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			body := &bytes.Buffer{}
+			if tc.input != nil {
+				b, err := json.Marshal(tc.input)
+				assert.NoError(t, err)
+				body = bytes.NewBuffer(b)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/rooms/%s", defaultRoomID), body)
+			r.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_DeleteRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockRoomService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), service, mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultRoomID := "conf-a"
+
+	testCases := []struct {
+		name string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				service.EXPECT().DeleteRoom(gomock.Any(), gomock.Eq(defaultRoomID)).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+		{
+			name: "NOT OK error from DeleteRoom not found",
+			buildStubs: func() {
+				service.EXPECT().DeleteRoom(gomock.Any(), gomock.Any()).Times(1).Return(domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name: "NOT OK error from DeleteRoom room in use",
+			buildStubs: func() {
+				service.EXPECT().DeleteRoom(gomock.Any(), gomock.Any()).Times(1).Return(domain.ErrRoomInUse) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, r.Code)
+			},
+		},
+		{
+			name: "NOT OK error from DeleteRoom unexpected",
+			buildStubs: func() {
+				service.EXPECT().DeleteRoom(gomock.Any(), gomock.Any()).Times(1).Return(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/rooms/%s", defaultRoomID), nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
@@ -5,23 +5,49 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func NewRouter(service ReservationService) *chi.Mux {
+func NewRouter(service ReservationService, rooms RoomService, schedules ScheduleService, idempotency IdempotencyService) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Logger)
 
-	r.Mount("/api/v1", v1(service))
+	r.Mount("/api/v1", v1(service, rooms, schedules, idempotency))
 
 	return r
 }
 
-func v1(service ReservationService) *chi.Mux {
+func v1(service ReservationService, rooms RoomService, schedules ScheduleService, idempotency IdempotencyService) *chi.Mux {
 	r := chi.NewRouter()
 
 	reservation := NewReservationController(service)
+	room := NewRoomController(rooms)
+	schedule := NewScheduleController(schedules)
 
-	r.Post("/reservations", reservation.CreateReservation)
+	r.With(idempotent(idempotency)).Post("/reservations", reservation.CreateReservation)
+	r.With(idempotent(idempotency)).Post("/reservations/batch", reservation.CreateReservationBatch)
 	r.Get("/reservations/{room_id}", reservation.ListByRoom)
+	r.Patch("/reservations/{id}", reservation.RescheduleReservation)
+	r.Post("/reservations/{id}/confirm", reservation.ConfirmReservation)
+	r.Delete("/reservations/{id}", reservation.CancelReservation)
+	// отдельное бронирование по ID, сюда ведет Location; /reservations/{room_id} остается списком комнаты
+	r.Get("/reservations/id/{id}", reservation.GetReservation)
+	r.Patch("/reservations/id/{id}", reservation.RescheduleReservation)
+	r.Post("/reservations/id/{id}/confirm", reservation.ConfirmReservation)
+	r.Delete("/reservations/id/{id}", reservation.CancelReservation)
+	r.Get("/waitlist/{id}", reservation.GetWaitlistEntry)
+
+	r.Post("/rooms", room.CreateRoom)
+	r.Get("/rooms", room.ListRooms)
+	r.Get("/rooms/{room_id}", room.GetRoom)
+	r.Put("/rooms/{room_id}", room.UpdateRoom)
+	r.Delete("/rooms/{room_id}", room.DeleteRoom)
+	r.Get("/rooms/{room_id}/availability", reservation.RoomAvailability)
+	r.Get("/rooms/{room_id}/schedule", schedule.GetSchedule)
+	r.Put("/rooms/{room_id}/schedule", schedule.SetSchedule)
+	r.Get("/rooms/{room_id}/blackouts", schedule.ListBlackouts)
+	r.Post("/rooms/{room_id}/blackouts", schedule.CreateBlackout)
+	r.Delete("/rooms/{room_id}/blackouts/{id}", schedule.DeleteBlackout)
+
+	r.Get("/availability", room.AvailableRooms)
 
 	return r
 }
@@ -0,0 +1,83 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_transport "github.com/ynuraddi/test-kami/internal/transport/mock"
+)
+
+// Location из ответа на создание ведет на то же бронирование, которое затем читается и меняется
+func Test_ReservationLocationRoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockReservationService(ctrl)
+	router := NewRouter(service, mock_transport.NewMockRoomService(ctrl), mock_transport.NewMockScheduleService(ctrl), mock_transport.NewMockIdempotencyService(ctrl))
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	created := domain.Reservation{
+		ID:        42,
+		RoomID:    "conf-a",
+		TimeRange: domain.TimeRange{Start: from, End: to},
+		Version:   1,
+	}
+	rescheduled := created
+	rescheduled.TimeRange = domain.TimeRange{Start: to, End: to.Add(1 * time.Hour)}
+	rescheduled.Version = 2
+
+	service.EXPECT().ReserveRoom(gomock.Any(), gomock.Eq("conf-a"), gomock.Eq(from), gomock.Eq(to), gomock.Any()).
+		Times(1).Return(created, nil)
+	service.EXPECT().GetReservation(gomock.Any(), gomock.Eq(created.ID)).
+		Times(1).Return(created, nil)
+	service.EXPECT().ListByRoom(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	service.EXPECT().RescheduleReservation(gomock.Any(), gomock.Eq(created.ID), gomock.Eq(rescheduled.TimeRange.Start), gomock.Eq(rescheduled.TimeRange.End), gomock.Eq(created.Version)).
+		Times(1).Return(rescheduled, nil)
+
+	b, err := json.Marshal(createReservationRequest{
+		RoomID:    "conf-a",
+		StartTime: ReservationTime{from},
+		EndTime:   ReservationTime{to},
+	})
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/reservations", bytes.NewBuffer(b)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	location := w.Header().Get("Location")
+	assert.Equal(t, "/api/v1/reservations/id/42", location)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, location, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var got reservation
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, newResevation(created), got)
+
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	b, err = json.Marshal(rescheduleReservationRequest{
+		StartTime: ReservationTime{rescheduled.TimeRange.Start},
+		EndTime:   ReservationTime{rescheduled.TimeRange.End},
+	})
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPatch, location, bytes.NewBuffer(b))
+	r.Header.Set("If-Match", etag)
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+}
@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+)
+
+type ScheduleService interface {
+	GetSchedule(ctx context.Context, roomID string) (domain.RoomSchedule, error)
+	SetSchedule(ctx context.Context, roomID, timeZone string, hours []domain.OpeningHours) (domain.RoomSchedule, error)
+	ListBlackouts(ctx context.Context, roomID string, from, to time.Time) ([]domain.Blackout, error)
+	CreateBlackout(ctx context.Context, roomID string, from, to time.Time, reason string) (domain.Blackout, error)
+	DeleteBlackout(ctx context.Context, roomID string, id int64) error
+}
+
+type scheduleController struct {
+	service ScheduleService
+}
+
+func NewScheduleController(service ScheduleService) *scheduleController {
+	return &scheduleController{
+		service: service,
+	}
+}
+
+func (h scheduleController) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	schedule, err := h.service.GetSchedule(ctx, roomID)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusOK, newRoomSchedule(schedule))
+}
+
+type setScheduleRequest struct {
+	TimeZone string         `json:"time_zone"`
+	Hours    []openingHours `json:"hours"`
+}
+
+func (h scheduleController) SetSchedule(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	var req setScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	hours, err := parseOpeningHours(req.Hours)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	schedule, err := h.service.SetSchedule(ctx, roomID, req.TimeZone, hours)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusOK, newRoomSchedule(schedule))
+}
+
+func (h scheduleController) ListBlackouts(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+	params := r.URL.Query()
+
+	from, err := time.Parse(ReservationTimeLayout, params.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid from: %w", err))
+		return
+	}
+	to, err := time.Parse(ReservationTimeLayout, params.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid to: %w", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	blackouts, err := h.service.ListBlackouts(ctx, roomID, from, to)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]blackout, 0, len(blackouts))
+	for _, b := range blackouts {
+		out = append(out, newBlackout(b))
+	}
+
+	write(w, http.StatusOK, out)
+}
+
+type createBlackoutRequest struct {
+	StartTime ReservationTime `json:"start_time"`
+	EndTime   ReservationTime `json:"end_time"`
+	Reason    string          `json:"reason"`
+}
+
+func (h scheduleController) CreateBlackout(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+
+	var req createBlackoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	created, err := h.service.CreateBlackout(ctx, roomID, req.StartTime.Time, req.EndTime.Time, req.Reason)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeCreated(w, fmt.Sprintf("/api/v1/rooms/%s/blackouts/%d", created.RoomID, created.ID), newBlackout(created))
+}
+
+func (h scheduleController) DeleteBlackout(w http.ResponseWriter, r *http.Request) {
+	roomID := chi.URLParam(r, "room_id")
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.service.DeleteBlackout(ctx, roomID, id)
+	if errors.Is(err, internal.ErrValidationFailed) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if errors.Is(err, domain.ErrBlackoutNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	write(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,396 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ynuraddi/test-kami/internal"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	mock_transport "github.com/ynuraddi/test-kami/internal/transport/mock"
+)
+
+func Test_GetSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockScheduleService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), mock_transport.NewMockRoomService(ctrl), service, mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultSchedule, err := domain.NewRoomSchedule("conf-a", "Europe/Berlin", []domain.OpeningHours{
+		{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour},
+	})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func() {
+				service.EXPECT().GetSchedule(gomock.Any(), gomock.Eq("conf-a")).Times(1).Return(defaultSchedule, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out roomSchedule
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, roomSchedule{
+					RoomID:   "conf-a",
+					TimeZone: "Europe/Berlin",
+					Hours:    []openingHours{{Weekday: "monday", Open: "09:00", Close: "18:00"}},
+				}, out)
+			},
+		},
+		{
+			name: "NOT OK error from GetSchedule not found",
+			buildStubs: func() {
+				service.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Times(1).Return(domain.RoomSchedule{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name: "NOT OK error from GetSchedule unexpected",
+			buildStubs: func() {
+				service.EXPECT().GetSchedule(gomock.Any(), gomock.Any()).Times(1).Return(domain.RoomSchedule{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/rooms/conf-a/schedule", nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_SetSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockScheduleService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), mock_transport.NewMockRoomService(ctrl), service, mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	defaultInput := setScheduleRequest{
+		TimeZone: "Europe/Berlin",
+		Hours:    []openingHours{{Weekday: "monday", Open: "09:00", Close: "18:00"}},
+	}
+
+	hours := []domain.OpeningHours{{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour}}
+
+	defaultSchedule, err := domain.NewRoomSchedule("conf-a", defaultInput.TimeZone, hours)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		input *setScheduleRequest
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().SetSchedule(gomock.Any(), gomock.Eq("conf-a"), gomock.Eq(defaultInput.TimeZone), gomock.Eq(hours)).
+					Times(1).Return(defaultSchedule, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, r.Code)
+
+				var out roomSchedule
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newRoomSchedule(defaultSchedule), out)
+			},
+		},
+		{
+			name:  "NOT OK nil body",
+			input: nil, // note
+			buildStubs: func() {
+				service.EXPECT().SetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name: "NOT OK invalid opening hours",
+			input: &setScheduleRequest{
+				TimeZone: defaultInput.TimeZone,
+				Hours:    []openingHours{{Weekday: "monday", Open: "9am", Close: "18:00"}}, // note
+			},
+			buildStubs: func() {
+				service.EXPECT().SetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from SetSchedule validation failed",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().SetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.RoomSchedule{}, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from SetSchedule not found",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().SetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.RoomSchedule{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from SetSchedule unexpected",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().SetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.RoomSchedule{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			body := &bytes.Buffer{}
+			if tc.input != nil {
+				b, err := json.Marshal(tc.input)
+				assert.NoError(t, err)
+				body = bytes.NewBuffer(b)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/api/v1/rooms/conf-a/schedule", body)
+			r.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_CreateBlackout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockScheduleService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), mock_transport.NewMockRoomService(ctrl), service, mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	defaultInput := createBlackoutRequest{
+		StartTime: ReservationTime{from},
+		EndTime:   ReservationTime{to},
+		Reason:    "maintenance",
+	}
+
+	defaultBlackout := domain.Blackout{
+		ID:        1,
+		RoomID:    "conf-a",
+		TimeRange: domain.TimeRange{Start: from, End: to},
+		Reason:    defaultInput.Reason,
+	}
+
+	testCases := []struct {
+		name  string
+		input *createBlackoutRequest
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateBlackout(gomock.Any(), gomock.Eq("conf-a"), gomock.Eq(from), gomock.Eq(to), gomock.Eq(defaultInput.Reason)).
+					Times(1).Return(defaultBlackout, nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, r.Code)
+				assert.Equal(t, "/api/v1/rooms/conf-a/blackouts/1", r.Header().Get("Location"))
+
+				var out blackout
+				err := json.NewDecoder(r.Body).Decode(&out)
+				assert.NoError(t, err)
+				assert.Equal(t, newBlackout(defaultBlackout), out)
+			},
+		},
+		{
+			name:  "NOT OK nil body",
+			input: nil, // note
+			buildStubs: func() {
+				service.EXPECT().CreateBlackout(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from CreateBlackout validation failed",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateBlackout(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Blackout{}, internal.ErrValidationFailed) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from CreateBlackout room not found",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateBlackout(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Blackout{}, domain.ErrRoomNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:  "NOT OK error from CreateBlackout unexpected",
+			input: &defaultInput,
+			buildStubs: func() {
+				service.EXPECT().CreateBlackout(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).Return(domain.Blackout{}, unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			body := &bytes.Buffer{}
+			if tc.input != nil {
+				b, err := json.Marshal(tc.input)
+				assert.NoError(t, err)
+				body = bytes.NewBuffer(b)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/rooms/conf-a/blackouts", body)
+			r.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
+
+func Test_DeleteBlackout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_transport.NewMockScheduleService(ctrl)
+	router := NewRouter(mock_transport.NewMockReservationService(ctrl), mock_transport.NewMockRoomService(ctrl), service, mock_transport.NewMockIdempotencyService(ctrl))
+
+	unexpectedError := errors.New("unexpecte error")
+
+	testCases := []struct {
+		name    string
+		idParam string
+
+		buildStubs  func()
+		checkResult func(t *testing.T, r *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			idParam: "1",
+			buildStubs: func() {
+				service.EXPECT().DeleteBlackout(gomock.Any(), gomock.Eq("conf-a"), gomock.Eq(int64(1))).Times(1).Return(nil)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK invalid id",
+			idParam: "one", // note
+			buildStubs: func() {
+				service.EXPECT().DeleteBlackout(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from DeleteBlackout not found",
+			idParam: "1",
+			buildStubs: func() {
+				service.EXPECT().DeleteBlackout(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(domain.ErrBlackoutNotFound) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, r.Code)
+			},
+		},
+		{
+			name:    "NOT OK error from DeleteBlackout unexpected",
+			idParam: "1",
+			buildStubs: func() {
+				service.EXPECT().DeleteBlackout(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(unexpectedError) // note
+			},
+			checkResult: func(t *testing.T, r *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, r.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/rooms/conf-a/blackouts/%s", tc.idParam), nil)
+
+			router.ServeHTTP(w, r)
+			tc.checkResult(t, w)
+		})
+	}
+}
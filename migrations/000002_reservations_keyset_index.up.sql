@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_reservations_room_start_id ON reservations (room_id, start_time, id);
@@ -0,0 +1,2 @@
+-- откат возможен только если все room_id числовые
+ALTER TABLE reservations ALTER COLUMN room_id TYPE int USING room_id::int;
@@ -0,0 +1 @@
+ALTER TABLE reservations ALTER COLUMN room_id TYPE varchar(72) USING room_id::varchar(72);
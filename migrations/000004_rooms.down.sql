@@ -0,0 +1,3 @@
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS fk_reservations_room;
+
+DROP TABLE IF EXISTS "rooms";
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "rooms" (
+    id varchar(72) primary key,
+    name varchar(128) not null,
+    capacity int not null check (capacity > 0),
+    location varchar(256) not null default '',
+    active boolean not null default true
+);
+
+-- комнаты, на которые уже есть бронирования, заводятся с минимальными данными
+INSERT INTO rooms (id, name, capacity)
+SELECT DISTINCT room_id, room_id, 1 FROM reservations
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE reservations
+    ADD CONSTRAINT fk_reservations_room FOREIGN KEY (room_id) REFERENCES rooms (id);
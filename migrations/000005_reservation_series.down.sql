@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_reservations_series;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS reservation_series;
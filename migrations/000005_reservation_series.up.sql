@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "reservation_series" (
+    id serial primary key,
+    room_id varchar(72) not null references rooms (id),
+    rrule varchar(256) not null
+);
+
+ALTER TABLE reservations
+    ADD COLUMN series_id int references reservation_series (id) on delete cascade;
+
+CREATE INDEX idx_reservations_series ON reservations (series_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+    key varchar(255) primary key,
+    request_hash varchar(64) not null,
+    status_code int,
+    location varchar(256),
+    body bytea,
+    created_at timestamp not null default (now() at time zone 'utc')
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE reservations
+    ADD COLUMN version bigint not null default 1;
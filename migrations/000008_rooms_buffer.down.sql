@@ -0,0 +1,3 @@
+ALTER TABLE rooms
+    DROP COLUMN IF EXISTS buffer_before_seconds,
+    DROP COLUMN IF EXISTS buffer_after_seconds;
@@ -0,0 +1,3 @@
+ALTER TABLE rooms
+    ADD COLUMN buffer_before_seconds int not null default 0 check (buffer_before_seconds >= 0),
+    ADD COLUMN buffer_after_seconds int not null default 0 check (buffer_after_seconds >= 0);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "room_blackouts";
+
+DROP TABLE IF EXISTS "room_opening_hours";
+
+ALTER TABLE rooms DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,23 @@
+ALTER TABLE rooms
+    ADD COLUMN time_zone varchar(64) not null default 'UTC';
+
+-- время в секундах от полуночи в часовом поясе комнаты
+CREATE TABLE IF NOT EXISTS "room_opening_hours" (
+    room_id varchar(72) not null references rooms (id) on delete cascade,
+    weekday smallint not null check (weekday between 0 and 6),
+    open_seconds int not null check (open_seconds >= 0),
+    close_seconds int not null check (close_seconds <= 86400),
+    check (open_seconds < close_seconds),
+    primary key (room_id, weekday, open_seconds)
+);
+
+CREATE TABLE IF NOT EXISTS "room_blackouts" (
+    id serial primary key,
+    room_id varchar(72) not null references rooms (id) on delete cascade,
+    start_time timestamp not null,
+    end_time timestamp not null,
+    reason varchar(256) not null default '',
+    check (start_time < end_time)
+);
+
+CREATE INDEX idx_room_blackouts_room_start_end ON room_blackouts (room_id, start_time, end_time);
@@ -0,0 +1,9 @@
+-- без статуса временные брони стали бы обычными бронированиями
+DELETE FROM reservations WHERE status = 'held';
+
+DROP INDEX IF EXISTS idx_reservations_hold_expires;
+
+ALTER TABLE reservations
+    DROP CONSTRAINT IF EXISTS chk_reservations_hold_expires,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,7 @@
+ALTER TABLE reservations
+    ADD COLUMN status varchar(16) not null default 'confirmed' check (status in ('held', 'confirmed')),
+    ADD COLUMN expires_at timestamp,
+    ADD CONSTRAINT chk_reservations_hold_expires check ((status = 'held') = (expires_at is not null));
+
+-- для очистки истекших броней
+CREATE INDEX idx_reservations_hold_expires ON reservations (expires_at) WHERE status = 'held';
@@ -0,0 +1,16 @@
+-- запросы, которые конфликтовали с существующими бронированиями и ждут отмены.
+-- reservation_id без внешнего ключа: продвинутый запрос остается promoted,
+-- даже если созданное по нему бронирование потом отменят
+CREATE TABLE IF NOT EXISTS "reservation_waitlist" (
+    id serial primary key,
+    room_id varchar(72) not null references rooms (id) on delete cascade,
+    start_time timestamp not null,
+    end_time timestamp not null,
+    status varchar(16) not null default 'waiting' check (status in ('waiting', 'promoted')),
+    reservation_id int,
+    created_at timestamp not null default (now() at time zone 'utc'),
+    check (start_time < end_time),
+    check ((status = 'promoted') = (reservation_id is not null))
+);
+
+CREATE INDEX idx_reservation_waitlist_waiting ON reservation_waitlist (room_id, start_time, end_time) WHERE status = 'waiting';
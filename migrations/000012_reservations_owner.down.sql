@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_reservations_owner_end;
+
+ALTER TABLE reservation_waitlist DROP COLUMN IF EXISTS owner;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS owner;
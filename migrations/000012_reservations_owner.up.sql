@@ -0,0 +1,8 @@
+ALTER TABLE reservations
+    ADD COLUMN owner varchar(128) not null default '';
+
+ALTER TABLE reservation_waitlist
+    ADD COLUMN owner varchar(128) not null default '';
+
+-- для квоты незакончившихся бронирований на владельца
+CREATE INDEX idx_reservations_owner_end ON reservations (owner, end_time) WHERE owner <> '';
@@ -0,0 +1,9 @@
+ALTER TABLE reservation_waitlist
+    DROP COLUMN IF EXISTS attendees,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS title;
+
+ALTER TABLE reservations
+    DROP COLUMN IF EXISTS attendees,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS title;
@@ -0,0 +1,9 @@
+ALTER TABLE reservations
+    ADD COLUMN title varchar(200) not null default '',
+    ADD COLUMN description varchar(2000) not null default '',
+    ADD COLUMN attendees text[] not null default '{}';
+
+ALTER TABLE reservation_waitlist
+    ADD COLUMN title varchar(200) not null default '',
+    ADD COLUMN description varchar(2000) not null default '',
+    ADD COLUMN attendees text[] not null default '{}';
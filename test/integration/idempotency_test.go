@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynuraddi/test-kami/internal/application"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	repository "github.com/ynuraddi/test-kami/internal/infrastructure/postgres"
+)
+
+func Test_IdempotencyKeys(t *testing.T) {
+	psg := setupPostgres(t)
+
+	repo := repository.NewIdempotencyKeys(psg)
+
+	im := application.NewIdempotencyManager(repo, time.Hour, time.Hour, time.Minute)
+	defer im.Close()
+
+	response := domain.IdempotentResponse{
+		StatusCode: 201,
+		Location:   "/api/v1/reservations/1",
+		Body:       []byte(`{"id":1}`),
+	}
+
+	t.Run("replay and reuse", func(t *testing.T) {
+		replay, err := im.Begin(context.Background(), "replay", "hash")
+		require.NoError(t, err)
+		assert.Nil(t, replay)
+
+		// пока ответа нет, повтор должен подождать
+		_, err = im.Begin(context.Background(), "replay", "hash")
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInProgress)
+
+		require.NoError(t, im.Complete(context.Background(), "replay", response))
+
+		replay, err = im.Begin(context.Background(), "replay", "hash")
+		require.NoError(t, err)
+		assert.Equal(t, &response, replay)
+
+		_, err = im.Begin(context.Background(), "replay", "other")
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("released key can be claimed again", func(t *testing.T) {
+		_, err := im.Begin(context.Background(), "release", "hash")
+		require.NoError(t, err)
+		require.NoError(t, im.Release(context.Background(), "release"))
+
+		replay, err := im.Begin(context.Background(), "release", "hash")
+		assert.NoError(t, err)
+		assert.Nil(t, replay)
+	})
+
+	t.Run("abandoned key can be claimed again", func(t *testing.T) {
+		_, err := im.Begin(context.Background(), "abandoned", "hash")
+		require.NoError(t, err)
+
+		// процесс упал, не сохранив ответ и не освободив ключ
+		_, err = psg.Exec(context.Background(), `update idempotency_keys set created_at = created_at - interval '2 minutes' where key = 'abandoned'`)
+		require.NoError(t, err)
+
+		_, err = im.Begin(context.Background(), "abandoned", "other")
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+
+		replay, err := im.Begin(context.Background(), "abandoned", "hash")
+		assert.NoError(t, err)
+		assert.Nil(t, replay)
+
+		// перезанятый ключ снова в работе
+		_, err = im.Begin(context.Background(), "abandoned", "hash")
+		assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInProgress)
+	})
+
+	t.Run("key is claimed once under concurrency", func(t *testing.T) {
+		concurrentCount := 30
+
+		var (
+			wg      sync.WaitGroup
+			claimed int32
+		)
+		wg.Add(concurrentCount)
+
+		done := make(chan struct{})
+		for i := 0; i < concurrentCount; i++ {
+			go func() {
+				defer wg.Done()
+				<-done
+
+				replay, err := im.Begin(context.Background(), "concurrent", "hash")
+				if err == nil && replay == nil {
+					atomic.AddInt32(&claimed, 1)
+				}
+			}()
+		}
+
+		close(done)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), claimed)
+	})
+
+	t.Run("expired keys deleted", func(t *testing.T) {
+		_, err := im.Begin(context.Background(), "expired", "hash")
+		require.NoError(t, err)
+
+		deleted, err := repo.DeleteExpired(context.Background(), time.Now().UTC().Add(time.Minute))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+
+		replay, err := im.Begin(context.Background(), "expired", "other")
+		assert.NoError(t, err)
+		assert.Nil(t, replay)
+	})
+}
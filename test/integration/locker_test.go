@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ynuraddi/test-kami/internal/application"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	repository "github.com/ynuraddi/test-kami/internal/infrastructure/postgres"
+	"github.com/ynuraddi/test-kami/internal/transport"
+)
+
+// две реплики приложения с общей базой, у каждой свой процессный MutexManager
+// не спас бы, поэтому сериализация только через advisory lock
+func Test_ReserveRoom_TwoInstances(t *testing.T) {
+	psg := setupPostgres(t)
+
+	roomRepo := repository.NewRooms(psg)
+	services := []transport.ReservationService{
+		application.NewReservationService(repository.NewReservations(psg), roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), repository.NewTxManager(psg, repository.DefaultRetryPolicy), repository.NewAdvisoryLocker(), application.NewLogPublisher(), nil),
+		application.NewReservationService(repository.NewReservations(psg), roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), repository.NewTxManager(psg, repository.DefaultRetryPolicy), repository.NewAdvisoryLocker(), application.NewLogPublisher(), nil),
+	}
+
+	roomID := "shared"
+	_, err := application.NewRoomService(roomRepo, repository.NewSchedules(psg)).CreateRoom(context.Background(), roomID, roomID, 10, "", 0, 0)
+	require.NoError(t, err)
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	concurrentReservesCount := 30
+
+	var (
+		wg      sync.WaitGroup
+		success int32
+		fail    int32
+	)
+	wg.Add(concurrentReservesCount)
+
+	done := make(chan struct{})
+
+	for i := 0; i < concurrentReservesCount; i++ {
+		go func(i int) {
+			defer wg.Done()
+			<-done
+
+			// запросы поочередно попадают на разные реплики
+			if _, err := services[i%2].ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{}); err != nil {
+				assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+				atomic.AddInt32(&fail, 1)
+			} else {
+				atomic.AddInt32(&success, 1)
+			}
+		}(i)
+	}
+
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), success)
+	assert.Equal(t, int32(concurrentReservesCount-1), fail)
+
+	for _, service := range services {
+		reservations, err := service.ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, reservations, 1)
+	}
+}
+
+// пакеты с общими комнатами в разном порядке не должны зависнуть друг на друге
+func Test_ReserveBatch_TwoInstances(t *testing.T) {
+	psg := setupPostgres(t)
+
+	roomRepo := repository.NewRooms(psg)
+	services := []transport.ReservationService{
+		application.NewReservationService(repository.NewReservations(psg), roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), repository.NewTxManager(psg, repository.DefaultRetryPolicy), repository.NewAdvisoryLocker(), application.NewLogPublisher(), nil),
+		application.NewReservationService(repository.NewReservations(psg), roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), repository.NewTxManager(psg, repository.DefaultRetryPolicy), repository.NewAdvisoryLocker(), application.NewLogPublisher(), nil),
+	}
+
+	roomIDs := []string{"bundle-a", "bundle-b", "bundle-c"}
+	for _, roomID := range roomIDs {
+		_, err := application.NewRoomService(roomRepo, repository.NewSchedules(psg)).CreateRoom(context.Background(), roomID, roomID, 10, "", 0, 0)
+		require.NoError(t, err)
+	}
+
+	from := time.Now().Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+
+	concurrentReservesCount := 30
+
+	var (
+		wg      sync.WaitGroup
+		success int32
+		fail    int32
+	)
+	wg.Add(concurrentReservesCount)
+
+	done := make(chan struct{})
+
+	for i := 0; i < concurrentReservesCount; i++ {
+		go func(i int) {
+			defer wg.Done()
+			<-done
+
+			// четные запросы перечисляют комнаты в обратном порядке
+			bundle := make([]domain.BookingRequest, 0, len(roomIDs))
+			for j := range roomIDs {
+				roomID := roomIDs[j]
+				if i%2 == 0 {
+					roomID = roomIDs[len(roomIDs)-1-j]
+				}
+				bundle = append(bundle, domain.BookingRequest{RoomID: roomID, From: from, To: to})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if _, err := services[i%2].ReserveBatch(ctx, bundle); err != nil {
+				assert.ErrorIs(t, err, &domain.BatchConflictError{})
+				atomic.AddInt32(&fail, 1)
+			} else {
+				atomic.AddInt32(&success, 1)
+			}
+		}(i)
+	}
+
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), success)
+	assert.Equal(t, int32(concurrentReservesCount-1), fail)
+
+	for _, roomID := range roomIDs {
+		reservations, err := services[0].ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, reservations, 1)
+	}
+}
+
+// квота владельца общая для всех комнат, а блокировки комнат разные,
+// поэтому параллельные бронирования разных комнат сериализуются по владельцу
+func Test_OwnerQuota_ConcurrentRooms(t *testing.T) {
+	psg := setupPostgres(t)
+
+	roomRepo := repository.NewRooms(psg)
+	policy := domain.OwnerQuota(1)
+	services := []transport.ReservationService{
+		application.NewReservationService(repository.NewReservations(psg), roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), repository.NewTxManager(psg, repository.DefaultRetryPolicy), application.NewMutexManager(time.Minute, time.Minute), application.NewLogPublisher(), policy),
+		application.NewReservationService(repository.NewReservations(psg), roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), repository.NewTxManager(psg, repository.DefaultRetryPolicy), repository.NewAdvisoryLocker(), application.NewLogPublisher(), policy),
+	}
+
+	roomIDs := []string{"quota-a", "quota-b"}
+	for _, roomID := range roomIDs {
+		_, err := application.NewRoomService(roomRepo, repository.NewSchedules(psg)).CreateRoom(context.Background(), roomID, roomID, 10, "", 0, 0)
+		require.NoError(t, err)
+	}
+
+	from := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	to := from.Add(1 * time.Hour)
+	owner := domain.ReservationDetails{Owner: "quota-owner"}
+
+	concurrentReservesCount := 20
+
+	var (
+		wg      sync.WaitGroup
+		success int32
+		fail    int32
+	)
+	wg.Add(concurrentReservesCount)
+
+	done := make(chan struct{})
+
+	for i := 0; i < concurrentReservesCount; i++ {
+		go func(i int) {
+			defer wg.Done()
+			<-done
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			// каждый запрос в свою комнату, конфликта по времени между комнатами нет
+			if _, err := services[i%2].ReserveRoom(ctx, roomIDs[i%2], from.Add(time.Duration(i)*time.Hour), to.Add(time.Duration(i)*time.Hour), owner); err != nil {
+				var violation domain.PolicyViolationError
+				assert.ErrorAs(t, err, &violation)
+				atomic.AddInt32(&fail, 1)
+			} else {
+				atomic.AddInt32(&success, 1)
+			}
+		}(i)
+	}
+
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), success)
+	assert.Equal(t, int32(concurrentReservesCount-1), fail)
+
+	var total int
+	for _, roomID := range roomIDs {
+		reservations, err := services[0].ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
+		assert.NoError(t, err)
+		total += len(reservations)
+	}
+	assert.Equal(t, 1, total)
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/ynuraddi/test-kami/internal/domain"
+	repository "github.com/ynuraddi/test-kami/internal/infrastructure/postgres"
+)
+
+// сравнение поиска пересечений: выгрузка всей истории комнаты и CrossWith в коде
+// против запроса FindOverlapping по индексу
+func Benchmark_Overlap(b *testing.B) {
+	psg := setupPostgres(b)
+
+	ctx := context.Background()
+
+	roomID := domain.RoomID("1000")
+	reservationsCount := 100_000
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := repository.NewRooms(psg).Create(ctx, domain.Room{ID: roomID, Name: "bench", Capacity: 1, Active: true})
+	require.NoError(b, err)
+
+	// часовые бронирования подряд
+	_, err = psg.Exec(ctx, `insert into reservations(room_id, start_time, end_time)
+	select $1, $2::timestamp + make_interval(hours => g), $2::timestamp + make_interval(hours => g + 1)
+	from generate_series(0, $3 - 1) g`, roomID, start, reservationsCount)
+	require.NoError(b, err)
+
+	_, err = psg.Exec(ctx, "analyze reservations")
+	require.NoError(b, err)
+
+	repo := repository.NewReservations(psg)
+
+	// полчаса посередине истории пересекаются ровно с одним бронированием
+	middle := start.Add(time.Duration(reservationsCount/2) * time.Hour)
+	tr, err := domain.NewTimeRange(middle.Add(15*time.Minute), middle.Add(45*time.Minute))
+	require.NoError(b, err)
+
+	b.Run("ListByRoom and CrossWith", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reservations, err := repo.ListByRoom(ctx, domain.ReservationQuery{RoomID: roomID})
+			require.NoError(b, err)
+
+			conflicts := 0
+			for _, r := range reservations {
+				if r.TimeRange.CrossWith(tr) {
+					conflicts++
+				}
+			}
+			require.Equal(b, 1, conflicts)
+		}
+	})
+
+	b.Run("FindOverlapping", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reservations, err := repo.FindOverlapping(ctx, roomID, tr)
+			require.NoError(b, err)
+			require.Len(b, reservations, 1)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package integration
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"sync"
 	"sync/atomic"
@@ -10,42 +9,27 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/ynuraddi/test-kami/internal/application"
+	"github.com/ynuraddi/test-kami/internal/domain"
 	repository "github.com/ynuraddi/test-kami/internal/infrastructure/postgres"
-	"github.com/ynuraddi/test-kami/pkg/postgres"
-	"github.com/ynuraddi/test-kami/test/container"
 )
 
 func Test_ReserveRoom_Concurrent(t *testing.T) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	dbContainer, err := container.SetupPostgresContainer(ctx)
-	assert.NoError(t, err)
-
-	t.Cleanup(func() {
-		dbContainer.Terminate(context.Background())
-	})
-
-	dbEndpoint, err := dbContainer.Endpoint(ctx, "")
-	assert.NoError(t, err)
-
-	dsn := fmt.Sprintf("postgresql://user:1234@%s/test?sslmode=disable", dbEndpoint)
-
-	psg, err := postgres.NewPool(ctx, dsn)
-	assert.NoError(t, err)
-
-	t.Cleanup(func() {
-		psg.Close()
-	})
-
-	err = postgres.Migrate("file://../../migrations", dsn)
-	assert.NoError(t, err)
+	psg := setupPostgres(t)
 
 	repo := repository.NewReservations(psg)
-	txM := repository.NewTxManager(psg)
+	roomRepo := repository.NewRooms(psg)
+	txM := repository.NewTxManager(psg, repository.DefaultRetryPolicy)
+
+	service := application.NewReservationService(repo, roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), txM,
+		application.NewMutexManager(time.Minute, time.Minute), application.NewLogPublisher(), nil)
+	roomService := application.NewRoomService(roomRepo, repository.NewSchedules(psg))
 
-	service := application.NewReservationService(repo, txM)
+	for _, roomID := range []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "100", "101", "102", "103", "conf-a"} {
+		_, err := roomService.CreateRoom(context.Background(), roomID, "room "+roomID, 10, "", 0, 0)
+		require.NoError(t, err)
+	}
 
 	now := time.Now().Truncate(time.Second).UTC()
 
@@ -68,7 +52,7 @@ func Test_ReserveRoom_Concurrent(t *testing.T) {
 
 		for i := 0; i < concurrentReservesCount; i++ {
 			go func() {
-				if err := service.ReserveRoom(context.Background(), roomID, from, to); err != nil {
+				if _, err := service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{}); err != nil {
 					atomic.AddInt32(&fail, 1)
 				} else {
 					atomic.AddInt32(&success, 1)
@@ -87,7 +71,7 @@ func Test_ReserveRoom_Concurrent(t *testing.T) {
 		assert.Equal(t, int32(1), success)
 		assert.Equal(t, int32(29), fail)
 
-		reservations, err := service.ListByRoom(context.Background(), roomID)
+		reservations, err := service.ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(reservations))
 	})
@@ -123,7 +107,7 @@ func Test_ReserveRoom_Concurrent(t *testing.T) {
 						from := now.Add(time.Duration(t) * time.Minute)
 						to := from.Add(1 * time.Minute)
 
-						if err := service.ReserveRoom(context.Background(), roomId, from, to); err != nil {
+						if _, err := service.ReserveRoom(context.Background(), roomId, from, to, domain.ReservationDetails{}); err != nil {
 							atomic.AddInt32(&fail, 1)
 						} else {
 							atomic.AddInt32(&success, 1)
@@ -144,4 +128,430 @@ func Test_ReserveRoom_Concurrent(t *testing.T) {
 		assert.Equal(t, int32(len(rooms)*60), success)
 		assert.Equal(t, int32(len(rooms)*60*9), fail)
 	})
+	t.Run("cancel releases slot", func(t *testing.T) {
+		roomID := "100"
+		from := now
+		to := from.Add(1 * time.Hour)
+
+		reservation, err := service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoomID(roomID), reservation.RoomID)
+
+		err = service.CancelReservation(context.Background(), reservation.ID, reservation.Version)
+		assert.NoError(t, err)
+
+		err = service.CancelReservation(context.Background(), reservation.ID, reservation.Version)
+		assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+
+		_, err = service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{})
+		assert.NoError(t, err)
+	})
+	t.Run("stale version rejected", func(t *testing.T) {
+		roomID := "103"
+		from := now
+		to := from.Add(1 * time.Hour)
+
+		reservation, err := service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), reservation.Version)
+
+		moved, err := service.RescheduleReservation(context.Background(), reservation.ID, to, to.Add(1*time.Hour), reservation.Version)
+		require.NoError(t, err)
+		assert.Equal(t, reservation.Version+1, moved.Version)
+
+		// второй клиент со старой версией не перезаписывает перенос
+		_, err = service.RescheduleReservation(context.Background(), reservation.ID, from, to, reservation.Version)
+		assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+
+		err = service.CancelReservation(context.Background(), reservation.ID, reservation.Version)
+		assert.ErrorIs(t, err, domain.ErrReservationVersionMismatch)
+
+		err = service.CancelReservation(context.Background(), moved.ID, moved.Version)
+		assert.NoError(t, err)
+	})
+	t.Run("reschedule keeps original on conflict", func(t *testing.T) {
+		roomID := "101"
+		from := now
+		to := from.Add(1 * time.Hour)
+
+		moved, err := service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{})
+		assert.NoError(t, err)
+		_, err = service.ReserveRoom(context.Background(), roomID, to, to.Add(1*time.Hour), domain.ReservationDetails{})
+		assert.NoError(t, err)
+
+		// пересечение только со своим старым временем допустимо
+		moved, err = service.RescheduleReservation(context.Background(), moved.ID, from.Add(-30*time.Minute), to.Add(-30*time.Minute), moved.Version)
+		assert.NoError(t, err)
+
+		_, err = service.RescheduleReservation(context.Background(), moved.ID, from, to.Add(30*time.Minute), moved.Version)
+		assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+
+		reservations, err := service.ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
+		assert.NoError(t, err)
+		for _, r := range reservations {
+			if r.ID == moved.ID {
+				assert.Equal(t, from.Add(-30*time.Minute), r.TimeRange.Start)
+				assert.Equal(t, to.Add(-30*time.Minute), r.TimeRange.End)
+			}
+		}
+	})
+	t.Run("non-numeric room id round-trip", func(t *testing.T) {
+		roomID := "conf-a"
+		from := now
+		to := from.Add(1 * time.Hour)
+
+		created, err := service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoomID(roomID), created.RoomID)
+
+		_, err = service.ReserveRoom(context.Background(), roomID, from, to, domain.ReservationDetails{})
+		assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+
+		reservations, err := service.ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Reservation{created}, reservations)
+	})
+	t.Run("recurring series all or nothing", func(t *testing.T) {
+		roomID := "102"
+		from := now
+		to := from.Add(1 * time.Hour)
+
+		series, err := service.ReserveRecurring(context.Background(), roomID, from, to, domain.ReservationDetails{Title: "weekly sync"}, "FREQ=WEEKLY;COUNT=3")
+		require.NoError(t, err)
+		assert.Len(t, series.Reservations, 3)
+		for _, r := range series.Reservations {
+			assert.Equal(t, "weekly sync", r.Details.Title)
+		}
+
+		// вторая серия задевает только третье вхождение первой
+		_, err = service.ReserveRecurring(context.Background(), roomID, from.AddDate(0, 0, 14), to.AddDate(0, 0, 14), domain.ReservationDetails{}, "FREQ=DAILY;COUNT=2")
+		var conflict domain.SeriesConflictError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Len(t, conflict.Conflicts, 1)
+
+		reservations, err := service.ListByRoom(context.Background(), roomID, domain.ReservationFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, series.Reservations, reservations)
+	})
+	t.Run("batch all or nothing", func(t *testing.T) {
+		from := now.AddDate(0, 2, 0)
+		to := from.Add(2 * time.Hour)
+
+		for _, id := range []string{"batch-hall", "batch-overflow-a", "batch-overflow-b"} {
+			_, err := roomService.CreateRoom(context.Background(), id, id, 10, "", 0, 0)
+			require.NoError(t, err)
+		}
+		busy, err := service.ReserveRoom(context.Background(), "batch-overflow-b", from.Add(time.Hour), to, domain.ReservationDetails{})
+		require.NoError(t, err)
+
+		bundle := []domain.BookingRequest{
+			{RoomID: "batch-hall", From: from, To: to},
+			{RoomID: "batch-overflow-a", From: from, To: to},
+			{RoomID: "batch-overflow-b", From: from, To: to},
+		}
+
+		_, err = service.ReserveBatch(context.Background(), bundle)
+		var conflict domain.BatchConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Len(t, conflict.Conflicts, 1)
+		assert.Equal(t, domain.RoomID("batch-overflow-b"), conflict.Conflicts[0].RoomID)
+
+		// ни одна комната пакета не забронирована
+		for _, id := range []string{"batch-hall", "batch-overflow-a"} {
+			reservations, err := service.ListByRoom(context.Background(), id, domain.ReservationFilter{})
+			assert.NoError(t, err)
+			assert.Empty(t, reservations)
+		}
+
+		require.NoError(t, service.CancelReservation(context.Background(), busy.ID, busy.Version))
+
+		reservations, err := service.ReserveBatch(context.Background(), bundle)
+		assert.NoError(t, err)
+		assert.Len(t, reservations, 3)
+	})
+	t.Run("available rooms single query", func(t *testing.T) {
+		from := now.AddDate(1, 0, 0)
+		to := from.Add(1 * time.Hour)
+
+		for id, capacity := range map[string]int{"avail-busy": 8, "avail-free": 8, "avail-small": 2} {
+			_, err := roomService.CreateRoom(context.Background(), id, id, capacity, "", 0, 0)
+			require.NoError(t, err)
+		}
+		_, err := service.ReserveRoom(context.Background(), "avail-busy", from.Add(30*time.Minute), to.Add(30*time.Minute), domain.ReservationDetails{})
+		require.NoError(t, err)
+
+		ids := func(rooms []domain.Room) []domain.RoomID {
+			out := make([]domain.RoomID, 0, len(rooms))
+			for _, r := range rooms {
+				out = append(out, r.ID)
+			}
+			return out
+		}
+
+		rooms, err := roomService.FindAvailableRooms(context.Background(), from, to, 5)
+		assert.NoError(t, err)
+		assert.Contains(t, ids(rooms), domain.RoomID("avail-free"))
+		assert.NotContains(t, ids(rooms), domain.RoomID("avail-busy"))
+		assert.NotContains(t, ids(rooms), domain.RoomID("avail-small"))
+	})
+	t.Run("room buffer between reservations", func(t *testing.T) {
+		from := now.AddDate(1, 1, 0)
+		to := from.Add(1 * time.Hour)
+
+		_, err := roomService.CreateRoom(context.Background(), "buffered", "buffered", 4, "", 5*time.Minute, 15*time.Minute)
+		require.NoError(t, err)
+
+		_, err = service.ReserveRoom(context.Background(), "buffered", from, to, domain.ReservationDetails{})
+		require.NoError(t, err)
+
+		// встреча сразу после уборки не помещается
+		_, err = service.ReserveRoom(context.Background(), "buffered", to, to.Add(1*time.Hour), domain.ReservationDetails{})
+		var conflict domain.ReservationConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, domain.ConflictBuffer, conflict.Reason)
+
+		rooms, err := roomService.FindAvailableRooms(context.Background(), to.Add(10*time.Minute), to.Add(1*time.Hour), 1)
+		assert.NoError(t, err)
+		for _, r := range rooms {
+			assert.NotEqual(t, domain.RoomID("buffered"), r.ID)
+		}
+
+		_, err = service.ReserveRoom(context.Background(), "buffered", to.Add(20*time.Minute), to.Add(1*time.Hour), domain.ReservationDetails{})
+		assert.NoError(t, err)
+	})
+	t.Run("opening hours and blackouts", func(t *testing.T) {
+		scheduleService := application.NewScheduleService(repository.NewSchedules(psg), txM)
+
+		_, err := roomService.CreateRoom(context.Background(), "office", "office", 4, "", 0, 0)
+		require.NoError(t, err)
+
+		// понедельник через два года, 09:00-18:00 по Берлину (UTC+1 зимой)
+		monday := time.Date(now.Year()+2, time.January, 1, 0, 0, 0, 0, time.UTC)
+		for monday.Weekday() != time.Monday {
+			monday = monday.AddDate(0, 0, 1)
+		}
+		_, err = scheduleService.SetSchedule(context.Background(), "office", "Europe/Berlin", []domain.OpeningHours{
+			{Weekday: time.Monday, Open: 9 * time.Hour, Close: 18 * time.Hour},
+		})
+		require.NoError(t, err)
+
+		_, err = service.ReserveRoom(context.Background(), "office", monday.Add(7*time.Hour), monday.Add(9*time.Hour), domain.ReservationDetails{})
+		assert.ErrorIs(t, err, &domain.OutsideBookableHoursError{})
+
+		_, err = scheduleService.CreateBlackout(context.Background(), "office", monday.Add(12*time.Hour), monday.Add(13*time.Hour), "cleaning")
+		require.NoError(t, err)
+
+		_, err = service.ReserveRoom(context.Background(), "office", monday.Add(11*time.Hour), monday.Add(13*time.Hour), domain.ReservationDetails{})
+		var outside domain.OutsideBookableHoursError
+		require.ErrorAs(t, err, &outside)
+		assert.NotNil(t, outside.Blackout)
+
+		_, err = service.ReserveRoom(context.Background(), "office", monday.Add(8*time.Hour), monday.Add(12*time.Hour), domain.ReservationDetails{})
+		assert.NoError(t, err)
+
+		free, err := service.RoomAvailability(context.Background(), "office", monday, monday.Add(24*time.Hour), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.TimeRange{{Start: monday.Add(13 * time.Hour), End: monday.Add(17 * time.Hour)}}, free)
+
+		// поиск свободных комнат учитывает и часы работы, и blackout
+		offered := func(from, to time.Time) bool {
+			rooms, err := roomService.FindAvailableRooms(context.Background(), from, to, 1)
+			require.NoError(t, err)
+			for _, r := range rooms {
+				if r.ID == "office" {
+					return true
+				}
+			}
+			return false
+		}
+		assert.False(t, offered(monday.Add(6*time.Hour), monday.Add(7*time.Hour)))
+		assert.False(t, offered(monday.Add(12*time.Hour), monday.Add(13*time.Hour)))
+		assert.True(t, offered(monday.Add(13*time.Hour), monday.Add(14*time.Hour)))
+	})
+	t.Run("hold blocks slot until confirmed or expired", func(t *testing.T) {
+		from := now.AddDate(1, 2, 0)
+		to := from.Add(1 * time.Hour)
+
+		_, err := roomService.CreateRoom(context.Background(), "holdable", "holdable", 4, "", 0, 0)
+		require.NoError(t, err)
+
+		held, err := service.HoldRoom(context.Background(), "holdable", from, to, domain.ReservationDetails{}, 10*time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusHeld, held.Status)
+		require.NotNil(t, held.ExpiresAt)
+
+		_, err = service.ReserveRoom(context.Background(), "holdable", from, to, domain.ReservationDetails{})
+		assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+
+		confirmed, err := service.ConfirmReservation(context.Background(), held.ID, held.Version)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusConfirmed, confirmed.Status)
+		assert.Nil(t, confirmed.ExpiresAt)
+
+		_, err = service.ConfirmReservation(context.Background(), confirmed.ID, confirmed.Version)
+		assert.ErrorIs(t, err, domain.ErrReservationNotHeld)
+
+		// истекшая бронь не занимает слот, подтвердить ее уже нельзя
+		expiring, err := service.HoldRoom(context.Background(), "holdable", to, to.Add(1*time.Hour), domain.ReservationDetails{}, time.Second)
+		require.NoError(t, err)
+
+		// пока hold жив, запрос на тот же слот встает в лист ожидания
+		_, waiting, err := service.WaitlistRoom(context.Background(), "holdable", to, to.Add(1*time.Hour), domain.ReservationDetails{Owner: "patient"})
+		require.NoError(t, err)
+		require.Equal(t, domain.WaitlistWaiting, waiting.Status)
+
+		time.Sleep(1500 * time.Millisecond)
+
+		_, err = service.ConfirmReservation(context.Background(), expiring.ID, expiring.Version)
+		assert.ErrorIs(t, err, domain.ErrHoldExpired)
+
+		reaped, err := service.ReapExpiredHolds(context.Background())
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, reaped, int64(1))
+
+		// слот истекшего hold достался ждущему запросу
+		waiting, err = service.GetWaitlistEntry(context.Background(), waiting.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WaitlistPromoted, waiting.Status)
+
+		_, err = service.ReserveRoom(context.Background(), "holdable", to, to.Add(1*time.Hour), domain.ReservationDetails{})
+		assert.ErrorIs(t, err, &domain.ReservationConflictError{})
+	})
+	t.Run("waitlist promoted on cancel", func(t *testing.T) {
+		from := now.AddDate(1, 3, 0)
+		to := from.Add(1 * time.Hour)
+
+		_, err := roomService.CreateRoom(context.Background(), "popular", "popular", 4, "", 0, 0)
+		require.NoError(t, err)
+
+		booked, _, err := service.WaitlistRoom(context.Background(), "popular", from, to, domain.ReservationDetails{})
+		require.NoError(t, err)
+		require.NotZero(t, booked.ID)
+
+		_, first, err := service.WaitlistRoom(context.Background(), "popular", from, to, domain.ReservationDetails{})
+		require.NoError(t, err)
+		require.NotZero(t, first.ID)
+		_, second, err := service.WaitlistRoom(context.Background(), "popular", from.Add(30*time.Minute), to.Add(30*time.Minute), domain.ReservationDetails{})
+		require.NoError(t, err)
+		require.NotZero(t, second.ID)
+
+		require.NoError(t, service.CancelReservation(context.Background(), booked.ID, booked.Version))
+
+		// первый в очереди получил слот, второму он все еще мешает
+		first, err = service.GetWaitlistEntry(context.Background(), first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WaitlistPromoted, first.Status)
+
+		promoted, err := service.GetReservation(context.Background(), first.ReservationID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TimeRange{Start: from, End: to}, promoted.TimeRange)
+
+		second, err = service.GetWaitlistEntry(context.Background(), second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WaitlistWaiting, second.Status)
+	})
+	t.Run("booking policy", func(t *testing.T) {
+		limited := application.NewReservationService(repo, roomRepo, repository.NewSchedules(psg), repository.NewWaitlist(psg), txM,
+			application.NewMutexManager(time.Minute, time.Minute), application.NewLogPublisher(),
+			domain.BookingPolicies{domain.NoPastBookings{}, domain.MinDuration(15 * time.Minute), domain.OwnerQuota(1)})
+
+		from := now.AddDate(1, 4, 0)
+		to := from.Add(1 * time.Hour)
+		owner := domain.ReservationDetails{Owner: "policy-owner"}
+
+		_, err := roomService.CreateRoom(context.Background(), "strict", "strict", 4, "", 0, 0)
+		require.NoError(t, err)
+
+		first, err := limited.ReserveRoom(context.Background(), "strict", from, to, owner)
+		require.NoError(t, err)
+		assert.Equal(t, owner, first.Details)
+
+		// квота владельца общая для всех комнат
+		_, err = limited.ReserveRoom(context.Background(), "conf-a", from, to, owner)
+		var violation domain.PolicyViolationError
+		require.ErrorAs(t, err, &violation)
+		assert.Equal(t, "owner_quota", violation.Violations[0].Rule())
+
+		// перенос своего бронирования квоту не расходует
+		_, err = limited.RescheduleReservation(context.Background(), first.ID, from.Add(2*time.Hour), to.Add(2*time.Hour), first.Version)
+		require.NoError(t, err)
+
+		_, err = limited.ReserveRoom(context.Background(), "strict", now.Add(-time.Hour), now.Add(-time.Hour+5*time.Minute), domain.ReservationDetails{})
+		require.ErrorAs(t, err, &violation)
+		assert.Len(t, violation.Violations, 2)
+	})
+	t.Run("reservation details listed", func(t *testing.T) {
+		from := now.AddDate(1, 5, 0)
+		to := from.Add(1 * time.Hour)
+		details := domain.ReservationDetails{
+			Owner:       "alice",
+			Title:       "quarterly planning",
+			Description: "budget and roadmap",
+			Attendees:   []string{"bob", "carol"},
+		}
+
+		_, err := roomService.CreateRoom(context.Background(), "planning", "planning", 4, "", 0, 0)
+		require.NoError(t, err)
+
+		created, err := service.ReserveRoom(context.Background(), "planning", from, to, details)
+		require.NoError(t, err)
+		assert.Equal(t, details, created.Details)
+
+		anonymous, err := service.ReserveRoom(context.Background(), "planning", to, to.Add(time.Hour), domain.ReservationDetails{})
+		require.NoError(t, err)
+
+		reservations, err := service.ListByRoom(context.Background(), "planning", domain.ReservationFilter{})
+		require.NoError(t, err)
+		require.Len(t, reservations, 2)
+		assert.Equal(t, details, reservations[0].Details)
+		assert.Equal(t, anonymous.Details, reservations[1].Details)
+		assert.Empty(t, reservations[1].Details)
+	})
+	t.Run("capacity enforced for attendees", func(t *testing.T) {
+		from := now.AddDate(1, 6, 0)
+		to := from.Add(1 * time.Hour)
+
+		_, err := roomService.CreateRoom(context.Background(), "pair", "pair", 2, "", 0, 0)
+		require.NoError(t, err)
+
+		_, err = service.ReserveRoom(context.Background(), "pair", from, to,
+			domain.ReservationDetails{Attendees: []string{"bob", "carol", "dave"}})
+		assert.ErrorIs(t, err, &domain.CapacityExceededError{})
+
+		// ровно заполненная комната подходит
+		_, err = service.ReserveRoom(context.Background(), "pair", from, to,
+			domain.ReservationDetails{Attendees: []string{"bob", "carol"}})
+		require.NoError(t, err)
+
+		ids := func(rooms []domain.Room) []domain.RoomID {
+			out := make([]domain.RoomID, 0, len(rooms))
+			for _, r := range rooms {
+				out = append(out, r.ID)
+			}
+			return out
+		}
+
+		rooms, err := roomService.FindAvailableRooms(context.Background(), to, to.Add(time.Hour), 2)
+		require.NoError(t, err)
+		assert.Contains(t, ids(rooms), domain.RoomID("pair"))
+
+		rooms, err = roomService.FindAvailableRooms(context.Background(), to, to.Add(time.Hour), 3)
+		require.NoError(t, err)
+		assert.NotContains(t, ids(rooms), domain.RoomID("pair"))
+	})
+	t.Run("unknown or inactive room rejected", func(t *testing.T) {
+		from := now
+		to := from.Add(1 * time.Hour)
+
+		_, err := service.ReserveRoom(context.Background(), "ghost", from, to, domain.ReservationDetails{})
+		assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+
+		_, err = roomService.CreateRoom(context.Background(), "closed", "closed", 1, "", 0, 0)
+		require.NoError(t, err)
+		_, err = roomService.UpdateRoom(context.Background(), "closed", "closed", 1, "", false, 0, 0)
+		require.NoError(t, err)
+
+		_, err = service.ReserveRoom(context.Background(), "closed", from, to, domain.ReservationDetails{})
+		assert.ErrorIs(t, err, domain.ErrRoomInactive)
+	})
 }
@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/ynuraddi/test-kami/pkg/postgres"
+	"github.com/ynuraddi/test-kami/test/container"
+)
+
+// setupPostgres поднимает контейнер с postgres и накатывает миграции
+func setupPostgres(tb testing.TB) *pgxpool.Pool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dbContainer, err := container.SetupPostgresContainer(ctx)
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() {
+		dbContainer.Terminate(context.Background())
+	})
+
+	dbEndpoint, err := dbContainer.Endpoint(ctx, "")
+	require.NoError(tb, err)
+
+	dsn := fmt.Sprintf("postgresql://user:1234@%s/test?sslmode=disable", dbEndpoint)
+
+	psg, err := postgres.NewPool(ctx, dsn)
+	require.NoError(tb, err)
+
+	tb.Cleanup(func() {
+		psg.Close()
+	})
+
+	err = postgres.Migrate("file://../../migrations", dsn)
+	require.NoError(tb, err)
+
+	return psg
+}